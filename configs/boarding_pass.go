@@ -0,0 +1,22 @@
+package configs
+
+import (
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// EnvBoardingPassSecret returns the key boarding ticket codes are signed with, the JWT secret is used
+// when no separate key is set.
+func EnvBoardingPassSecret() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	secret := os.Getenv("BOARDING_PASS_SECRET")
+	if secret == "" {
+		return os.Getenv("SECRET_JWT")
+	}
+	return secret
+}
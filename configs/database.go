@@ -151,7 +151,7 @@ func TrainSeatSeeder(db *gorm.DB) error {
 
 				// Check if data already exists
 				var count int64
-				if err := db.Model(&models.TrainSeat{}).Where(&seat).Count(&count).Error; err != nil {
+				if err := db.Model(&models.TrainSeat{}).Where(&seat).Where("train_carriage_id = ?", 0).Count(&count).Error; err != nil {
 					return err
 				}
 
@@ -169,17 +169,130 @@ func TrainSeatSeeder(db *gorm.DB) error {
 	return nil
 }
 
+func TrainStationSequenceSeeder(db *gorm.DB) error {
+	var trainIDs []uint
+	if err := db.Model(&models.TrainStation{}).Where("sequence = ?", 0).Distinct().Pluck("train_id", &trainIDs).Error; err != nil {
+		return err
+	}
+
+	for _, trainID := range trainIDs {
+		var trainStations []models.TrainStation
+		if err := db.Where("train_id = ?", trainID).Order("id ASC").Find(&trainStations).Error; err != nil {
+			return err
+		}
+
+		// Backfill the stop order and the day each stop is reached for routes created before they existed
+		dayOffset, previousMinute := 0, -1
+		for i, trainStation := range trainStations {
+			if arriveTime, err := time.Parse("15:04", trainStation.ArriveTime); err == nil {
+				minute := arriveTime.Hour()*60 + arriveTime.Minute()
+				if minute < previousMinute {
+					dayOffset++
+				}
+				previousMinute = minute
+			}
+			if err := db.Model(&models.TrainStation{}).Where("id = ?", trainStation.ID).Updates(map[string]interface{}{"sequence": i + 1, "day_offset": dayOffset}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func HotelOrderRoomSeeder(db *gorm.DB) error {
+	var hotelOrders []models.HotelOrder
+	if err := db.Where("id NOT IN (?)", db.Model(&models.HotelOrderRoom{}).Select("hotel_order_id")).Find(&hotelOrders).Error; err != nil {
+		return err
+	}
+
+	// Backfill one room line per order placed before lines existed, holding the nights it already booked
+	for _, hotelOrder := range hotelOrders {
+		status := "active"
+		if hotelOrder.Status == "canceled" || hotelOrder.Status == "refund" {
+			status = hotelOrder.Status
+		}
+		hotelOrderRoom := models.HotelOrderRoom{
+			HotelOrderID:   hotelOrder.ID,
+			HotelRoomID:    hotelOrder.HotelRoomID,
+			Quantity:       1,
+			QuantityAdult:  hotelOrder.QuantityAdult,
+			QuantityInfant: hotelOrder.QuantityInfant,
+			Price:          hotelOrder.TotalAmount,
+			Status:         status,
+		}
+		if err := db.Create(&hotelOrderRoom).Error; err != nil {
+			return err
+		}
+		if err := db.Model(&models.HotelRoomBooking{}).Where("hotel_order_id = ? AND hotel_order_room_id = ?", hotelOrder.ID, 0).Update("hotel_order_room_id", hotelOrderRoom.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func HotelRoomBookingSeeder(db *gorm.DB) error {
+	var hotelOrders []models.HotelOrder
+	if err := db.Where("status IN ?", []string{"unpaid", "paid"}).
+		Where("id NOT IN (?)", db.Model(&models.HotelRoomBooking{}).Select("hotel_order_id")).
+		Find(&hotelOrders).Error; err != nil {
+		return err
+	}
+
+	// Backfill the room ledger of orders placed before it existed, one room per night of the stay
+	for _, hotelOrder := range hotelOrders {
+		var hotelOrderRoom models.HotelOrderRoom
+		if err := db.Where("hotel_order_id = ?", hotelOrder.ID).Order("id ASC").First(&hotelOrderRoom).Error; err != nil {
+			return err
+		}
+
+		var hotelRoomBookings []models.HotelRoomBooking
+		for night := hotelOrder.DateStart; night.Before(hotelOrder.DateEnd); night = night.AddDate(0, 0, 1) {
+			hotelRoomBookings = append(hotelRoomBookings, models.HotelRoomBooking{
+				HotelOrderID:     hotelOrder.ID,
+				HotelOrderRoomID: hotelOrderRoom.ID,
+				HotelRoomID:      hotelOrder.HotelRoomID,
+				Date:             night,
+				Quantity:         1,
+				Price:            hotelOrder.Price,
+			})
+		}
+		if len(hotelRoomBookings) == 0 {
+			continue
+		}
+		if err := db.Create(&hotelRoomBookings).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func MigrateDB(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&models.User{},
+		&models.Region{},
 		&models.Station{},
 		&models.Train{},
 		&models.TrainStation{},
+		&models.TrainSchedule{},
+		&models.TrainScheduleException{},
+		&models.SeatLayout{},
 		&models.TrainCarriage{},
 		&models.TrainSeat{},
 		&models.TravelerDetail{},
 		&models.TicketOrder{},
 		&models.TicketTravelerDetail{},
+		&models.TrainSeatHold{},
+		&models.TrainWaitlist{},
+		&models.TrainSeatBooking{},
+		&models.TrainFare{},
+		&models.TrainFarePeak{},
+		&models.TrainFareAdvance{},
+		&models.RefundPolicy{},
+		&models.RefundPolicyRule{},
+		&models.TicketRefund{},
+		&models.OrderStatusHistory{},
+		&models.TicketReschedule{},
+		&models.SavedPassenger{},
 		&models.Article{},
 		&models.HistorySearch{},
 		&models.Payment{},
@@ -190,6 +303,10 @@ func MigrateDB(db *gorm.DB) error {
 		&models.HotelRoom{},
 		&models.HotelRoomImage{},
 		&models.HotelRoomFacilities{},
+		&models.HotelOrderRoom{},
+		&models.HotelRoomBooking{},
+		&models.HotelRoomRate{},
+		&models.HotelRefund{},
 		&models.Notification{},
 		&models.TemplateMessage{},
 		&models.HotelRating{},
@@ -0,0 +1,24 @@
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+const defaultPaymentWindowMinutes = 60
+
+// EnvPaymentWindowMinutes returns how long an order may stay unpaid before it is canceled.
+func EnvPaymentWindowMinutes() int {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	minutes, err := strconv.Atoi(os.Getenv("PAYMENT_WINDOW_MINUTES"))
+	if err != nil || minutes < 1 {
+		return defaultPaymentWindowMinutes
+	}
+	return minutes
+}
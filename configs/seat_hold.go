@@ -0,0 +1,23 @@
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+const defaultSeatHoldMinutes = 15
+
+func EnvSeatHoldMinutes() int {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	minutes, err := strconv.Atoi(os.Getenv("SEAT_HOLD_MINUTES"))
+	if err != nil || minutes < 1 {
+		return defaultSeatHoldMinutes
+	}
+	return minutes
+}
@@ -0,0 +1,25 @@
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+const defaultReschedulePaymentMinutes = 1440
+
+// EnvReschedulePaymentMinutes returns how long the extra payment of a reschedule may wait for an admin to
+// confirm it, the new seat stays held for that long.
+func EnvReschedulePaymentMinutes() int {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	minutes, err := strconv.Atoi(os.Getenv("RESCHEDULE_PAYMENT_MINUTES"))
+	if err != nil || minutes < 1 {
+		return defaultReschedulePaymentMinutes
+	}
+	return minutes
+}
@@ -0,0 +1,24 @@
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+const defaultWaitlistOfferMinutes = 30
+
+// EnvWaitlistOfferMinutes returns how long a seat offered to a waitlisted user stays held for them.
+func EnvWaitlistOfferMinutes() int {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	minutes, err := strconv.Atoi(os.Getenv("WAITLIST_OFFER_MINUTES"))
+	if err != nil || minutes < 1 {
+		return defaultWaitlistOfferMinutes
+	}
+	return minutes
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BoardingPassController interface {
+	GetBoardingPass(c echo.Context) error
+	CheckInBoardingPass(c echo.Context) error
+}
+
+type boardingPassController struct {
+	boardingPassUsecase usecases.BoardingPassUsecase
+}
+
+func NewBoardingPassController(boardingPassUsecase usecases.BoardingPassUsecase) BoardingPassController {
+	return &boardingPassController{boardingPassUsecase}
+}
+
+func (c *boardingPassController) GetBoardingPass(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	ticketOrderIDParam := ctx.QueryParam("ticket_order_id")
+	ticketOrderID, _ := strconv.Atoi(ticketOrderIDParam)
+
+	ticketTravelerDetailIDParam := ctx.QueryParam("ticket_traveler_detail_id")
+	ticketTravelerDetailID, _ := strconv.Atoi(ticketTravelerDetailIDParam)
+
+	boardingPass, err := c.boardingPassUsecase.GetBoardingPass(userId, uint(ticketOrderID), uint(ticketTravelerDetailID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get boarding pass",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	fileName := fmt.Sprintf("boarding-pass-%d.pdf", ticketOrderID)
+	if ticketTravelerDetailID > 0 {
+		fileName = fmt.Sprintf("boarding-pass-%d-%d.pdf", ticketOrderID, ticketTravelerDetailID)
+	}
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	return ctx.Blob(http.StatusOK, "application/pdf", boardingPass)
+}
+
+func (c *boardingPassController) CheckInBoardingPass(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	staffId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var boardingCheckInInput dtos.BoardingCheckInInput
+	if err := ctx.Bind(&boardingCheckInInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding boarding check in",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	boardingCheckIn, err := c.boardingPassUsecase.CheckInBoardingPass(staffId, boardingCheckInInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to check in passenger",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully checked in passenger",
+			boardingCheckIn,
+		),
+	)
+}
@@ -17,8 +17,11 @@ type HotelController interface {
 	GetHotelByID(c echo.Context) error
 	CreateHotel(c echo.Context) error
 	UpdateHotel(c echo.Context) error
+	UpdateHotelLocation(c echo.Context) error
 	DeleteHotel(c echo.Context) error
 	SearchHotelAvailable(c echo.Context) error
+	GetHotelSearchFacets(c echo.Context) error
+	GetHotelsNearTicketOrder(c echo.Context) error
 }
 
 type hotelController struct {
@@ -248,6 +251,78 @@ func (c *hotelController) UpdateHotel(ctx echo.Context) error {
 	)
 }
 
+func (c *hotelController) UpdateHotelLocation(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	var locationInput dtos.LocationInput
+	if err := ctx.Bind(&locationInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding hotel location",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	hotel, err := c.hotelUsecase.GetHotelByID(userId, uint(id))
+	if hotel.HotelID == 0 {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get hotel by id",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	hotelResp, err := c.hotelUsecase.UpdateHotelLocation(userId, uint(id), locationInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update hotel location",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated hotel location",
+			hotelResp,
+		),
+	)
+}
+
 func (c *hotelController) DeleteHotel(ctx echo.Context) error {
 	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
 	if tokenString == "" {
@@ -320,12 +395,27 @@ func (c *hotelController) SearchHotelAvailable(ctx echo.Context) error {
 	minimumPrice, _ := strconv.Atoi(ctx.QueryParam("minimum_price"))
 	maximumPrice, _ := strconv.Atoi(ctx.QueryParam("maximum_price"))
 	ratingClass, _ := strconv.Atoi(ctx.QueryParam("rating_class"))
+	guest, _ := strconv.Atoi(ctx.QueryParam("guest"))
+	stationId, _ := strconv.Atoi(ctx.QueryParam("station_id"))
+	regionId, _ := strconv.Atoi(ctx.QueryParam("region_id"))
+
+	var latitude, longitude *float64
+	if latitudeParam, err := strconv.ParseFloat(ctx.QueryParam("latitude"), 64); err == nil {
+		latitude = &latitudeParam
+	}
+	if longitudeParam, err := strconv.ParseFloat(ctx.QueryParam("longitude"), 64); err == nil {
+		longitude = &longitudeParam
+	}
+	radius, _ := strconv.ParseFloat(ctx.QueryParam("radius"), 64)
 
 	addressParam := ctx.QueryParam("address")
 	nameParam := ctx.QueryParam("name")
 
 	sortByPriceParam := ctx.QueryParam("sort_by_price")
-	hotels, count, err := c.hotelUsecase.SearchHotelAvailable(int(userId), page, limit, minimumPrice, maximumPrice, ratingClass, addressParam, nameParam, sortByPriceParam)
+	sortByDistance, _ := strconv.ParseBool(ctx.QueryParam("sort_by_distance"))
+	checkInParam := ctx.QueryParam("check_in")
+	checkOutParam := ctx.QueryParam("check_out")
+	hotels, count, err := c.hotelUsecase.SearchHotelAvailable(int(userId), page, limit, minimumPrice, maximumPrice, ratingClass, guest, stationId, regionId, latitude, longitude, radius, addressParam, nameParam, sortByPriceParam, checkInParam, checkOutParam, sortByDistance)
 	if err != nil {
 		return ctx.JSON(
 			http.StatusBadRequest,
@@ -349,3 +439,118 @@ func (c *hotelController) SearchHotelAvailable(ctx echo.Context) error {
 		),
 	)
 }
+
+func (c *hotelController) GetHotelSearchFacets(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	stationId, _ := strconv.Atoi(ctx.QueryParam("station_id"))
+	regionId, _ := strconv.Atoi(ctx.QueryParam("region_id"))
+
+	var latitude, longitude *float64
+	if latitudeParam, err := strconv.ParseFloat(ctx.QueryParam("latitude"), 64); err == nil {
+		latitude = &latitudeParam
+	}
+	if longitudeParam, err := strconv.ParseFloat(ctx.QueryParam("longitude"), 64); err == nil {
+		longitude = &longitudeParam
+	}
+	radius, _ := strconv.ParseFloat(ctx.QueryParam("radius"), 64)
+
+	addressParam := ctx.QueryParam("address")
+	nameParam := ctx.QueryParam("name")
+
+	facets, err := c.hotelUsecase.GetHotelSearchFacets(stationId, regionId, latitude, longitude, radius, addressParam, nameParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get hotel search facets",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get hotel search facets",
+			facets,
+		),
+	)
+}
+
+func (c *hotelController) GetHotelsNearTicketOrder(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 1000
+	}
+
+	ticketOrderId, _ := strconv.Atoi(ctx.QueryParam("ticket_order_id"))
+	radius, _ := strconv.ParseFloat(ctx.QueryParam("radius"), 64)
+
+	hotels, count, err := c.hotelUsecase.GetHotelsNearTicketOrder(userId, uint(ticketOrderId), page, limit, radius)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get hotels near ticket order",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get hotels near ticket order",
+			hotels,
+			page,
+			limit,
+			count,
+		),
+	)
+}
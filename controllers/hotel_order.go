@@ -23,6 +23,7 @@ type HotelOrderController interface {
 	CreateHotelOrder(c echo.Context) error
 	CreateHotelOrder2(c echo.Context) error
 	UpdateHotelOrder(c echo.Context) error
+	CancelHotelOrderRoom(c echo.Context) error
 	CsvHotelOrder(ctx echo.Context) error
 }
 
@@ -434,6 +435,56 @@ func (c *hotelOrderController) UpdateHotelOrder(ctx echo.Context) error {
 	)
 
 }
+
+func (c *hotelOrderController) CancelHotelOrderRoom(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	hotelOrderID, _ := strconv.Atoi(ctx.QueryParam("hotel_order_id"))
+	hotelOrderRoomID, _ := strconv.Atoi(ctx.QueryParam("hotel_order_room_id"))
+
+	hotelOrder, err := c.hotelOrderUsecase.CancelHotelOrderRoom(userId, uint(hotelOrderID), uint(hotelOrderRoomID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to cancel a hotel order room",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to cancel a hotel order room",
+			hotelOrder,
+		),
+	)
+}
 func (c *hotelOrderController) CsvHotelOrder(ctx echo.Context) error {
 	var cloudinary models.Url
 
@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HotelRefundController interface {
+	GetHotelRefundsByAdmin(c echo.Context) error
+	ApproveHotelRefund(c echo.Context) error
+	RejectHotelRefund(c echo.Context) error
+	RequestHotelRefund(c echo.Context) error
+	GetHotelRefunds(c echo.Context) error
+}
+
+type hotelRefundController struct {
+	hotelRefundUsecase usecases.HotelRefundUsecase
+}
+
+func NewHotelRefundController(hotelRefundUsecase usecases.HotelRefundUsecase) HotelRefundController {
+	return &hotelRefundController{hotelRefundUsecase}
+}
+
+// =============================== ADMIN ================================== \\
+
+func (c *hotelRefundController) GetHotelRefundsByAdmin(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	statusParam := ctx.QueryParam("status")
+
+	hotelRefunds, count, err := c.hotelRefundUsecase.GetHotelRefundsByAdmin(page, limit, statusParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusInternalServerError,
+			helpers.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Failed to get hotel refunds",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get hotel refunds",
+			hotelRefunds,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *hotelRefundController) ApproveHotelRefund(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var hotelRefundProcessInput dtos.HotelRefundProcessInput
+	if err := ctx.Bind(&hotelRefundProcessInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding hotel refund",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	hotelRefund, err := c.hotelRefundUsecase.ApproveHotelRefund(userId, uint(id), hotelRefundProcessInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to approve hotel refund",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully approved hotel refund",
+			hotelRefund,
+		),
+	)
+}
+
+func (c *hotelRefundController) RejectHotelRefund(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var hotelRefundProcessInput dtos.HotelRefundProcessInput
+	if err := ctx.Bind(&hotelRefundProcessInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding hotel refund",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	hotelRefund, err := c.hotelRefundUsecase.RejectHotelRefund(userId, uint(id), hotelRefundProcessInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to reject hotel refund",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully rejected hotel refund",
+			hotelRefund,
+		),
+	)
+}
+
+// =============================== ADMIN END ================================== \\
+
+// =============================== USER ================================== \\
+
+func (c *hotelRefundController) RequestHotelRefund(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var hotelRefundInput dtos.HotelRefundInput
+	if err := ctx.Bind(&hotelRefundInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding hotel refund",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	hotelRefund, err := c.hotelRefundUsecase.RequestHotelRefund(userId, hotelRefundInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to request hotel refund",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully requested hotel refund",
+			hotelRefund,
+		),
+	)
+}
+
+func (c *hotelRefundController) GetHotelRefunds(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	statusParam := ctx.QueryParam("status")
+
+	hotelRefunds, count, err := c.hotelRefundUsecase.GetHotelRefunds(page, limit, userId, statusParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusInternalServerError,
+			helpers.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Failed to get hotel refunds",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get hotel refunds",
+			hotelRefunds,
+			page,
+			limit,
+			count,
+		),
+	)
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HotelRoomRateController interface {
+	GetHotelRoomRates(c echo.Context) error
+	CreateHotelRoomRate(c echo.Context) error
+	DeleteHotelRoomRate(c echo.Context) error
+}
+
+type hotelRoomRateController struct {
+	hotelRoomRateUsecase usecases.HotelRoomRateUsecase
+}
+
+func NewHotelRoomRateController(hotelRoomRateUsecase usecases.HotelRoomRateUsecase) HotelRoomRateController {
+	return &hotelRoomRateController{hotelRoomRateUsecase}
+}
+
+func (c *hotelRoomRateController) GetHotelRoomRates(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	hotelRoomRates, err := c.hotelRoomRateUsecase.GetHotelRoomRates(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get hotel room rates",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get hotel room rates",
+			hotelRoomRates,
+		),
+	)
+}
+
+func (c *hotelRoomRateController) CreateHotelRoomRate(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var hotelRoomRateInput dtos.HotelRoomRateInput
+	if err := ctx.Bind(&hotelRoomRateInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding hotel room rate",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	hotelRoomRate, err := c.hotelRoomRateUsecase.CreateHotelRoomRate(uint(id), hotelRoomRateInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a hotel room rate",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a hotel room rate",
+			hotelRoomRate,
+		),
+	)
+}
+
+func (c *hotelRoomRateController) DeleteHotelRoomRate(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	rateId, _ := strconv.Atoi(ctx.Param("rate_id"))
+
+	err := c.hotelRoomRateUsecase.DeleteHotelRoomRate(uint(id), uint(rateId))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to delete hotel room rate",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted hotel room rate",
+			nil,
+		),
+	)
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OrderStatusHistoryController interface {
+	GetOrderStatusHistories(c echo.Context) error
+	GetOrderStatusHistoriesByAdmin(c echo.Context) error
+	UpdateTicketOrderStatusByAdmin(c echo.Context) error
+	UpdateHotelOrderStatusByAdmin(c echo.Context) error
+}
+
+type orderStatusHistoryController struct {
+	orderStatusHistoryUsecase usecases.OrderStatusHistoryUsecase
+}
+
+func NewOrderStatusHistoryController(orderStatusHistoryUsecase usecases.OrderStatusHistoryUsecase) OrderStatusHistoryController {
+	return &orderStatusHistoryController{orderStatusHistoryUsecase}
+}
+
+func (c *orderStatusHistoryController) GetOrderStatusHistories(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return c.getOrderStatusHistories(ctx, userId)
+}
+
+func (c *orderStatusHistoryController) GetOrderStatusHistoriesByAdmin(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	return c.getOrderStatusHistories(ctx, 1)
+}
+
+func (c *orderStatusHistoryController) getOrderStatusHistories(ctx echo.Context, userId uint) error {
+	orderTypeParam := ctx.QueryParam("order_type")
+
+	orderIDParam := ctx.QueryParam("order_id")
+	orderID, _ := strconv.Atoi(orderIDParam)
+
+	orderStatusHistories, err := c.orderStatusHistoryUsecase.GetOrderStatusHistories(userId, orderTypeParam, uint(orderID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get order status history",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get order status history",
+			orderStatusHistories,
+		),
+	)
+}
+
+func (c *orderStatusHistoryController) UpdateTicketOrderStatusByAdmin(ctx echo.Context) error {
+	return c.updateOrderStatusByAdmin(ctx, "ticket")
+}
+
+func (c *orderStatusHistoryController) UpdateHotelOrderStatusByAdmin(ctx echo.Context) error {
+	return c.updateOrderStatusByAdmin(ctx, "hotel")
+}
+
+func (c *orderStatusHistoryController) updateOrderStatusByAdmin(ctx echo.Context, orderType string) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var orderStatusInput dtos.OrderStatusInput
+	if err := ctx.Bind(&orderStatusInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding order status",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	orderStatusHistories, err := c.orderStatusHistoryUsecase.UpdateOrderStatusByAdmin(userId, orderType, uint(id), orderStatusInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update order status",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated order status",
+			orderStatusHistories,
+		),
+	)
+}
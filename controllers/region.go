@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RegionController interface {
+	GetRegions(c echo.Context) error
+	GetRegionByID(c echo.Context) error
+	CreateRegion(c echo.Context) error
+	UpdateRegion(c echo.Context) error
+	DeleteRegion(c echo.Context) error
+}
+
+type regionController struct {
+	regionUsecase usecases.RegionUsecase
+}
+
+func NewRegionController(regionUsecase usecases.RegionUsecase) RegionController {
+	return &regionController{regionUsecase}
+}
+
+func (c *regionController) GetRegions(ctx echo.Context) error {
+	parentID, _ := strconv.Atoi(ctx.QueryParam("parent_id"))
+	nameParam := ctx.QueryParam("name")
+
+	regions, err := c.regionUsecase.GetRegions(uint(parentID), nameParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get regions",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get regions",
+			regions,
+		),
+	)
+}
+
+func (c *regionController) GetRegionByID(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	region, err := c.regionUsecase.GetRegionByID(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get region by id",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get region",
+			region,
+		),
+	)
+}
+
+func (c *regionController) CreateRegion(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var regionInput dtos.RegionInput
+	if err := ctx.Bind(&regionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding region",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	region, err := c.regionUsecase.CreateRegion(regionInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a region",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a region",
+			region,
+		),
+	)
+}
+
+func (c *regionController) UpdateRegion(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var regionInput dtos.RegionInput
+	if err := ctx.Bind(&regionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding region",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	region, err := c.regionUsecase.UpdateRegion(uint(id), regionInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed update region",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated region",
+			region,
+		),
+	)
+}
+
+func (c *regionController) DeleteRegion(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.regionUsecase.DeleteRegion(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete region",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted region",
+			nil,
+		),
+	)
+}
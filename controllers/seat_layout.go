@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SeatLayoutController interface {
+	GetAllSeatLayouts(c echo.Context) error
+	GetSeatLayoutByID(c echo.Context) error
+	CreateSeatLayout(c echo.Context) error
+	UpdateSeatLayout(c echo.Context) error
+	DeleteSeatLayout(c echo.Context) error
+}
+
+type seatLayoutController struct {
+	seatLayoutUsecase usecases.SeatLayoutUsecase
+}
+
+func NewSeatLayoutController(seatLayoutUsecase usecases.SeatLayoutUsecase) SeatLayoutController {
+	return &seatLayoutController{seatLayoutUsecase}
+}
+
+func (c *seatLayoutController) GetAllSeatLayouts(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	seatLayouts, count, err := c.seatLayoutUsecase.GetAllSeatLayouts(page, limit)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusInternalServerError,
+			helpers.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Failed fetching seat layout",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get all seat layouts",
+			seatLayouts,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *seatLayoutController) GetSeatLayoutByID(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	seatLayout, err := c.seatLayoutUsecase.GetSeatLayoutByID(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get seat layout by id",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get seat layout by id",
+			seatLayout,
+		),
+	)
+}
+
+func (c *seatLayoutController) CreateSeatLayout(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var seatLayoutInput dtos.SeatLayoutInput
+	if err := ctx.Bind(&seatLayoutInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding seat layout",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	seatLayout, err := c.seatLayoutUsecase.CreateSeatLayout(seatLayoutInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a seat layout",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a seat layout",
+			seatLayout,
+		),
+	)
+}
+
+func (c *seatLayoutController) UpdateSeatLayout(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var seatLayoutInput dtos.SeatLayoutInput
+	if err := ctx.Bind(&seatLayoutInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding seat layout",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	seatLayout, err := c.seatLayoutUsecase.UpdateSeatLayout(uint(id), seatLayoutInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed update seat layout",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated seat layout",
+			seatLayout,
+		),
+	)
+}
+
+func (c *seatLayoutController) DeleteSeatLayout(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.seatLayoutUsecase.DeleteSeatLayout(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to delete seat layout",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted seat layout",
+			nil,
+		),
+	)
+}
@@ -15,8 +15,10 @@ type StationController interface {
 	GetAllStations(c echo.Context) error
 	GetAllStationsByAdmin(c echo.Context) error
 	GetStationByID(c echo.Context) error
+	AutocompleteStation(c echo.Context) error
 	CreateStation(c echo.Context) error
 	UpdateStation(c echo.Context) error
+	UpdateStationLocation(c echo.Context) error
 	DeleteStation(c echo.Context) error
 }
 
@@ -147,6 +149,37 @@ func (c *stationController) GetStationByID(ctx echo.Context) error {
 
 }
 
+func (c *stationController) AutocompleteStation(ctx echo.Context) error {
+	keywordParam := ctx.QueryParam("keyword")
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	stations, err := c.stationUsecase.AutocompleteStation(keywordParam, limit)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get station suggestions",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get station suggestions",
+			stations,
+		),
+	)
+}
+
 func (c *stationController) CreateStation(ctx echo.Context) error {
 	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
 	if tokenString == "" {
@@ -253,6 +286,66 @@ func (c *stationController) UpdateStation(ctx echo.Context) error {
 	)
 }
 
+func (c *stationController) UpdateStationLocation(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var locationInput dtos.LocationInput
+	if err := ctx.Bind(&locationInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding station location",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	station, err := c.stationUsecase.GetStationByID(uint(id))
+	if station.StationID == 0 {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get station by id",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	stationResp, err := c.stationUsecase.UpdateStationLocation(uint(id), locationInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed update station location",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated station location",
+			stationResp,
+		),
+	)
+}
+
 func (c *stationController) DeleteStation(ctx echo.Context) error {
 	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
 	if tokenString == "" {
@@ -0,0 +1,351 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TicketRescheduleController interface {
+	QuoteTicketReschedule(c echo.Context) error
+	CreateTicketReschedule(c echo.Context) error
+	UpdateTicketReschedule(c echo.Context) error
+	GetTicketReschedules(c echo.Context) error
+	GetTicketReschedulesByAdmin(c echo.Context) error
+	ConfirmTicketReschedule(c echo.Context) error
+}
+
+type ticketRescheduleController struct {
+	ticketRescheduleUsecase usecases.TicketRescheduleUsecase
+}
+
+func NewTicketRescheduleController(ticketRescheduleUsecase usecases.TicketRescheduleUsecase) TicketRescheduleController {
+	return &ticketRescheduleController{ticketRescheduleUsecase}
+}
+
+func (c *ticketRescheduleController) QuoteTicketReschedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var ticketRescheduleInput dtos.TicketRescheduleInput
+	if err := ctx.Bind(&ticketRescheduleInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding ticket reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	ticketRescheduleQuote, err := c.ticketRescheduleUsecase.QuoteTicketReschedule(userId, ticketRescheduleInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get reschedule quote",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get reschedule quote",
+			ticketRescheduleQuote,
+		),
+	)
+}
+
+func (c *ticketRescheduleController) CreateTicketReschedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var ticketRescheduleInput dtos.TicketRescheduleInput
+	if err := ctx.Bind(&ticketRescheduleInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding ticket reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	ticketReschedule, err := c.ticketRescheduleUsecase.CreateTicketReschedule(userId, ticketRescheduleInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to reschedule ticket",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully rescheduled ticket",
+			ticketReschedule,
+		),
+	)
+}
+
+func (c *ticketRescheduleController) UpdateTicketReschedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	ticketRescheduleIDParam := ctx.QueryParam("ticket_reschedule_id")
+	ticketRescheduleID, _ := strconv.Atoi(ticketRescheduleIDParam)
+
+	statusParam := ctx.QueryParam("status")
+
+	ticketReschedule, err := c.ticketRescheduleUsecase.UpdateTicketReschedule(userId, uint(ticketRescheduleID), statusParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update ticket reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated ticket reschedule",
+			ticketReschedule,
+		),
+	)
+}
+
+func (c *ticketRescheduleController) GetTicketReschedules(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	statusParam := ctx.QueryParam("status")
+
+	ticketReschedules, count, err := c.ticketRescheduleUsecase.GetTicketReschedules(page, limit, userId, statusParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusInternalServerError,
+			helpers.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Failed to get ticket reschedules",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get ticket reschedules",
+			ticketReschedules,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *ticketRescheduleController) GetTicketReschedulesByAdmin(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	statusParam := ctx.QueryParam("status")
+
+	ticketReschedules, count, err := c.ticketRescheduleUsecase.GetTicketReschedulesByAdmin(page, limit, statusParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusInternalServerError,
+			helpers.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Failed to get ticket reschedules",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get ticket reschedules",
+			ticketReschedules,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *ticketRescheduleController) ConfirmTicketReschedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	ticketReschedule, err := c.ticketRescheduleUsecase.ConfirmTicketReschedule(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to confirm ticket reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully confirmed ticket reschedule",
+			ticketReschedule,
+		),
+	)
+}
@@ -22,6 +22,7 @@ type TrainController interface {
 
 	//user
 	SearchTrainAvailable(c echo.Context) error
+	SearchTrainJourney(c echo.Context) error
 }
 
 type trainController struct {
@@ -348,7 +349,12 @@ func (c *trainController) SearchTrainAvailable(ctx echo.Context) error {
 	stationDestinationIdParam := ctx.QueryParam("station_destination_id")
 	stationDestinationId, _ := strconv.Atoi(stationDestinationIdParam)
 
-	trains, count, err := c.trainUsecase.SearchTrainAvailable(userId, page, limit, stationOriginId, stationDestinationId, sortByTrainId, classParam, sortByPriceParam, sortByArriveTimeParam)
+	originCityParam := ctx.QueryParam("origin_city")
+	destinationCityParam := ctx.QueryParam("destination_city")
+
+	dateParam := ctx.QueryParam("date")
+
+	trains, count, err := c.trainUsecase.SearchTrainAvailable(userId, page, limit, stationOriginId, stationDestinationId, sortByTrainId, originCityParam, destinationCityParam, dateParam, classParam, sortByPriceParam, sortByArriveTimeParam)
 	if err != nil {
 		return ctx.JSON(
 			http.StatusBadRequest,
@@ -372,3 +378,86 @@ func (c *trainController) SearchTrainAvailable(ctx echo.Context) error {
 		),
 	)
 }
+
+func (c *trainController) SearchTrainJourney(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 1000
+	}
+
+	classParam := ctx.QueryParam("sort_by_class")
+
+	maxTransferParam := ctx.QueryParam("max_transfer")
+	maxTransfer, err := strconv.Atoi(maxTransferParam)
+	if err != nil {
+		maxTransfer = 2
+	}
+
+	minConnectionTimeParam := ctx.QueryParam("min_connection_time")
+	minConnectionTime, err := strconv.Atoi(minConnectionTimeParam)
+	if err != nil {
+		minConnectionTime = 30
+	}
+
+	stationOriginIdParam := ctx.QueryParam("station_origin_id")
+	stationOriginId, _ := strconv.Atoi(stationOriginIdParam)
+
+	stationDestinationIdParam := ctx.QueryParam("station_destination_id")
+	stationDestinationId, _ := strconv.Atoi(stationDestinationIdParam)
+
+	dateParam := ctx.QueryParam("date")
+
+	journeys, count, err := c.trainUsecase.SearchTrainJourney(userId, page, limit, stationOriginId, stationDestinationId, maxTransfer, minConnectionTime, dateParam, classParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get train journey",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get train journey",
+			journeys,
+			page,
+			limit,
+			count,
+		),
+	)
+}
@@ -32,6 +32,10 @@ func NewTrainCarriageController(trainCarriageUsecase usecases.TrainCarriageUseca
 func (c *trainCarriageController) GetAllTrainCarriages(ctx echo.Context) error {
 	trainIdParam := ctx.QueryParam("train_id")
 	trainId, _ := strconv.Atoi(trainIdParam)
+	stationOriginIdParam := ctx.QueryParam("station_origin_id")
+	stationOriginId, _ := strconv.Atoi(stationOriginIdParam)
+	stationDestinationIdParam := ctx.QueryParam("station_destination_id")
+	stationDestinationId, _ := strconv.Atoi(stationDestinationIdParam)
 
 	pageParam := ctx.QueryParam("page")
 	page, err := strconv.Atoi(pageParam)
@@ -48,7 +52,7 @@ func (c *trainCarriageController) GetAllTrainCarriages(ctx echo.Context) error {
 	dateParam := ctx.QueryParam("date")
 	statusParam := ctx.QueryParam("status")
 
-	trainCarriages, count, err := c.trainCarriageUsecase.GetAllTrainCarriages(trainId, page, limit, classParam, dateParam, statusParam)
+	trainCarriages, count, err := c.trainCarriageUsecase.GetAllTrainCarriages(trainId, stationOriginId, stationDestinationId, page, limit, classParam, dateParam, statusParam)
 	if err != nil {
 		return ctx.JSON(
 			http.StatusBadRequest,
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TrainFareController interface {
+	GetTrainFare(c echo.Context) error
+	UpdateTrainFare(c echo.Context) error
+	CreateTrainFarePeak(c echo.Context) error
+	DeleteTrainFarePeak(c echo.Context) error
+	CreateTrainFareAdvance(c echo.Context) error
+	DeleteTrainFareAdvance(c echo.Context) error
+}
+
+type trainFareController struct {
+	trainFareUsecase usecases.TrainFareUsecase
+}
+
+func NewTrainFareController(trainFareUsecase usecases.TrainFareUsecase) TrainFareController {
+	return &trainFareController{trainFareUsecase}
+}
+
+func (c *trainFareController) GetTrainFare(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainFare, err := c.trainFareUsecase.GetTrainFare(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get train fare",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get train fare",
+			trainFare,
+		),
+	)
+}
+
+func (c *trainFareController) UpdateTrainFare(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var trainFareInput dtos.TrainFareInput
+	if err := ctx.Bind(&trainFareInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding train fare",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainFare, err := c.trainFareUsecase.UpdateTrainFare(uint(id), trainFareInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed update train fare",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated train fare",
+			trainFare,
+		),
+	)
+}
+
+func (c *trainFareController) CreateTrainFarePeak(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var trainFarePeakInput dtos.TrainFarePeakInput
+	if err := ctx.Bind(&trainFarePeakInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding train fare peak",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainFarePeak, err := c.trainFareUsecase.CreateTrainFarePeak(uint(id), trainFarePeakInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a train fare peak",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a train fare peak",
+			trainFarePeak,
+		),
+	)
+}
+
+func (c *trainFareController) DeleteTrainFarePeak(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	peakId, _ := strconv.Atoi(ctx.Param("peak_id"))
+
+	err := c.trainFareUsecase.DeleteTrainFarePeak(uint(id), uint(peakId))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to delete train fare peak",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted train fare peak",
+			nil,
+		),
+	)
+}
+
+func (c *trainFareController) CreateTrainFareAdvance(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var trainFareAdvanceInput dtos.TrainFareAdvanceInput
+	if err := ctx.Bind(&trainFareAdvanceInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding train fare advance discount",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainFareAdvance, err := c.trainFareUsecase.CreateTrainFareAdvance(uint(id), trainFareAdvanceInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a train fare advance discount",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a train fare advance discount",
+			trainFareAdvance,
+		),
+	)
+}
+
+func (c *trainFareController) DeleteTrainFareAdvance(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	advanceId, _ := strconv.Atoi(ctx.Param("advance_id"))
+
+	err := c.trainFareUsecase.DeleteTrainFareAdvance(uint(id), uint(advanceId))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to delete train fare advance discount",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted train fare advance discount",
+			nil,
+		),
+	)
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TrainScheduleController interface {
+	GetTrainSchedule(c echo.Context) error
+	UpdateTrainSchedule(c echo.Context) error
+	CreateTrainScheduleException(c echo.Context) error
+	DeleteTrainScheduleException(c echo.Context) error
+}
+
+type trainScheduleController struct {
+	trainScheduleUsecase usecases.TrainScheduleUsecase
+}
+
+func NewTrainScheduleController(trainScheduleUsecase usecases.TrainScheduleUsecase) TrainScheduleController {
+	return &trainScheduleController{trainScheduleUsecase}
+}
+
+func (c *trainScheduleController) GetTrainSchedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainSchedule, err := c.trainScheduleUsecase.GetTrainSchedule(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get train schedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get train schedule",
+			trainSchedule,
+		),
+	)
+}
+
+func (c *trainScheduleController) UpdateTrainSchedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var trainScheduleInput dtos.TrainScheduleInput
+	if err := ctx.Bind(&trainScheduleInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding train schedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainSchedule, err := c.trainScheduleUsecase.UpdateTrainSchedule(uint(id), trainScheduleInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed update train schedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated train schedule",
+			trainSchedule,
+		),
+	)
+}
+
+func (c *trainScheduleController) CreateTrainScheduleException(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	var trainScheduleExceptionInput dtos.TrainScheduleExceptionInput
+	if err := ctx.Bind(&trainScheduleExceptionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding train schedule exception",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	trainScheduleException, err := c.trainScheduleUsecase.CreateTrainScheduleException(uint(id), trainScheduleExceptionInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a train schedule exception",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a train schedule exception",
+			trainScheduleException,
+		),
+	)
+}
+
+func (c *trainScheduleController) DeleteTrainScheduleException(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	exceptionId, _ := strconv.Atoi(ctx.Param("exception_id"))
+
+	err := c.trainScheduleUsecase.DeleteTrainScheduleException(uint(id), uint(exceptionId))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to delete train schedule exception",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted train schedule exception",
+			nil,
+		),
+	)
+}
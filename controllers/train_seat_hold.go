@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/middlewares"
+	"back-end-golang/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TrainSeatHoldController interface {
+	GetTrainSeatHolds(c echo.Context) error
+	CreateTrainSeatHold(c echo.Context) error
+	DeleteTrainSeatHold(c echo.Context) error
+}
+
+type trainSeatHoldController struct {
+	trainSeatHoldUsecase usecases.TrainSeatHoldUsecase
+}
+
+func NewTrainSeatHoldController(trainSeatHoldUsecase usecases.TrainSeatHoldUsecase) TrainSeatHoldController {
+	return &trainSeatHoldController{trainSeatHoldUsecase}
+}
+
+func (c *trainSeatHoldController) GetTrainSeatHolds(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	trainSeatHolds, err := c.trainSeatHoldUsecase.GetTrainSeatHolds(userId)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get train seat holds",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully get train seat holds",
+			trainSeatHolds,
+		),
+	)
+}
+
+func (c *trainSeatHoldController) CreateTrainSeatHold(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	var trainSeatHoldInput dtos.TrainSeatHoldInput
+	if err := ctx.Bind(&trainSeatHoldInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding train seat hold",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	trainSeatHold, err := c.trainSeatHoldUsecase.CreateTrainSeatHold(userId, trainSeatHoldInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a train seat hold",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a train seat hold",
+			trainSeatHold,
+		),
+	)
+}
+
+func (c *trainSeatHoldController) DeleteTrainSeatHold(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err = c.trainSeatHoldUsecase.DeleteTrainSeatHold(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to delete train seat hold",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted train seat hold",
+			nil,
+		),
+	)
+}
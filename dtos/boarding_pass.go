@@ -0,0 +1,25 @@
+package dtos
+
+import "time"
+
+type BoardingCheckInInput struct {
+	BoardingTicketCode string `json:"boarding_ticket_code" form:"boarding_ticket_code" example:"boarding-ticket-0d5e0b4a-4f0a-4a64-9f3e-6a5b0c0f8f10.hejbyVUjc2UWTu68fhJ7e5Ue9mzXyjznFSGN0c21adw"`
+	StationID          uint   `json:"station_id" form:"station_id" example:"1"`
+}
+
+type BoardingCheckInResponse struct {
+	TicketOrderID          uint      `json:"ticket_order_id" example:"1"`
+	TicketTravelerDetailID uint      `json:"ticket_traveler_detail_id" example:"1"`
+	BoardingTicketCode     string    `json:"boarding_ticket_code" example:"boarding-ticket-0d5e0b4a-4f0a-4a64-9f3e-6a5b0c0f8f10"`
+	FullName               string    `json:"full_name" example:"Mochammad Hanif"`
+	PassengerType          string    `json:"passenger_type" example:"adult"`
+	TrainID                uint      `json:"train_id" example:"1"`
+	TrainCarriageID        uint      `json:"train_carriage_id" example:"1"`
+	TrainSeatID            uint      `json:"train_seat_id" example:"5"`
+	StationOriginID        uint      `json:"station_origin_id" example:"1"`
+	StationDestinationID   uint      `json:"station_destination_id" example:"3"`
+	DepartureTime          string    `json:"departure_time" example:"08:00"`
+	Date                   time.Time `json:"date" example:"2023-06-20T00:00:00+07:00"`
+	BoardedAt              time.Time `json:"boarded_at" example:"2023-06-20T07:45:16.504+07:00"`
+	BoardedBy              uint      `json:"boarded_by" example:"3"`
+}
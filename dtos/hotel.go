@@ -9,6 +9,10 @@ type HotelInput struct {
 	PhoneNumber     string                 `form:"phone_number" json:"phone_number"`
 	Email           string                 `form:"email" json:"email"`
 	Address         string                 `form:"address" json:"address"`
+	Latitude        *float64               `form:"latitude" json:"latitude,omitempty" example:"-6.1754"`
+	Longitude       *float64               `form:"longitude" json:"longitude,omitempty" example:"106.8272"`
+	RegionID        uint                   `form:"region_id" json:"region_id,omitempty" example:"3"`
+	PostalCode      string                 `form:"postal_code" json:"postal_code" example:"10110"`
 	HotelImage      []HotelImageInput      `form:"hotel_image" json:"hotel_image"`
 	HotelFacilities []HotelFacilitiesInput `form:"hotel_facilities" json:"hotel_facilities"`
 	HotelPolicy     []HotelPoliciesInput   `form:"hotel_policy" json:"hotel_policy"`
@@ -22,6 +26,11 @@ type HotelResponse struct {
 	PhoneNumber     string                     `form:"phone_number" json:"phone_number"`
 	Email           string                     `form:"email" json:"email"`
 	Address         string                     `form:"address" json:"address"`
+	Latitude        *float64                   `form:"latitude" json:"latitude,omitempty"`
+	Longitude       *float64                   `form:"longitude" json:"longitude,omitempty"`
+	DistanceKm      *float64                   `form:"distance_km" json:"distance_km,omitempty"`
+	PostalCode      string                     `form:"postal_code" json:"postal_code,omitempty"`
+	Region          []RegionResponse           `form:"region" json:"region,omitempty"`
 	HotelRoom       []HotelRoomHotelIDResponse `form:"hotel_room" json:"hotel_room,omitempty"`
 	HotelRoomStart  int                        `form:"hotel_room_start" json:"hotel_room_start"`
 	HotelImage      []HotelImageResponse       `form:"hotel_image" json:"hotel_image"`
@@ -38,6 +47,10 @@ type HotelByIDResponse struct {
 	PhoneNumber     string                     `form:"phone_number" json:"phone_number"`
 	Email           string                     `form:"email" json:"email"`
 	Address         string                     `form:"address" json:"address"`
+	Latitude        *float64                   `form:"latitude" json:"latitude,omitempty"`
+	Longitude       *float64                   `form:"longitude" json:"longitude,omitempty"`
+	PostalCode      string                     `form:"postal_code" json:"postal_code,omitempty"`
+	Region          []RegionResponse           `form:"region" json:"region,omitempty"`
 	HotelImage      []HotelImageResponse       `form:"hotel_image" json:"hotel_image"`
 	HotelFacilities []HotelFacilitiesResponse  `form:"hotel_facilities" json:"hotel_facilities"`
 	HotelPolicy     HotelPoliciesResponse      `form:"hotel_policy" json:"hotel_policy"`
@@ -15,6 +15,27 @@ type HotelOrderInput struct {
 	PhoneNumberOrder string                `form:"phone_number_order" json:"phone_number_order" example:"085115151515"`
 	SpecialRequest   string                `form:"special_request" json:"special_request" example:"Tambah 1 Bed"`
 	TravelerDetail   []TravelerDetailInput `json:"traveler_detail"`
+	Rooms            []HotelOrderRoomInput `json:"rooms"`
+}
+
+// HotelOrderRoomInput is a room line of an order. Orders without lines book one room of HotelRoomID.
+type HotelOrderRoomInput struct {
+	HotelRoomID    int `form:"hotel_room_id" json:"hotel_room_id" example:"1"`
+	Quantity       int `form:"quantity" json:"quantity" example:"1"`
+	QuantityAdult  int `form:"quantity_adult" json:"quantity_adult" example:"2"`
+	QuantityInfant int `form:"quantity_infant" json:"quantity_infant" example:"0"`
+}
+
+type HotelOrderRoomResponse struct {
+	HotelOrderRoomID uint                      `json:"hotel_order_room_id" example:"1"`
+	HotelRoomID      uint                      `json:"hotel_room_id" example:"1"`
+	Name             string                    `json:"name" example:"Deluxe"`
+	Quantity         int                       `json:"quantity" example:"1"`
+	QuantityAdult    int                       `json:"quantity_adult" example:"2"`
+	QuantityInfant   int                       `json:"quantity_infant" example:"0"`
+	Price            int                       `json:"price" example:"450000"`
+	Status           string                    `json:"status" example:"active"`
+	Nights           []HotelOrderNightResponse `json:"nights,omitempty"`
 }
 
 type HotelOrderMidtransInput struct {
@@ -49,6 +70,7 @@ type HotelOrderResponse struct {
 	IsCheckIn        bool                      `json:"is_check_in" example:"false"`
 	IsCheckOut       bool                      `json:"is_check_out" example:"false"`
 	Status           string                    `json:"status" example:"unpaid"`
+	Rooms            []HotelOrderRoomResponse  `json:"rooms,omitempty"`
 	Hotel            HotelByIDResponses        `json:"hotel"`
 	Payment          *PaymentResponses         `json:"payment,omitempty"`
 	TravelerDetail   []TravelerDetailResponse  `json:"traveler_detail"`
@@ -75,6 +97,7 @@ type HotelOrderResponse2 struct {
 	IsCheckIn        bool                      `json:"is_check_in" example:"false"`
 	IsCheckOut       bool                      `json:"is_check_out" example:"false"`
 	Status           string                    `json:"status" example:"unpaid"`
+	Rooms            []HotelOrderRoomResponse  `json:"rooms,omitempty"`
 	Hotel            HotelByIDResponses        `json:"hotel"`
 	TravelerDetail   []TravelerDetailResponse  `json:"traveler_detail"`
 	User             *UserInformationResponses `json:"user,omitempty"`
@@ -0,0 +1,26 @@
+package dtos
+
+import "time"
+
+type HotelRefundInput struct {
+	HotelOrderID uint   `json:"hotel_order_id" form:"hotel_order_id" example:"1"`
+	Reason       string `json:"reason" form:"reason" example:"Change of plans"`
+}
+
+type HotelRefundProcessInput struct {
+	Note string `json:"note" form:"note" example:"Transferred to the original account"`
+}
+
+type HotelRefundResponse struct {
+	HotelRefundID uint       `json:"hotel_refund_id" example:"1"`
+	HotelOrderID  uint       `json:"hotel_order_id" example:"1"`
+	UserID        uint       `json:"user_id" example:"1"`
+	Amount        int        `json:"amount" example:"500000"`
+	Reason        string     `json:"reason" example:"Change of plans"`
+	Status        string     `json:"status" example:"pending"`
+	ProcessedBy   uint       `json:"processed_by,omitempty" example:"1"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	Note          string     `json:"note,omitempty" example:"Transferred to the original account"`
+	CreatedAt     time.Time  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt     time.Time  `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
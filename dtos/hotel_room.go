@@ -42,6 +42,7 @@ type HotelRoomHotelIDResponse struct {
 	Name              string                        `form:"name" json:"name"`
 	SizeOfRoom        int                           `form:"size_of_room" json:"size_of_room"`
 	QuantityOfRoom    int                           `form:"quantity_of_room" json:"quantity_of_room"`
+	AvailableRoom     int                           `form:"available_room" json:"available_room,omitempty"`
 	Description       string                        `form:"description" json:"description"`
 	NormalPrice       int                           `form:"normal_price" json:"normal_price"`
 	Discount          int                           `form:"discount" json:"discount"`
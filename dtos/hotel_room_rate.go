@@ -0,0 +1,26 @@
+package dtos
+
+type HotelRoomRateInput struct {
+	DateStart   string `json:"date_start" form:"date_start" example:"2023-06-20"`
+	DateEnd     string `json:"date_end" form:"date_end" example:"2023-06-30"`
+	Weekdays    []int  `json:"weekdays" form:"weekdays" example:"5,6"`
+	Price       int    `json:"price" form:"price" example:"450000"`
+	MinimumStay int    `json:"minimum_stay" form:"minimum_stay" example:"2"`
+	Note        string `json:"note" form:"note" example:"Weekend"`
+}
+
+type HotelRoomRateResponse struct {
+	HotelRoomRateID uint   `json:"hotel_room_rate_id" example:"1"`
+	HotelRoomID     uint   `json:"hotel_room_id" example:"1"`
+	DateStart       string `json:"date_start,omitempty" example:"2023-06-20"`
+	DateEnd         string `json:"date_end,omitempty" example:"2023-06-30"`
+	Weekdays        []int  `json:"weekdays" example:"5,6"`
+	Price           int    `json:"price" example:"450000"`
+	MinimumStay     int    `json:"minimum_stay" example:"2"`
+	Note            string `json:"note" example:"Weekend"`
+}
+
+type HotelOrderNightResponse struct {
+	Date  string `json:"date" example:"2023-05-01"`
+	Price int    `json:"price" example:"450000"`
+}
@@ -0,0 +1,6 @@
+package dtos
+
+type LocationInput struct {
+	Latitude  *float64 `form:"latitude" json:"latitude" example:"-6.1766"`
+	Longitude *float64 `form:"longitude" json:"longitude" example:"106.8308"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+type OrderStatusInput struct {
+	Status string `json:"status" form:"status" example:"paid"`
+	Note   string `json:"note" form:"note" example:"Bank transfer received"`
+}
+
+type OrderStatusHistoryResponse struct {
+	OrderStatusHistoryID uint      `json:"order_status_history_id" example:"1"`
+	OrderType            string    `json:"order_type" example:"ticket"`
+	OrderID              uint      `json:"order_id" example:"1"`
+	FromStatus           string    `json:"from_status" example:"unpaid"`
+	ToStatus             string    `json:"to_status" example:"paid"`
+	ChangedBy            uint      `json:"changed_by" example:"1"`
+	Note                 string    `json:"note" example:"Midtrans settlement"`
+	CreatedAt            time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
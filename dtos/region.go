@@ -0,0 +1,26 @@
+package dtos
+
+type RegionInput struct {
+	ParentID uint   `form:"parent_id" json:"parent_id" example:"1"`
+	Name     string `form:"name" json:"name" example:"Kota Denpasar"`
+}
+
+type RegionResponse struct {
+	RegionID uint   `json:"region_id" example:"2"`
+	ParentID uint   `json:"parent_id" example:"1"`
+	Level    string `json:"level" example:"regency"`
+	Name     string `json:"name" example:"Kota Denpasar"`
+}
+
+type HotelRegionFacetResponse struct {
+	RegionID uint   `json:"region_id" example:"2"`
+	ParentID uint   `json:"parent_id" example:"1"`
+	Name     string `json:"name" example:"Kota Denpasar"`
+	Count    int    `json:"count" example:"12"`
+}
+
+type HotelSearchFacetResponse struct {
+	Province []HotelRegionFacetResponse `json:"province"`
+	Regency  []HotelRegionFacetResponse `json:"regency"`
+	District []HotelRegionFacetResponse `json:"district"`
+}
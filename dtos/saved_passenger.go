@@ -0,0 +1,22 @@
+package dtos
+
+import "time"
+
+type SavedPassengerInput struct {
+	Title     string `form:"title" json:"title" example:"Saudara"`
+	FullName  string `form:"full_name" json:"full_name" example:"Mochammad Hanif"`
+	IDType    string `form:"id_type" json:"id_type" example:"ktp"`
+	IDNumber  string `form:"id_number" json:"id_number" example:"1902389012801211"`
+	BirthDate string `form:"birth_date" json:"birth_date" example:"2000-01-01"`
+}
+
+type SavedPassengerResponse struct {
+	SavedPassengerID uint      `json:"saved_passenger_id" example:"1"`
+	Title            string    `json:"title" example:"Saudara"`
+	FullName         string    `json:"full_name" example:"Mochammad Hanif"`
+	IDType           string    `json:"id_type" example:"ktp"`
+	IDNumber         string    `json:"id_number" example:"1902389012801211"`
+	BirthDate        string    `json:"birth_date,omitempty" example:"2000-01-01"`
+	CreatedAt        time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt        time.Time `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
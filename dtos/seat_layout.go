@@ -0,0 +1,30 @@
+package dtos
+
+import "time"
+
+type SeatLayoutInput struct {
+	Name             string   `json:"name" form:"name" example:"Ekonomi 106"`
+	Class            string   `json:"class" form:"class" example:"Ekonomi"`
+	Rows             int      `json:"rows" form:"rows" example:"22"`
+	Columns          int      `json:"columns" form:"columns" example:"5"`
+	AisleAfterColumn int      `json:"aisle_after_column" form:"aisle_after_column" example:"2"`
+	DisabledSeats    []string `json:"disabled_seats" form:"disabled_seats" example:"C1,C2"`
+}
+
+type SeatLayoutResponse struct {
+	SeatLayoutID     uint      `json:"seat_layout_id" example:"1"`
+	Name             string    `json:"name" example:"Ekonomi 106"`
+	Class            string    `json:"class" example:"Ekonomi"`
+	Rows             int       `json:"rows" example:"22"`
+	Columns          int       `json:"columns" example:"5"`
+	AisleAfterColumn int       `json:"aisle_after_column" example:"2"`
+	DisabledSeats    []string  `json:"disabled_seats" example:"C1,C2"`
+	CreatedAt        time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt        time.Time `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type SeatMapResponse struct {
+	Rows             int `json:"rows" example:"22"`
+	Columns          int `json:"columns" example:"5"`
+	AisleAfterColumn int `json:"aisle_after_column" example:"2"`
+}
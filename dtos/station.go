@@ -3,19 +3,34 @@ package dtos
 import "time"
 
 type StationInput struct {
-	Origin  string `form:"origin" json:"origin" example:"Jakarta"`
-	Name    string `form:"name" json:"name" example:"Pasar Senen"`
-	Initial string `form:"initial" json:"initial" example:"PSE"`
+	Origin    string   `form:"origin" json:"origin" example:"Jakarta"`
+	Name      string   `form:"name" json:"name" example:"Pasar Senen"`
+	Initial   string   `form:"initial" json:"initial" example:"PSE"`
+	Latitude  *float64 `form:"latitude" json:"latitude,omitempty" example:"-6.1744"`
+	Longitude *float64 `form:"longitude" json:"longitude,omitempty" example:"106.8451"`
+	RegionID  uint     `form:"region_id" json:"region_id,omitempty" example:"3"`
 }
 
 type StationResponse struct {
-	StationID uint      `json:"station_id" example:"1"`
-	Origin    string    `json:"origin" example:"Jakarta"`
-	Name      string    `json:"name" example:"Pasar Senen"`
-	Initial   string    `json:"initial" example:"PSE"`
-	CreatedAt time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
-	UpdatedAt time.Time `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
-	DeletedAt *string   `json:"deleted_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	StationID uint             `json:"station_id" example:"1"`
+	Origin    string           `json:"origin" example:"Jakarta"`
+	Name      string           `json:"name" example:"Pasar Senen"`
+	Initial   string           `json:"initial" example:"PSE"`
+	Latitude  *float64         `json:"latitude,omitempty" example:"-6.1744"`
+	Longitude *float64         `json:"longitude,omitempty" example:"106.8451"`
+	Region    []RegionResponse `json:"region,omitempty"`
+	CreatedAt time.Time        `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt time.Time        `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+	DeletedAt *string          `json:"deleted_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type StationAutocompleteResponse struct {
+	Type         string `json:"type" example:"station"`
+	StationID    uint   `json:"station_id,omitempty" example:"1"`
+	Origin       string `json:"origin" example:"Jakarta"`
+	Name         string `json:"name,omitempty" example:"Pasar Senen"`
+	Initial      string `json:"initial,omitempty" example:"PSE"`
+	StationCount int    `json:"station_count,omitempty" example:"2"`
 }
 
 type StationResponseSimply struct {
@@ -34,6 +34,12 @@ type StationStatusOKResponse struct {
 	Data       StationResponse `json:"data"`
 }
 
+type StationAutocompleteStatusOKResponse struct {
+	StatusCode int                           `json:"status_code" example:"200"`
+	Message    string                        `json:"message" example:"Successfully get station suggestions"`
+	Data       []StationAutocompleteResponse `json:"data"`
+}
+
 type StationCreeatedResponse struct {
 	StatusCode int             `json:"status_code" example:"201"`
 	Message    string          `json:"message" example:"Successfully created station"`
@@ -54,6 +60,13 @@ type GetAllTrainStatusOKResponses struct {
 	Meta       helpers.Meta   `json:"meta"`
 }
 
+type GetAllTrainJourneyStatusOKResponse struct {
+	StatusCode int                  `json:"status_code" example:"200"`
+	Message    string               `json:"message" example:"Successfully get train journey"`
+	Data       TrainJourneyResponse `json:"data"`
+	Meta       helpers.Meta         `json:"meta"`
+}
+
 type TrainStatusOKResponse struct {
 	StatusCode int           `json:"status_code" example:"200"`
 	Message    string        `json:"message" example:"Successfully get train"`
@@ -72,6 +85,227 @@ type TrainCreeatedResponse struct {
 	Data       TrainResponse `json:"data"`
 }
 
+type TrainScheduleStatusOKResponse struct {
+	StatusCode int                   `json:"status_code" example:"200"`
+	Message    string                `json:"message" example:"Successfully get train schedule"`
+	Data       TrainScheduleResponse `json:"data"`
+}
+
+type TrainScheduleExceptionCreatedResponse struct {
+	StatusCode int                            `json:"status_code" example:"201"`
+	Message    string                         `json:"message" example:"Successfully created train schedule exception"`
+	Data       TrainScheduleExceptionResponse `json:"data"`
+}
+
+type GetAllSeatLayoutStatusOKResponse struct {
+	StatusCode int                  `json:"status_code" example:"200"`
+	Message    string               `json:"message" example:"Successfully get all seat layouts"`
+	Data       []SeatLayoutResponse `json:"data"`
+	Meta       helpers.Meta         `json:"meta"`
+}
+
+type SeatLayoutStatusOKResponse struct {
+	StatusCode int                `json:"status_code" example:"200"`
+	Message    string             `json:"message" example:"Successfully get seat layout by id"`
+	Data       SeatLayoutResponse `json:"data"`
+}
+
+type SeatLayoutCreatedResponse struct {
+	StatusCode int                `json:"status_code" example:"201"`
+	Message    string             `json:"message" example:"Successfully to created a seat layout"`
+	Data       SeatLayoutResponse `json:"data"`
+}
+
+type TrainFareStatusOKResponse struct {
+	StatusCode int               `json:"status_code" example:"200"`
+	Message    string            `json:"message" example:"Successfully get train fare"`
+	Data       TrainFareResponse `json:"data"`
+}
+
+type TrainFarePeakCreatedResponse struct {
+	StatusCode int                   `json:"status_code" example:"201"`
+	Message    string                `json:"message" example:"Successfully to created a train fare peak"`
+	Data       TrainFarePeakResponse `json:"data"`
+}
+
+type TrainFareAdvanceCreatedResponse struct {
+	StatusCode int                      `json:"status_code" example:"201"`
+	Message    string                   `json:"message" example:"Successfully to created a train fare advance discount"`
+	Data       TrainFareAdvanceResponse `json:"data"`
+}
+
+type GetAllHotelRoomRateStatusOKResponse struct {
+	StatusCode int                     `json:"status_code" example:"200"`
+	Message    string                  `json:"message" example:"Successfully get hotel room rates"`
+	Data       []HotelRoomRateResponse `json:"data"`
+}
+
+type HotelRoomRateCreatedResponse struct {
+	StatusCode int                   `json:"status_code" example:"201"`
+	Message    string                `json:"message" example:"Successfully to created a hotel room rate"`
+	Data       HotelRoomRateResponse `json:"data"`
+}
+
+type GetAllRegionStatusOKResponse struct {
+	StatusCode int              `json:"status_code" example:"200"`
+	Message    string           `json:"message" example:"Successfully get regions"`
+	Data       []RegionResponse `json:"data"`
+}
+
+type RegionStatusOKResponse struct {
+	StatusCode int            `json:"status_code" example:"200"`
+	Message    string         `json:"message" example:"Successfully get region"`
+	Data       RegionResponse `json:"data"`
+}
+
+type RegionCreatedResponse struct {
+	StatusCode int            `json:"status_code" example:"201"`
+	Message    string         `json:"message" example:"Successfully to created a region"`
+	Data       RegionResponse `json:"data"`
+}
+
+type HotelSearchFacetStatusOKResponse struct {
+	StatusCode int                      `json:"status_code" example:"200"`
+	Message    string                   `json:"message" example:"Successfully get hotel search facets"`
+	Data       HotelSearchFacetResponse `json:"data"`
+}
+
+type GetAllOrderStatusHistoryStatusOKResponse struct {
+	StatusCode int                          `json:"status_code" example:"200"`
+	Message    string                       `json:"message" example:"Successfully get order status history"`
+	Data       []OrderStatusHistoryResponse `json:"data"`
+}
+
+type GetAllRefundPolicyStatusOKResponse struct {
+	StatusCode int                    `json:"status_code" example:"200"`
+	Message    string                 `json:"message" example:"Successfully get refund policies"`
+	Data       []RefundPolicyResponse `json:"data"`
+}
+
+type RefundPolicyStatusOKResponse struct {
+	StatusCode int                  `json:"status_code" example:"200"`
+	Message    string               `json:"message" example:"Successfully updated refund policy"`
+	Data       RefundPolicyResponse `json:"data"`
+}
+
+type TicketRefundQuoteStatusOKResponse struct {
+	StatusCode int                       `json:"status_code" example:"200"`
+	Message    string                    `json:"message" example:"Successfully get refund quote"`
+	Data       TicketRefundQuoteResponse `json:"data"`
+}
+
+type GetAllTicketRefundStatusOKResponse struct {
+	StatusCode int                    `json:"status_code" example:"200"`
+	Message    string                 `json:"message" example:"Successfully get ticket refunds"`
+	Data       []TicketRefundResponse `json:"data"`
+	Meta       helpers.Meta           `json:"meta"`
+}
+
+type TicketRefundStatusOKResponse struct {
+	StatusCode int                  `json:"status_code" example:"200"`
+	Message    string               `json:"message" example:"Successfully approved ticket refund"`
+	Data       TicketRefundResponse `json:"data"`
+}
+
+type TicketRefundCreatedResponse struct {
+	StatusCode int                  `json:"status_code" example:"201"`
+	Message    string               `json:"message" example:"Successfully requested ticket refund"`
+	Data       TicketRefundResponse `json:"data"`
+}
+
+type GetAllHotelRefundStatusOKResponse struct {
+	StatusCode int                   `json:"status_code" example:"200"`
+	Message    string                `json:"message" example:"Successfully get hotel refunds"`
+	Data       []HotelRefundResponse `json:"data"`
+	Meta       helpers.Meta          `json:"meta"`
+}
+
+type HotelRefundStatusOKResponse struct {
+	StatusCode int                 `json:"status_code" example:"200"`
+	Message    string              `json:"message" example:"Successfully approved hotel refund"`
+	Data       HotelRefundResponse `json:"data"`
+}
+
+type HotelRefundCreatedResponse struct {
+	StatusCode int                 `json:"status_code" example:"201"`
+	Message    string              `json:"message" example:"Successfully requested hotel refund"`
+	Data       HotelRefundResponse `json:"data"`
+}
+
+type TicketRescheduleQuoteStatusOKResponse struct {
+	StatusCode int                           `json:"status_code" example:"200"`
+	Message    string                        `json:"message" example:"Successfully get reschedule quote"`
+	Data       TicketRescheduleQuoteResponse `json:"data"`
+}
+
+type GetAllTicketRescheduleStatusOKResponse struct {
+	StatusCode int                        `json:"status_code" example:"200"`
+	Message    string                     `json:"message" example:"Successfully get ticket reschedules"`
+	Data       []TicketRescheduleResponse `json:"data"`
+	Meta       helpers.Meta               `json:"meta"`
+}
+
+type TicketRescheduleStatusOKResponse struct {
+	StatusCode int                      `json:"status_code" example:"200"`
+	Message    string                   `json:"message" example:"Successfully updated ticket reschedule"`
+	Data       TicketRescheduleResponse `json:"data"`
+}
+
+type TicketRescheduleCreatedResponse struct {
+	StatusCode int                      `json:"status_code" example:"201"`
+	Message    string                   `json:"message" example:"Successfully rescheduled ticket"`
+	Data       TicketRescheduleResponse `json:"data"`
+}
+
+type BoardingCheckInStatusOKResponse struct {
+	StatusCode int                     `json:"status_code" example:"200"`
+	Message    string                  `json:"message" example:"Successfully checked in passenger"`
+	Data       BoardingCheckInResponse `json:"data"`
+}
+
+type GetAllSavedPassengerStatusOKResponse struct {
+	StatusCode int                      `json:"status_code" example:"200"`
+	Message    string                   `json:"message" example:"Successfully get saved passengers"`
+	Data       []SavedPassengerResponse `json:"data"`
+	Meta       helpers.Meta             `json:"meta"`
+}
+
+type SavedPassengerStatusOKResponse struct {
+	StatusCode int                    `json:"status_code" example:"200"`
+	Message    string                 `json:"message" example:"Successfully get saved passenger"`
+	Data       SavedPassengerResponse `json:"data"`
+}
+
+type SavedPassengerCreeatedResponse struct {
+	StatusCode int                    `json:"status_code" example:"201"`
+	Message    string                 `json:"message" example:"Successfully created saved passenger"`
+	Data       SavedPassengerResponse `json:"data"`
+}
+
+type GetAllTrainSeatHoldStatusOKResponse struct {
+	StatusCode int                     `json:"status_code" example:"200"`
+	Message    string                  `json:"message" example:"Successfully get train seat holds"`
+	Data       []TrainSeatHoldResponse `json:"data"`
+}
+
+type TrainSeatHoldCreatedResponse struct {
+	StatusCode int                   `json:"status_code" example:"201"`
+	Message    string                `json:"message" example:"Successfully to created a train seat hold"`
+	Data       TrainSeatHoldResponse `json:"data"`
+}
+
+type GetAllTrainWaitlistStatusOKResponse struct {
+	StatusCode int                     `json:"status_code" example:"200"`
+	Message    string                  `json:"message" example:"Successfully get train waitlists"`
+	Data       []TrainWaitlistResponse `json:"data"`
+}
+
+type TrainWaitlistCreatedResponse struct {
+	StatusCode int                   `json:"status_code" example:"201"`
+	Message    string                `json:"message" example:"Successfully joined the train waitlist"`
+	Data       TrainWaitlistResponse `json:"data"`
+}
+
 type TrainCreeatedResponses struct {
 	StatusCode int            `json:"status_code" example:"201"`
 	Message    string         `json:"message" example:"Successfully created train"`
@@ -16,25 +16,40 @@ type TicketOrderInput struct {
 }
 
 type TravelerDetailInput struct {
-	Title        string `form:"title" json:"title" example:"Saudara"`
-	FullName     string `form:"full_name" json:"full_name" example:"Mochammad Hanif"`
-	IDCardNumber string `form:"id_card_number" json:"id_card_number" example:"1902389012801211"`
+	// SavedPassengerID fills the traveler from the address book of the user instead of the name and ID fields.
+	SavedPassengerID uint   `form:"saved_passenger_id" json:"saved_passenger_id,omitempty" example:"0"`
+	Title            string `form:"title" json:"title" example:"Saudara"`
+	FullName         string `form:"full_name" json:"full_name" example:"Mochammad Hanif"`
+	IDType           string `form:"id_type" json:"id_type" example:"ktp"`
+	IDCardNumber     string `form:"id_card_number" json:"id_card_number" example:"3201010101900001"`
+	BirthDate        string `form:"birth_date" json:"birth_date" example:"1990-01-01"`
+	PassengerType    string `form:"passenger_type" json:"passenger_type" example:"adult"`
+	// AccompaniedBy is the index in traveler_detail of the adult an infant rides with, infants without
+	// one are placed with the first adult who has no infant yet.
+	AccompaniedBy *int `form:"accompanied_by" json:"accompanied_by,omitempty" example:"0"`
 }
 
 type TravelerDetailResponse struct {
-	ID           int    `form:"traveler_detail_id" json:"traveler_detail_id" example:"1"`
-	Title        string `form:"title" json:"title" example:"Saudara"`
-	FullName     string `form:"full_name" json:"full_name" example:"Mochammad Hanif"`
-	IDCardNumber string `form:"id_card_number" json:"id_card_number" example:"1902389012801211"`
+	ID            int    `form:"traveler_detail_id" json:"traveler_detail_id" example:"1"`
+	Title         string `form:"title" json:"title" example:"Saudara"`
+	FullName      string `form:"full_name" json:"full_name" example:"Mochammad Hanif"`
+	IDCardNumber  string `form:"id_card_number" json:"id_card_number" example:"1902389012801211"`
+	PassengerType string `form:"passenger_type" json:"passenger_type,omitempty" example:"adult"`
 }
 
 type TicketTravelerDetailInput struct {
+	// TravelerIndex is the index in traveler_detail of the passenger taking this seat, entries without one
+	// go to the passengers with a seat in order. A single entry is booked for the whole group.
+	TravelerIndex *int `form:"traveler_index" json:"traveler_index,omitempty" example:"0"`
 	// TrainID              int    `form:"train_id" json:"train_id" example:"1"`
-	TrainCarriageID      int    `form:"train_carriage_id" json:"train_carriage_id"`
+	TrainCarriageID int `form:"train_carriage_id" json:"train_carriage_id"`
+	// TrainSeatID left empty lets the order pick seats next to the rest of the group.
 	TrainSeatID          int    `form:"train_seat_id" json:"train_seat_id"`
 	StationOriginID      int    `form:"station_origin_id" json:"station_origin_id"`
 	StationDestinationID int    `form:"station_destination_id" json:"station_destination_id"`
 	Date                 string `form:"date" json:"date" example:"2023-05-31"`
+	// Legs books a transfer itinerary, one entry per train in travel order.
+	Legs []TicketTravelerDetailInput `form:"legs" json:"legs,omitempty"`
 }
 
 type TicketOrderResponse struct {
@@ -79,6 +94,7 @@ type TicketTravelerDetailResponse struct {
 	StationDestination     StationResponseSimply  `json:"station_destination"`
 	Date                   time.Time              `json:"date" example:"2023-05-31"`
 	BoardingTicketCode     string                 `json:"boarding_ticket_code" example:"RANDOMBOARDINGTICKETCODE123"`
+	AccompaniedByID        int                    `json:"accompanied_by_ticket_traveler_detail_id,omitempty" example:"0"`
 }
 
 type TicketTravelerDetailOrderResponse struct {
@@ -0,0 +1,69 @@
+package dtos
+
+import "time"
+
+type RefundPolicyRuleInput struct {
+	MinHoursBefore int `json:"min_hours_before" form:"min_hours_before" example:"24"`
+	RefundPercent  int `json:"refund_percent" form:"refund_percent" example:"75"`
+}
+
+type RefundPolicyInput struct {
+	Class      string                  `json:"class" form:"class" example:"Ekonomi"`
+	Refundable bool                    `json:"refundable" form:"refundable" example:"true"`
+	Rules      []RefundPolicyRuleInput `json:"rules" form:"rules"`
+}
+
+type RefundPolicyRuleResponse struct {
+	MinHoursBefore int `json:"min_hours_before" example:"24"`
+	RefundPercent  int `json:"refund_percent" example:"75"`
+}
+
+type RefundPolicyResponse struct {
+	RefundPolicyID uint                       `json:"refund_policy_id" example:"1"`
+	Class          string                     `json:"class" example:"Ekonomi"`
+	Refundable     bool                       `json:"refundable" example:"true"`
+	Rules          []RefundPolicyRuleResponse `json:"rules"`
+	CreatedAt      time.Time                  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt      time.Time                  `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type TicketRefundInput struct {
+	TicketOrderID uint   `json:"ticket_order_id" form:"ticket_order_id" example:"1"`
+	Reason        string `json:"reason" form:"reason" example:"Change of plans"`
+}
+
+type TicketRefundProcessInput struct {
+	Note string `json:"note" form:"note" example:"Transferred to the original account"`
+}
+
+type TicketRefundTravelerResponse struct {
+	TicketTravelerDetailID uint   `json:"ticket_traveler_detail_id" example:"1"`
+	Class                  string `json:"class" example:"Ekonomi"`
+	TrainPrice             int    `json:"train_price" example:"50000"`
+	HoursBeforeDeparture   int    `json:"hours_before_departure" example:"48"`
+	RefundPercent          int    `json:"refund_percent" example:"75"`
+	RefundAmount           int    `json:"refund_amount" example:"37500"`
+}
+
+type TicketRefundQuoteResponse struct {
+	TicketOrderID uint                           `json:"ticket_order_id" example:"1"`
+	TotalAmount   int                            `json:"total_amount" example:"50000"`
+	RefundAmount  int                            `json:"refund_amount" example:"37500"`
+	Refundable    bool                           `json:"refundable" example:"true"`
+	Travelers     []TicketRefundTravelerResponse `json:"travelers"`
+}
+
+type TicketRefundResponse struct {
+	TicketRefundID     uint       `json:"ticket_refund_id" example:"1"`
+	TicketOrderID      uint       `json:"ticket_order_id" example:"1"`
+	UserID             uint       `json:"user_id" example:"1"`
+	Amount             int        `json:"amount" example:"37500"`
+	Reason             string     `json:"reason" example:"Change of plans"`
+	Status             string     `json:"status" example:"pending"`
+	ProcessedBy        uint       `json:"processed_by,omitempty" example:"1"`
+	ProcessedAt        *time.Time `json:"processed_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	Note               string     `json:"note,omitempty" example:"Transferred to the original account"`
+	TicketRescheduleID uint       `json:"ticket_reschedule_id,omitempty" example:"0"`
+	CreatedAt          time.Time  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt          time.Time  `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
@@ -0,0 +1,48 @@
+package dtos
+
+import "time"
+
+type TicketRescheduleInput struct {
+	TicketTravelerDetailID uint   `json:"ticket_traveler_detail_id" form:"ticket_traveler_detail_id" example:"1"`
+	TrainCarriageID        uint   `json:"train_carriage_id" form:"train_carriage_id" example:"2"`
+	TrainSeatID            uint   `json:"train_seat_id" form:"train_seat_id" example:"12"`
+	Date                   string `json:"date" form:"date" example:"2023-06-25"`
+	PaymentID              int    `json:"payment_id" form:"payment_id" example:"1"`
+}
+
+type TicketRescheduleQuoteResponse struct {
+	TicketTravelerDetailID uint   `json:"ticket_traveler_detail_id" example:"1"`
+	TrainID                uint   `json:"train_id" example:"1"`
+	TrainCarriageID        uint   `json:"train_carriage_id" example:"2"`
+	TrainSeatID            uint   `json:"train_seat_id" example:"12"`
+	Date                   string `json:"date" example:"2023-06-25"`
+	OldTrainPrice          int    `json:"old_train_price" example:"50000"`
+	NewTrainPrice          int    `json:"new_train_price" example:"65000"`
+	FareDifference         int    `json:"fare_difference" example:"15000"`
+	ExtraPayment           int    `json:"extra_payment" example:"15000"`
+	Credit                 int    `json:"credit" example:"0"`
+}
+
+type TicketRescheduleResponse struct {
+	TicketRescheduleID     uint       `json:"ticket_reschedule_id" example:"1"`
+	TicketOrderID          uint       `json:"ticket_order_id" example:"1"`
+	TicketTravelerDetailID uint       `json:"ticket_traveler_detail_id" example:"1"`
+	OldTrainID             uint       `json:"old_train_id" example:"1"`
+	OldTrainCarriageID     uint       `json:"old_train_carriage_id" example:"1"`
+	OldTrainSeatID         uint       `json:"old_train_seat_id" example:"5"`
+	OldDate                time.Time  `json:"old_date" example:"2023-06-20T00:00:00+07:00"`
+	OldTrainPrice          int        `json:"old_train_price" example:"50000"`
+	OldBoardingTicketCode  string     `json:"old_boarding_ticket_code" example:"boarding-ticket-0d5e0b4a-4f0a-4a64-9f3e-6a5b0c0f8f10"`
+	NewTrainID             uint       `json:"new_train_id" example:"1"`
+	NewTrainCarriageID     uint       `json:"new_train_carriage_id" example:"2"`
+	NewTrainSeatID         uint       `json:"new_train_seat_id" example:"12"`
+	NewDate                time.Time  `json:"new_date" example:"2023-06-25T00:00:00+07:00"`
+	NewTrainPrice          int        `json:"new_train_price" example:"65000"`
+	NewBoardingTicketCode  string     `json:"new_boarding_ticket_code,omitempty" example:"boarding-ticket-6c2f3f8e-2b9d-4a3b-8d3c-0c1f5e9a7b21"`
+	FareDifference         int        `json:"fare_difference" example:"15000"`
+	PaymentID              int        `json:"payment_id,omitempty" example:"1"`
+	Status                 string     `json:"status" example:"unpaid"`
+	ExpiredAt              *time.Time `json:"expired_at,omitempty" example:"2023-05-17T15:22:16.504+07:00"`
+	CreatedAt              time.Time  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt              time.Time  `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
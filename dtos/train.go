@@ -15,9 +15,12 @@ type TrainResponse struct {
 	Name            string                    `json:"name" example:"Bengawan"`
 	Class           string                    `json:"class" example:"Ekonomi"`
 	Price           int                       `json:"price" example:"50000"`
+	Fare            *TrainFareQuoteResponse   `json:"fare,omitempty"`
 	Route           []TrainStationResponse    `json:"route"`
 	TrainCarriage   *[]TrainCarriageResponses `json:"train_carriage,omitempty"`
 	TrainCarriageID uint                      `json:"train_carriage_id,omitempty"`
+	DepartureAt     *time.Time                `json:"departure_at,omitempty" example:"2023-06-01T08:00:00+07:00"`
+	ArrivalAt       *time.Time                `json:"arrival_at,omitempty" example:"2023-06-01T15:30:00+07:00"`
 	Status          string                    `json:"status" example:"available"`
 	CreatedAt       time.Time                 `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
 	UpdatedAt       time.Time                 `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
@@ -29,6 +32,7 @@ type TrainResponse2 struct {
 	Name          string                    `json:"name" example:"Bengawan"`
 	Class         string                    `json:"class" example:"Ekonomi"`
 	Price         int                       `json:"price" example:"50000"`
+	Fare          *TrainFareQuoteResponse   `json:"fare,omitempty"`
 	Route         []TrainStationResponse    `json:"route"`
 	TrainCarriage *[]TrainCarriageResponses `json:"train_carriage,omitempty"`
 	Status        string                    `json:"status" example:"available"`
@@ -0,0 +1,63 @@
+package dtos
+
+type TrainFareInput struct {
+	Class          string `json:"class" form:"class" example:"Ekonomi"`
+	Basis          string `json:"basis" form:"basis" example:"stop"`
+	BaseFare       int    `json:"base_fare" form:"base_fare" example:"20000"`
+	RatePerUnit    int    `json:"rate_per_unit" form:"rate_per_unit" example:"10000"`
+	SeniorDiscount int    `json:"senior_discount" form:"senior_discount" example:"20"`
+	ChildDiscount  int    `json:"child_discount" form:"child_discount" example:"0"`
+	InfantDiscount int    `json:"infant_discount" form:"infant_discount" example:"100"`
+}
+
+type TrainFareRuleResponse struct {
+	TrainFareID    uint   `json:"train_fare_id" example:"1"`
+	Class          string `json:"class" example:"Ekonomi"`
+	Basis          string `json:"basis" example:"stop"`
+	BaseFare       int    `json:"base_fare" example:"20000"`
+	RatePerUnit    int    `json:"rate_per_unit" example:"10000"`
+	SeniorDiscount int    `json:"senior_discount" example:"20"`
+	ChildDiscount  int    `json:"child_discount" example:"0"`
+	InfantDiscount int    `json:"infant_discount" example:"100"`
+}
+
+type TrainFarePeakInput struct {
+	DateStart        string `json:"date_start" form:"date_start" example:"2023-06-20"`
+	DateEnd          string `json:"date_end" form:"date_end" example:"2023-06-30"`
+	SurchargePercent int    `json:"surcharge_percent" form:"surcharge_percent" example:"25"`
+	Note             string `json:"note" form:"note" example:"Lebaran"`
+}
+
+type TrainFarePeakResponse struct {
+	TrainFarePeakID  uint   `json:"train_fare_peak_id" example:"1"`
+	DateStart        string `json:"date_start" example:"2023-06-20"`
+	DateEnd          string `json:"date_end" example:"2023-06-30"`
+	SurchargePercent int    `json:"surcharge_percent" example:"25"`
+	Note             string `json:"note" example:"Lebaran"`
+}
+
+type TrainFareAdvanceInput struct {
+	MinDaysBefore   int `json:"min_days_before" form:"min_days_before" example:"30"`
+	DiscountPercent int `json:"discount_percent" form:"discount_percent" example:"10"`
+}
+
+type TrainFareAdvanceResponse struct {
+	TrainFareAdvanceID uint `json:"train_fare_advance_id" example:"1"`
+	MinDaysBefore      int  `json:"min_days_before" example:"30"`
+	DiscountPercent    int  `json:"discount_percent" example:"10"`
+}
+
+type TrainFareResponse struct {
+	TrainID  uint                       `json:"train_id" example:"1"`
+	Fares    []TrainFareRuleResponse    `json:"fares"`
+	Peaks    []TrainFarePeakResponse    `json:"peaks"`
+	Advances []TrainFareAdvanceResponse `json:"advances"`
+}
+
+type TrainFareQuoteResponse struct {
+	BaseFare          int `json:"base_fare" example:"50000"`
+	PeakSurcharge     int `json:"peak_surcharge" example:"0"`
+	AdvanceDiscount   int `json:"advance_discount" example:"0"`
+	PassengerDiscount int `json:"passenger_discount" example:"0"`
+	Total             int `json:"total" example:"50000"`
+}
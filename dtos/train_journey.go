@@ -0,0 +1,34 @@
+package dtos
+
+import "time"
+
+type TrainJourneyResponse struct {
+	Transfer      int                       `json:"transfer" example:"1"`
+	DepartureTime string                    `json:"departure_time" example:"08:00"`
+	ArrivalTime   string                    `json:"arrival_time" example:"15:30"`
+	DepartureAt   time.Time                 `json:"departure_at" example:"2023-06-01T08:00:00+07:00"`
+	ArrivalAt     time.Time                 `json:"arrival_at" example:"2023-06-01T15:30:00+07:00"`
+	TotalDuration int                       `json:"total_duration" example:"450"`
+	TotalFare     int                       `json:"total_fare" example:"150000"`
+	Legs          []TrainJourneyLegResponse `json:"legs"`
+}
+
+type TrainJourneyLegResponse struct {
+	TrainID            uint                           `json:"train_id" example:"1"`
+	CodeTrain          string                         `json:"code_train" example:"TRAIN001"`
+	Name               string                         `json:"name" example:"Bengawan"`
+	Date               string                         `json:"date" example:"2023-06-01"`
+	StationOrigin      StationResponseSimply          `json:"station_origin"`
+	StationDestination StationResponseSimply          `json:"station_destination"`
+	TrainCarriageID    uint                           `json:"train_carriage_id" example:"1"`
+	Class              string                         `json:"class" example:"Ekonomi"`
+	Price              int                            `json:"price" example:"50000"`
+	TrainCarriage      []TrainJourneyCarriageResponse `json:"train_carriage"`
+	ConnectionTime     int                            `json:"connection_time,omitempty" example:"45"`
+}
+
+type TrainJourneyCarriageResponse struct {
+	TrainCarriageID uint   `json:"train_carriage_id" example:"1"`
+	Class           string `json:"class" example:"Ekonomi"`
+	Price           int    `json:"price" example:"50000"`
+}
@@ -0,0 +1,31 @@
+package dtos
+
+import "time"
+
+type TrainScheduleInput struct {
+	OperatingDays  []string `json:"operating_days" form:"operating_days" example:"monday,friday"`
+	EffectiveFrom  string   `json:"effective_from" form:"effective_from" example:"2023-06-01"`
+	EffectiveUntil string   `json:"effective_until" form:"effective_until" example:"2023-12-31"`
+}
+
+type TrainScheduleResponse struct {
+	TrainID        uint                             `json:"train_id" example:"1"`
+	OperatingDays  []string                         `json:"operating_days" example:"monday,friday"`
+	EffectiveFrom  string                           `json:"effective_from" example:"2023-06-01"`
+	EffectiveUntil string                           `json:"effective_until" example:"2023-12-31"`
+	Exceptions     []TrainScheduleExceptionResponse `json:"exceptions"`
+	UpdatedAt      time.Time                        `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type TrainScheduleExceptionInput struct {
+	Date      string `json:"date" form:"date" example:"2023-12-25"`
+	IsRunning bool   `json:"is_running" form:"is_running" example:"false"`
+	Note      string `json:"note" form:"note" example:"Christmas"`
+}
+
+type TrainScheduleExceptionResponse struct {
+	TrainScheduleExceptionID uint   `json:"train_schedule_exception_id" example:"1"`
+	Date                     string `json:"date" example:"2023-12-25"`
+	IsRunning                bool   `json:"is_running" example:"false"`
+	Note                     string `json:"note" example:"Christmas"`
+}
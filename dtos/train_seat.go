@@ -6,9 +6,14 @@ type TrainSeatResponse struct {
 }
 
 type TrainSeatAvailableResponse struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name" example:"A1"`
-	Available bool   `json:"available" example:"true"`
+	ID         int    `json:"id"`
+	Name       string `json:"name" example:"A1"`
+	Row        int    `json:"row" example:"1"`
+	Column     int    `json:"column" example:"1"`
+	IsWindow   bool   `json:"is_window" example:"true"`
+	IsAisle    bool   `json:"is_aisle" example:"false"`
+	IsDisabled bool   `json:"is_disabled" example:"false"`
+	Available  bool   `json:"available" example:"true"`
 }
 
 type TrainSeatResponseSimply struct {
@@ -0,0 +1,20 @@
+package dtos
+
+import "time"
+
+type TrainSeatHoldInput struct {
+	TrainCarriageID      uint   `json:"train_carriage_id" form:"train_carriage_id" example:"1"`
+	TrainSeatID          uint   `json:"train_seat_id" form:"train_seat_id" example:"1"`
+	StationOriginID      uint   `json:"station_origin_id" form:"station_origin_id" example:"1"`
+	StationDestinationID uint   `json:"station_destination_id" form:"station_destination_id" example:"2"`
+	Date                 string `json:"date" form:"date" example:"2023-06-01"`
+}
+
+type TrainSeatHoldResponse struct {
+	TrainSeatHoldID    uint                  `json:"train_seat_hold_id" example:"1"`
+	Train              TrainResponsesSimply  `json:"train"`
+	StationOrigin      StationResponseSimply `json:"station_origin"`
+	StationDestination StationResponseSimply `json:"station_destination"`
+	Date               string                `json:"date" example:"2023-06-01"`
+	ExpiredAt          time.Time             `json:"expired_at" example:"2023-05-17T15:22:16.504+07:00"`
+}
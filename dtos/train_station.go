@@ -3,10 +3,14 @@ package dtos
 type TrainStationInput struct {
 	StationID  uint   `json:"station_id" form:"station_id" example:"1"`
 	ArriveTime string `json:"arrive_time" form:"arrive_time" example:"00:00"`
+	DistanceKm int    `json:"distance_km" form:"distance_km" example:"0"`
 }
 
 type TrainStationResponse struct {
 	StationID  uint         `json:"station_id" example:"1"`
 	Station    StationInput `json:"station"`
 	ArriveTime string       `json:"arrive_time"`
+	Sequence   int          `json:"sequence" example:"1"`
+	DayOffset  int          `json:"day_offset" example:"0"`
+	DistanceKm int          `json:"distance_km" example:"0"`
 }
@@ -0,0 +1,24 @@
+package dtos
+
+import "time"
+
+type TrainWaitlistInput struct {
+	TrainID              uint   `json:"train_id" form:"train_id" example:"1"`
+	Class                string `json:"class" form:"class" example:"Ekonomi"`
+	StationOriginID      uint   `json:"station_origin_id" form:"station_origin_id" example:"1"`
+	StationDestinationID uint   `json:"station_destination_id" form:"station_destination_id" example:"2"`
+	Date                 string `json:"date" form:"date" example:"2023-06-01"`
+}
+
+type TrainWaitlistResponse struct {
+	TrainWaitlistID    uint                  `json:"train_waitlist_id" example:"1"`
+	Train              TrainResponsesSimply  `json:"train"`
+	StationOrigin      StationResponseSimply `json:"station_origin"`
+	StationDestination StationResponseSimply `json:"station_destination"`
+	Date               string                `json:"date" example:"2023-06-01"`
+	Status             string                `json:"status" example:"waiting"`
+	// Position counts the users ahead in the queue while the entry is waiting.
+	Position       int        `json:"position" example:"0"`
+	OfferExpiredAt *time.Time `json:"offer_expired_at,omitempty" example:"2023-05-17T15:37:16.504+07:00"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
@@ -0,0 +1,125 @@
+package helpers
+
+import (
+	"back-end-golang/configs"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// BoardingPass is everything printed on the boarding pass of one passenger.
+type BoardingPass struct {
+	TicketOrderCode    string
+	BoardingTicketCode string
+	FullName           string
+	PassengerType      string
+	TrainName          string
+	CodeTrain          string
+	Class              string
+	TrainCarriage      string
+	TrainSeat          string
+	StationOrigin      string
+	StationDestination string
+	DepartureDate      string
+	DepartureTime      string
+	ArrivalDate        string
+	ArrivalTime        string
+}
+
+// SignBoardingTicketCode appends an HMAC of the code, so a gate can tell a printed code from a guessed one.
+func SignBoardingTicketCode(boardingTicketCode string) string {
+	return boardingTicketCode + "." + boardingTicketCodeSignature(boardingTicketCode)
+}
+
+// VerifyBoardingTicketCode returns the boarding ticket code of a signed code and whether the signature matches.
+func VerifyBoardingTicketCode(signedBoardingTicketCode string) (string, bool) {
+	index := strings.LastIndex(signedBoardingTicketCode, ".")
+	if index < 1 {
+		return "", false
+	}
+	boardingTicketCode := signedBoardingTicketCode[:index]
+	signature := signedBoardingTicketCode[index+1:]
+	if !hmac.Equal([]byte(signature), []byte(boardingTicketCodeSignature(boardingTicketCode))) {
+		return "", false
+	}
+	return boardingTicketCode, true
+}
+
+func boardingTicketCodeSignature(boardingTicketCode string) string {
+	mac := hmac.New(sha256.New, []byte(configs.EnvBoardingPassSecret()))
+	mac.Write([]byte(boardingTicketCode))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewBoardingPassPDF renders one A5 landscape page per boarding pass, each with a QR code of the signed
+// boarding ticket code.
+func NewBoardingPassPDF(boardingPasses []BoardingPass) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A5", "")
+	pdf.SetTitle("Boarding Pass", false)
+	// Core fonts are cp1252, names and stations are translated from UTF-8 before they are written
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for i, boardingPass := range boardingPasses {
+		qrCode, err := qrcode.Encode(SignBoardingTicketCode(boardingPass.BoardingTicketCode), qrcode.Medium, 512)
+		if err != nil {
+			return nil, err
+		}
+		qrCodeName := fmt.Sprintf("qr-%d", i)
+		pdf.RegisterImageOptionsReader(qrCodeName, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qrCode))
+
+		pdf.AddPage()
+
+		pdf.SetFillColor(13, 71, 161)
+		pdf.Rect(0, 0, 210, 22, "F")
+		pdf.SetTextColor(255, 255, 255)
+		pdf.SetFont("Helvetica", "B", 18)
+		pdf.SetXY(12, 6)
+		pdf.CellFormat(120, 10, "BOARDING PASS", "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetXY(120, 6)
+		pdf.CellFormat(78, 10, boardingPass.TicketOrderCode, "", 0, "R", false, 0, "")
+
+		pdf.SetTextColor(0, 0, 0)
+		boardingPassField(pdf, translate, 12, 30, "Passenger", boardingPass.FullName+" ("+boardingPass.PassengerType+")")
+		boardingPassField(pdf, translate, 12, 46, "Train", boardingPass.TrainName+" "+boardingPass.CodeTrain)
+		boardingPassField(pdf, translate, 80, 46, "Class", boardingPass.Class)
+		boardingPassField(pdf, translate, 12, 62, "Carriage", boardingPass.TrainCarriage)
+		boardingPassField(pdf, translate, 80, 62, "Seat", boardingPass.TrainSeat)
+		boardingPassField(pdf, translate, 12, 78, "From", boardingPass.StationOrigin)
+		boardingPassField(pdf, translate, 80, 78, "Departure", boardingPass.DepartureDate+" "+boardingPass.DepartureTime)
+		boardingPassField(pdf, translate, 12, 94, "To", boardingPass.StationDestination)
+		boardingPassField(pdf, translate, 80, 94, "Arrival", boardingPass.ArrivalDate+" "+boardingPass.ArrivalTime)
+
+		pdf.ImageOptions(qrCodeName, 145, 32, 55, 55, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.SetXY(140, 90)
+		pdf.MultiCell(65, 3.5, boardingPass.BoardingTicketCode, "", "C", false)
+
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetXY(12, 130)
+		pdf.CellFormat(186, 5, "Show this boarding pass and your identity card at the gate.", "", 0, "L", false, 0, "")
+	}
+
+	var buffer bytes.Buffer
+	err := pdf.Output(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func boardingPassField(pdf *gofpdf.Fpdf, translate func(string) string, x, y float64, label, value string) {
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.CellFormat(60, 4, strings.ToUpper(label), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(60, 7, translate(value), "", 0, "L", false, 0, "")
+}
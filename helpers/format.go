@@ -15,3 +15,11 @@ func FormatStringToDate(stringDate string) (time.Time, error) {
 	return birthDateParse, err
 
 }
+
+func FormatTimeToMinutes(stringTime string) (int, error) {
+	timeParse, err := time.Parse("15:04", stringTime)
+	if err != nil {
+		return 0, err
+	}
+	return timeParse.Hour()*60 + timeParse.Minute(), nil
+}
@@ -0,0 +1,6 @@
+package helpers
+
+// ValidateCoordinate checks a latitude and a longitude given in decimal degrees.
+func ValidateCoordinate(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
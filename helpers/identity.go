@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	nikPattern      = regexp.MustCompile(`^[0-9]{16}$`)
+	passportPattern = regexp.MustCompile(`^[A-Z]{1,2}[0-9]{6,7}$`)
+)
+
+// ValidateNIK checks an Indonesian NIK, 16 digits made of the region code, the birth date (day plus 40 for
+// women) and a serial number that is never 0000.
+func ValidateNIK(nik string) bool {
+	if !nikPattern.MatchString(nik) {
+		return false
+	}
+
+	province, _ := strconv.Atoi(nik[0:2])
+	day, _ := strconv.Atoi(nik[6:8])
+	month, _ := strconv.Atoi(nik[8:10])
+	if province < 11 || province > 94 {
+		return false
+	}
+	if day > 40 {
+		day -= 40
+	}
+	if day < 1 || day > 31 || month < 1 || month > 12 {
+		return false
+	}
+	return nik[12:16] != "0000"
+}
+
+// ValidatePassportNumber checks an Indonesian passport number, one or two capital letters followed by six
+// or seven digits.
+func ValidatePassportNumber(passportNumber string) bool {
+	return passportPattern.MatchString(passportNumber)
+}
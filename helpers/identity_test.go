@@ -0,0 +1,34 @@
+package helpers
+
+import "testing"
+
+func TestValidateNIK(t *testing.T) {
+	tests := []struct {
+		name string
+		nik  string
+		want bool
+	}{
+		{"man", "3201010101900001", true},
+		{"woman born on the 1st", "3201014101900001", true},
+		{"last province code", "9401013112990001", true},
+		{"too short", "320101010190001", false},
+		{"too long", "32010101019000011", false},
+		{"letters", "32010101019000A1", false},
+		{"province below 11", "1001010101900001", false},
+		{"province above 94", "9501010101900001", false},
+		{"day zero", "3201010001900001", false},
+		{"day 32", "3201013201900001", false},
+		{"woman day above 71", "3201017201900001", false},
+		{"month zero", "3201010100900001", false},
+		{"month 13", "3201010113900001", false},
+		{"serial 0000", "3201010101900000", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateNIK(tt.nik); got != tt.want {
+				t.Errorf("ValidateNIK(%q) = %v, want %v", tt.nik, got, tt.want)
+			}
+		})
+	}
+}
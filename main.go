@@ -54,6 +54,21 @@ func main() {
 		panic(err)
 	}
 
+	err = configs.TrainStationSequenceSeeder(db)
+	if err != nil {
+		panic(err)
+	}
+
+	err = configs.HotelOrderRoomSeeder(db)
+	if err != nil {
+		panic(err)
+	}
+
+	err = configs.HotelRoomBookingSeeder(db)
+	if err != nil {
+		panic(err)
+	}
+
 	err = configs.AccountSeeder(db)
 	if err != nil {
 		panic(err)
@@ -92,7 +92,8 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-func RoleMiddleware(role string) echo.MiddlewareFunc {
+// RoleMiddleware lets the request through when the role of the token is one of the given roles.
+func RoleMiddleware(roles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			user := c.Get("user").(*jwt.Token)
@@ -102,8 +103,15 @@ func RoleMiddleware(role string) echo.MiddlewareFunc {
 			// fmt.Println("1", role)
 			// fmt.Println("2", userRole)
 
-			// Check if the user's role matches the required role
-			if userRole != role {
+			// Check if the user's role matches one of the required roles
+			allowed := false
+			for _, role := range roles {
+				if userRole == role {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
 				// Return an error response indicating unauthorized access
 				errorResponse := helpers.ErrorResponse{
 					StatusCode: http.StatusForbidden,
@@ -4,10 +4,16 @@ import "gorm.io/gorm"
 
 type Hotel struct {
 	gorm.Model
-	Name        string `form:"name" json:"name"`
-	Class       int    `form:"class" json:"class"`
-	Description string `form:"description" json:"description"`
-	PhoneNumber string `form:"phone_number" json:"phone_number"`
-	Email       string `form:"email" json:"email"`
-	Address     string `form:"address" json:"address"`
+	Name        string   `form:"name" json:"name"`
+	Class       int      `form:"class" json:"class"`
+	Description string   `form:"description" json:"description"`
+	PhoneNumber string   `form:"phone_number" json:"phone_number"`
+	Email       string   `form:"email" json:"email"`
+	Address     string   `form:"address" json:"address"`
+	Latitude    *float64 `form:"latitude" json:"latitude"`
+	Longitude   *float64 `form:"longitude" json:"longitude"`
+	ProvinceID  uint     `form:"province_id" json:"province_id" gorm:"default:0;index"`
+	RegencyID   uint     `form:"regency_id" json:"regency_id" gorm:"default:0;index"`
+	DistrictID  uint     `form:"district_id" json:"district_id" gorm:"default:0;index"`
+	PostalCode  string   `form:"postal_code" json:"postal_code"`
 }
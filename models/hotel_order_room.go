@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// HotelOrderRoom is a room line of a hotel order: Quantity rooms of one hotel room shared by its guests,
+// at Price for the whole stay. A line is canceled on its own or together with its order.
+type HotelOrderRoom struct {
+	gorm.Model
+	HotelOrderID   uint       `gorm:"index"`
+	HotelOrder     HotelOrder `gorm:"foreignKey:HotelOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	HotelRoomID    uint
+	HotelRoom      HotelRoom `gorm:"foreignKey:HotelRoomID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Quantity       int       `gorm:"default:1"`
+	QuantityAdult  int
+	QuantityInfant int
+	Price          int
+	Status         string `gorm:"type:ENUM('active', 'canceled', 'refund');default:'active'"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type HotelRefund struct {
+	gorm.Model
+	HotelOrderID uint
+	HotelOrder   HotelOrder `gorm:"foreignKey:HotelOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID       uint
+	User         User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Amount       int
+	Reason       string
+	Status       string `gorm:"type:ENUM('pending', 'approved', 'rejected');default:'pending'"`
+	ProcessedBy  uint   `gorm:"default:0"`
+	ProcessedAt  *time.Time
+	Note         string
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HotelRoomBooking takes Quantity rooms of a hotel room for one night of an order room line at the
+// nightly Price of one room. The rows of a room are its per-night inventory ledger, checked against
+// QuantityOfRoom.
+type HotelRoomBooking struct {
+	gorm.Model
+	HotelOrderID     uint       `gorm:"index"`
+	HotelOrder       HotelOrder `gorm:"foreignKey:HotelOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	HotelOrderRoomID uint       `gorm:"index"`
+	HotelRoomID      uint       `gorm:"index:idx_hotel_room_booking_night"`
+	HotelRoom        HotelRoom  `gorm:"foreignKey:HotelRoomID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Date             time.Time  `gorm:"type:DATE;index:idx_hotel_room_booking_night"`
+	Quantity         int        `gorm:"default:1"`
+	Price            int
+}
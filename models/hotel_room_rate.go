@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HotelRoomRate prices the nights of a hotel room falling between DateStart and DateEnd, either open
+// ended when empty, on the days of week listed in Weekdays (0 Sunday to 6 Saturday, every day when
+// empty). A Price of 0 keeps the room price and only applies MinimumStay.
+type HotelRoomRate struct {
+	gorm.Model
+	HotelRoomID uint
+	HotelRoom   HotelRoom  `gorm:"foreignKey:HotelRoomID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	DateStart   *time.Time `gorm:"type:DATE"`
+	DateEnd     *time.Time `gorm:"type:DATE"`
+	Weekdays    string     `gorm:"type:varchar(20)"`
+	Price       int        `gorm:"default:0"`
+	MinimumStay int        `gorm:"default:0"`
+	Note        string
+}
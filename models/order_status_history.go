@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+type OrderStatusHistory struct {
+	gorm.Model
+	OrderType  string `gorm:"type:ENUM('ticket', 'hotel')"`
+	OrderID    uint
+	FromStatus string
+	ToStatus   string
+	ChangedBy  uint
+	Note       string
+}
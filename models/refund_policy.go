@@ -0,0 +1,17 @@
+package models
+
+import "gorm.io/gorm"
+
+type RefundPolicy struct {
+	gorm.Model
+	Class      string `gorm:"type:varchar(255);unique"`
+	Refundable bool   `gorm:"default:true"`
+}
+
+type RefundPolicyRule struct {
+	gorm.Model
+	RefundPolicyID uint
+	RefundPolicy   RefundPolicy `gorm:"foreignKey:RefundPolicyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	MinHoursBefore int
+	RefundPercent  int
+}
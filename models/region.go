@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// Region is a province, a regency within a province or a district within a regency. Provinces have no
+// parent.
+type Region struct {
+	gorm.Model
+	ParentID uint   `gorm:"default:0;index"`
+	Level    string `gorm:"type:ENUM('province', 'regency', 'district')"`
+	Name     string
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SavedPassenger struct {
+	gorm.Model
+	UserID    uint
+	User      User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Title     string
+	FullName  string
+	IDType    string `gorm:"type:ENUM('ktp', 'passport', 'none');default:'ktp'"`
+	IDNumber  string
+	BirthDate *time.Time `gorm:"type:DATE"`
+}
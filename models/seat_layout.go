@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+type SeatLayout struct {
+	gorm.Model
+	Name             string
+	Class            string `gorm:"type:varchar(255)"`
+	Rows             int
+	Columns          int
+	AisleAfterColumn int
+	DisabledSeats    string
+}
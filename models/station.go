@@ -6,7 +6,12 @@ import (
 
 type Station struct {
 	gorm.Model
-	Origin  string
-	Name    string `gorm:"unique"`
-	Initial string
+	Origin     string
+	Name       string `gorm:"unique"`
+	Initial    string
+	Latitude   *float64
+	Longitude  *float64
+	ProvinceID uint `gorm:"default:0;index"`
+	RegencyID  uint `gorm:"default:0;index"`
+	DistrictID uint `gorm:"default:0;index"`
 }
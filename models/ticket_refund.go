@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TicketRefund struct {
+	gorm.Model
+	TicketOrderID uint
+	TicketOrder   TicketOrder `gorm:"foreignKey:TicketOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID        uint
+	User          User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Amount        int
+	Reason        string
+	Status        string `gorm:"type:ENUM('pending', 'approved', 'rejected');default:'pending'"`
+	ProcessedBy   uint   `gorm:"default:0"`
+	ProcessedAt   *time.Time
+	Note          string
+	// TicketRescheduleID is set when the refund pays out the credit of a reschedule to a cheaper fare,
+	// the order stays paid when it is approved
+	TicketRescheduleID uint `gorm:"default:0"`
+}
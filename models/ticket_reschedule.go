@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketReschedule moves one ticket of a paid order to another train, seat or date. The old ticket is
+// kept here so its boarding ticket code can be told apart from an unknown code after it is voided.
+type TicketReschedule struct {
+	gorm.Model
+	TicketOrderID          uint
+	TicketOrder            TicketOrder `gorm:"foreignKey:TicketOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TicketTravelerDetailID uint
+	TicketTravelerDetail   TicketTravelerDetail `gorm:"foreignKey:TicketTravelerDetailID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID                 uint
+	User                   User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	OldTrainID             uint
+	OldTrainCarriageID     uint
+	OldTrainSeatID         uint
+	OldDateOfDeparture     time.Time `gorm:"type:DATE"`
+	OldTrainPrice          int
+	OldBoardingTicketCode  string
+	NewTrainID             uint
+	NewTrainCarriageID     uint
+	NewTrainSeatID         uint
+	NewDateOfDeparture     time.Time `gorm:"type:DATE"`
+	NewServiceDate         time.Time `gorm:"type:DATE"`
+	NewTrainPrice          int
+	NewBoardingTicketCode  string
+	FareDifference         int
+	PaymentID              int
+	Status                 string `gorm:"type:ENUM('unpaid', 'paid', 'credited', 'canceled');default:'unpaid'"`
+	ExpiredAt              *time.Time
+}
@@ -25,6 +25,13 @@ type TicketTravelerDetail struct {
 	StationDestinationID uint
 	StationDestination   Station `gorm:"foreignKey:StationDestinationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	ArrivalTime          string
-	DateOfDeparture      time.Time `gorm:"type:DATE"`
+	OriginSequence       int        `gorm:"default:0"`
+	DestinationSequence  int        `gorm:"default:0"`
+	DateOfDeparture      time.Time  `gorm:"type:DATE"`
+	ServiceDate          *time.Time `gorm:"type:DATE"`
 	BoardingTicketCode   string
+	BoardedAt            *time.Time
+	BoardedBy            uint `gorm:"default:0"`
+	// AccompaniedByID is the ticket of the adult an infant rides with on the same seat
+	AccompaniedByID uint `gorm:"default:0"`
 }
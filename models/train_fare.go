@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrainFare struct {
+	gorm.Model
+	TrainID        uint
+	Train          Train  `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Class          string `gorm:"type:varchar(255)"`
+	Basis          string `gorm:"type:ENUM('stop', 'distance');default:'stop'"`
+	BaseFare       int
+	RatePerUnit    int
+	SeniorDiscount int `gorm:"default:0"`
+	ChildDiscount  int `gorm:"default:0"`
+	InfantDiscount int `gorm:"default:100"`
+}
+
+type TrainFarePeak struct {
+	gorm.Model
+	TrainID          uint
+	Train            Train     `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	DateStart        time.Time `gorm:"type:DATE"`
+	DateEnd          time.Time `gorm:"type:DATE"`
+	SurchargePercent int
+	Note             string
+}
+
+type TrainFareAdvance struct {
+	gorm.Model
+	TrainID         uint
+	Train           Train `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	MinDaysBefore   int
+	DiscountPercent int
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrainSchedule struct {
+	gorm.Model
+	TrainID        uint
+	Train          Train      `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	OperatingDays  string     `gorm:"default:'monday,tuesday,wednesday,thursday,friday,saturday,sunday'"`
+	EffectiveFrom  *time.Time `gorm:"type:DATE"`
+	EffectiveUntil *time.Time `gorm:"type:DATE"`
+}
+
+type TrainScheduleException struct {
+	gorm.Model
+	TrainID   uint
+	Train     Train     `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Date      time.Time `gorm:"type:DATE"`
+	IsRunning bool      `gorm:"default:false"`
+	Note      string
+}
@@ -4,6 +4,12 @@ import "gorm.io/gorm"
 
 type TrainSeat struct {
 	gorm.Model
-	Class string `gorm:"type:varchar(255)"`
-	Name  string
+	TrainCarriageID uint   `gorm:"default:0"`
+	Class           string `gorm:"type:varchar(255)"`
+	Name            string
+	Row             int  `gorm:"default:0"`
+	Column          int  `gorm:"default:0"`
+	IsWindow        bool `gorm:"default:false"`
+	IsAisle         bool `gorm:"default:false"`
+	IsDisabled      bool `gorm:"default:false"`
 }
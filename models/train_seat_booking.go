@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrainSeatBooking claims one stop-to-stop hop of a train seat for a ticket. The unique index makes
+// the database reject a second ticket on any hop of the same seat and service date.
+type TrainSeatBooking struct {
+	gorm.Model
+	TicketOrderID          uint
+	TicketOrder            TicketOrder `gorm:"foreignKey:TicketOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TicketTravelerDetailID uint
+	TicketTravelerDetail   TicketTravelerDetail `gorm:"foreignKey:TicketTravelerDetailID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TrainID                uint                 `gorm:"uniqueIndex:idx_train_seat_booking"`
+	TrainSeatID            uint                 `gorm:"uniqueIndex:idx_train_seat_booking"`
+	ServiceDate            time.Time            `gorm:"type:DATE;uniqueIndex:idx_train_seat_booking"`
+	Sequence               int                  `gorm:"uniqueIndex:idx_train_seat_booking"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrainSeatHold struct {
+	gorm.Model
+	UserID               uint
+	User                 User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TrainID              uint
+	Train                Train `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TrainCarriageID      uint
+	TrainCarriage        TrainCarriage `gorm:"foreignKey:TrainCarriageID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TrainSeatID          uint
+	TrainSeat            TrainSeat `gorm:"foreignKey:TrainSeatID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StationOriginID      uint
+	StationOrigin        Station `gorm:"foreignKey:StationOriginID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StationDestinationID uint
+	StationDestination   Station `gorm:"foreignKey:StationDestinationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	OriginSequence       int
+	DestinationSequence  int
+	DateOfDeparture      time.Time `gorm:"type:DATE"`
+	ServiceDate          time.Time `gorm:"type:DATE"`
+	ExpiredAt            time.Time
+}
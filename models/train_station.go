@@ -9,4 +9,7 @@ type TrainStation struct {
 	Train      Train   `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Station    Station `gorm:"foreignKey:StationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	ArriveTime string
+	Sequence   int `gorm:"default:0"`
+	DayOffset  int `gorm:"default:0"`
+	DistanceKm int `gorm:"default:0"`
 }
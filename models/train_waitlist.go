@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrainWaitlist is a place in the queue for a sold-out class of a train on a segment and service date.
+// An offered entry holds the seat of TrainCarriageID and TrainSeatID until OfferExpiredAt.
+type TrainWaitlist struct {
+	gorm.Model
+	UserID               uint
+	User                 User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TrainID              uint
+	Train                Train  `gorm:"foreignKey:TrainID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Class                string `gorm:"type:varchar(255)"`
+	StationOriginID      uint
+	StationOrigin        Station `gorm:"foreignKey:StationOriginID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StationDestinationID uint
+	StationDestination   Station `gorm:"foreignKey:StationDestinationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	OriginSequence       int
+	DestinationSequence  int
+	DateOfDeparture      time.Time `gorm:"type:DATE"`
+	ServiceDate          time.Time `gorm:"type:DATE"`
+	Status               string    `gorm:"type:ENUM('waiting', 'offered', 'booked', 'expired', 'canceled');default:'waiting'"`
+	TrainCarriageID      uint      `gorm:"default:0"`
+	TrainSeatID          uint      `gorm:"default:0"`
+	OfferExpiredAt       *time.Time
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -14,5 +16,8 @@ type TravelerDetail struct {
     HotelOrder    HotelOrder  `gorm:"foreignKey:HotelOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
     Title         string      `form:"title" json:"title"`
     FullName      string      `form:"full_name" json:"full_name"`
+    IDType        string      `gorm:"type:ENUM('ktp', 'passport', 'none');default:'ktp'" form:"id_type" json:"id_type"`
     IDCardNumber  *string     `gorm:"null" form:"id_card_number" json:"id_card_number"`
+    BirthDate     *time.Time  `gorm:"type:DATE" form:"birth_date" json:"birth_date"`
+    PassengerType string      `gorm:"default:'adult'" form:"passenger_type" json:"passenger_type"`
 }
@@ -15,5 +15,5 @@ type User struct {
 	BirthDate      *time.Time `gorm:"type:DATE"`
 	ProfilePicture string
 	Citizen        string
-	Role           string `gorm:"type:ENUM('user','admin')"`
+	Role           string `gorm:"type:ENUM('user','admin','staff')"`
 }
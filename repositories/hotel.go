@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"back-end-golang/models"
+	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -13,7 +15,47 @@ type HotelRepository interface {
 	CreateHotel(hotel models.Hotel) (models.Hotel, error)
 	UpdateHotel(hotel models.Hotel) (models.Hotel, error)
 	DeleteHotel(id uint) error
-	SearchHotelAvailable(page, limit int, address, name string) ([]models.Hotel, int, error)
+	SearchHotelAvailable(filter HotelSearchFilter, page, limit int) ([]HotelSearchRow, int, error)
+	CountSearchHotelsByRegion(filter HotelSearchFilter, level string) ([]HotelRegionCount, error)
+}
+
+// HotelSearchFilter narrows a hotel search. RegionID keeps hotels placed in
+// that region at any level. When Latitude and Longitude are set only hotels
+// with a location are returned, each with its distance from that point, and a
+// positive RadiusKm drops hotels farther away. A hotel is only found when one
+// of its rooms fits Guest and, with CheckIn and CheckOut set, has a room left
+// on every night of the stay; the price filters and sort use the cheapest of
+// those rooms.
+type HotelSearchFilter struct {
+	Address        string
+	Name           string
+	RegionID       uint
+	Latitude       *float64
+	Longitude      *float64
+	RadiusKm       float64
+	SortByDistance bool
+	Guest          int
+	CheckIn        string
+	CheckOut       string
+	MinimumPrice   int
+	MaximumPrice   int
+	Class          int
+	SortByPrice    string
+}
+
+// HotelSearchRow is a hotel found by a search, with its distance in kilometres
+// from the searched point if one was given and the price of its cheapest
+// matching room.
+type HotelSearchRow struct {
+	models.Hotel
+	DistanceKm     *float64
+	HotelRoomStart int
+}
+
+// HotelRegionCount is how many hotels a search finds in one region.
+type HotelRegionCount struct {
+	RegionID uint
+	Count    int
 }
 
 type hotelRepository struct {
@@ -71,45 +113,123 @@ func (r *hotelRepository) DeleteHotel(id uint) error {
 	return err
 }
 
-func (r *hotelRepository) SearchHotelAvailable(page, limit int, address, name string) ([]models.Hotel, int, error) {
-	var (
-		hotels []models.Hotel
-		count  int64
-		err    error
-	)
+// hotelSearchDistance is the great-circle distance in kilometres from the searched point to a hotel.
+// LEAST keeps ACOS in range when a hotel sits on the point.
+const hotelSearchDistance = "6371 * ACOS(LEAST(1, COS(RADIANS(?)) * COS(RADIANS(latitude)) * COS(RADIANS(longitude) - RADIANS(?)) + SIN(RADIANS(?)) * SIN(RADIANS(latitude))))"
+
+// hotelRegionColumns are the hotel columns placing a hotel in each level of the region master.
+var hotelRegionColumns = map[string]string{
+	"province": "province_id",
+	"regency":  "regency_id",
+	"district": "district_id",
+}
 
-	if address == "" && name == "" {
-		err = r.db.Find(&hotels).Count(&count).Error
+// searchHotelQuery applies the filters of a hotel search.
+// hotelRoomStartQuery prices a hotel from its cheapest room matching the guests and, for a stay, sold out
+// on none of its nights. It is NULL when no room matches.
+func hotelRoomStartQuery(filter HotelSearchFilter) (string, []interface{}) {
+	sql := "SELECT MIN(hr.discount_price) FROM hotel_rooms hr WHERE hr.hotel_id = hotels.id AND hr.deleted_at IS NULL"
+	var args []interface{}
+	if filter.Guest > 0 {
+		sql += " AND hr.number_of_guest >= ?"
+		args = append(args, filter.Guest)
 	}
-	if address != "" && name == "" {
-		err = r.db.Where("address LIKE ?", "%"+address+"%").Find(&hotels).Count(&count).Error
+	if filter.CheckIn != "" && filter.CheckOut != "" {
+		sql += ` AND hr.quantity_of_room > 0 AND NOT EXISTS (
+			SELECT 1
+			FROM hotel_room_bookings hrb
+			WHERE hrb.hotel_room_id = hr.id AND hrb.deleted_at IS NULL AND hrb.date >= ? AND hrb.date < ?
+				AND hrb.hotel_order_id NOT IN (SELECT id FROM hotel_orders WHERE status IN ?)
+			GROUP BY hrb.date
+			HAVING SUM(hrb.quantity) >= hr.quantity_of_room
+		)`
+		args = append(args, filter.CheckIn, filter.CheckOut, []string{"canceled", "refund"})
 	}
-	if address == "" && name != "" {
-		err = r.db.Where("name LIKE ?", "%"+name+"%").Find(&hotels).Count(&count).Error
+	return "(" + sql + ")", args
+}
+
+func (r *hotelRepository) searchHotelQuery(filter HotelSearchFilter) *gorm.DB {
+	query := r.db.Model(&models.Hotel{})
+	hotelRoomStart, hotelRoomStartArgs := hotelRoomStartQuery(filter)
+	query = query.Where(hotelRoomStart+" IS NOT NULL", hotelRoomStartArgs...)
+	if filter.MinimumPrice > 0 {
+		query = query.Where(hotelRoomStart+" >= ?", append(hotelRoomStartArgs, filter.MinimumPrice)...)
 	}
-	if address != "" && name != "" {
-		err = r.db.Where("name LIKE ? AND address LIKE ?", "%"+name+"%", "%"+address+"%").Find(&hotels).Count(&count).Error
+	if filter.MaximumPrice > 0 {
+		query = query.Where(hotelRoomStart+" <= ?", append(hotelRoomStartArgs, filter.MaximumPrice)...)
 	}
-
-	if err != nil {
-		return hotels, int(count), err
+	if filter.Class != 0 {
+		query = query.Where("class = ?", filter.Class)
+	}
+	if filter.Address != "" {
+		// Addresses rarely spell out the regency or province, so a hotel placed in a region of that name matches too
+		regionIDs := r.db.Model(&models.Region{}).Select("id").Where("name LIKE ?", "%"+filter.Address+"%")
+		query = query.Where("(address LIKE ? OR province_id IN (?) OR regency_id IN (?) OR district_id IN (?))", "%"+filter.Address+"%", regionIDs, regionIDs, regionIDs)
 	}
+	if filter.Name != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.RegionID != 0 {
+		query = query.Where("(province_id = ? OR regency_id = ? OR district_id = ?)", filter.RegionID, filter.RegionID, filter.RegionID)
+	}
+	if filter.Latitude != nil && filter.Longitude != nil {
+		query = query.Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+		if filter.RadiusKm > 0 {
+			query = query.Where(hotelSearchDistance+" <= ?", *filter.Latitude, *filter.Longitude, *filter.Latitude, filter.RadiusKm)
+		}
+	}
+	return query
+}
 
-	offset := (page - 1) * limit
+func (r *hotelRepository) SearchHotelAvailable(filter HotelSearchFilter, page, limit int) ([]HotelSearchRow, int, error) {
+	var (
+		rows  []HotelSearchRow
+		count int64
+	)
 
-	if address == "" && name == "" {
-		err = r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&hotels).Error
-	}
-	if address != "" && name == "" {
-		err = r.db.Where("address LIKE ?", "%"+address+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&hotels).Error
+	query := r.searchHotelQuery(filter)
+	err := query.Session(&gorm.Session{}).Count(&count).Error
+	if err != nil {
+		return rows, int(count), err
 	}
-	if address == "" && name != "" {
-		err = r.db.Where("name LIKE ?", "%"+name+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&hotels).Error
+
+	hotelRoomStart, hotelRoomStartArgs := hotelRoomStartQuery(filter)
+	selectColumns, selectArgs, order := "hotels.*, "+hotelRoomStart+" AS hotel_room_start", hotelRoomStartArgs, "id DESC"
+	if filter.Latitude != nil && filter.Longitude != nil {
+		selectColumns += ", " + hotelSearchDistance + " AS distance_km"
+		selectArgs = append(selectArgs, *filter.Latitude, *filter.Longitude, *filter.Latitude)
+		if filter.SortByDistance {
+			order = "distance_km ASC, id DESC"
+		}
 	}
-	if address != "" && name != "" {
-		err = r.db.Where("name LIKE ? AND address LIKE ?", "%"+name+"%", "%"+address+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&hotels).Error
+	switch strings.ToLower(filter.SortByPrice) {
+	case "asc":
+		order = "hotel_room_start ASC, " + order
+	case "desc":
+		order = "hotel_room_start DESC, " + order
 	}
 
-	return hotels, int(count), err
+	offset := (page - 1) * limit
+
+	err = query.Select(selectColumns, selectArgs...).Order(order).Limit(limit).Offset(offset).Scan(&rows).Error
+
+	return rows, int(count), err
+}
+
+// CountSearchHotelsByRegion counts the hotels a search finds in each region of a level, leaving out hotels
+// not placed down to that level.
+func (r *hotelRepository) CountSearchHotelsByRegion(filter HotelSearchFilter, level string) ([]HotelRegionCount, error) {
+	var hotelRegionCounts []HotelRegionCount
+	column, ok := hotelRegionColumns[level]
+	if !ok {
+		return hotelRegionCounts, errors.New("Unknown region level")
+	}
 
+	err := r.searchHotelQuery(filter).
+		Select(column + " AS region_id, COUNT(*) AS count").
+		Where(column + " > 0").
+		Group(column).
+		Order("count DESC").
+		Scan(&hotelRegionCounts).Error
+	return hotelRegionCounts, err
 }
@@ -5,17 +5,33 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type HotelOrderRepository interface {
+	BeginTransaction() *gorm.DB
+	WithTx(tx *gorm.DB) HotelOrderRepository
 	GetHotelOrders(page, limit int, userID uint, status string) ([]models.HotelOrder, int, error)
 	GetHotelOrderByStatusAndID(id, userID uint, status string) (models.HotelOrder, error)
 	GetHotelOrderByID(id, userID uint) (models.HotelOrder, error)
+	GetHotelOrderByIDForUpdate(id uint) (models.HotelOrder, error)
+	GetUnpaidHotelOrdersCreatedBefore(createdBefore time.Time) ([]models.HotelOrder, error)
 	GetHotelOrderByID2(id, userID uint) (models.HotelOrderMidtrans, error)
 	GetHotelOrderID(orderId string) (models.HotelOrder, error)
+	GetHotelRoomPeakBooking(hotelRoomID uint, dateStart, dateEnd string) (int, error)
+	GetHotelRoomBookingsByHotelOrderID(hotelOrderID uint) ([]models.HotelRoomBooking, error)
+	GetHotelOrderRoomsByHotelOrderID(hotelOrderID uint) ([]models.HotelOrderRoom, error)
+	GetHotelOrderRoomByID(id uint) (models.HotelOrderRoom, error)
+	CreateHotelOrderRoom(hotelOrderRoom models.HotelOrderRoom) (models.HotelOrderRoom, error)
+	UpdateHotelOrderRoom(hotelOrderRoom models.HotelOrderRoom) (models.HotelOrderRoom, error)
+	UpdateActiveHotelOrderRoomsStatus(hotelOrderID uint, status string) error
+	DeleteHotelRoomBookingsByHotelOrderRoomID(hotelOrderRoomID uint) error
+	CreateHotelRoomBookings(hotelRoomBookings []models.HotelRoomBooking) error
+	DeleteHotelRoomBookingsByHotelOrderID(hotelOrderID uint) error
 	CreateHotelOrder(hotelOrder models.HotelOrder) (models.HotelOrder, error)
 	CreateHotelOrder2(hotelOrder models.HotelOrderMidtrans) (models.HotelOrderMidtrans, error)
 	UpdateHotelOrder(hotelOrder models.HotelOrder) (models.HotelOrder, error)
+	UpdateHotelOrderStatus(id uint, fromStatus, toStatus string) (bool, error)
 	UpdateHotelOrder2(hotelOrder models.HotelOrderMidtrans) (models.HotelOrderMidtrans, error)
 	DeleteHotelOrder(hotelOrder models.HotelOrder) (models.HotelOrder, error)
 	CsvHotelOrder() ([]models.HotelOrder, error)
@@ -29,6 +45,14 @@ func NewHotelOrderRepository(db *gorm.DB) HotelOrderRepository {
 	return &hotelOrderRepository{db}
 }
 
+func (r *hotelOrderRepository) BeginTransaction() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *hotelOrderRepository) WithTx(tx *gorm.DB) HotelOrderRepository {
+	return &hotelOrderRepository{tx}
+}
+
 func (r *hotelOrderRepository) GetHotelOrders(page, limit int, userID uint, status string) ([]models.HotelOrder, int, error) {
 	var (
 		hotelOrders []models.HotelOrder
@@ -91,6 +115,18 @@ func (r *hotelOrderRepository) GetHotelOrderByID(id, userID uint) (models.HotelO
 	return hotelOrder, err
 }
 
+func (r *hotelOrderRepository) GetHotelOrderByIDForUpdate(id uint) (models.HotelOrder, error) {
+	var hotelOrder models.HotelOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&hotelOrder).Error
+	return hotelOrder, err
+}
+
+func (r *hotelOrderRepository) GetUnpaidHotelOrdersCreatedBefore(createdBefore time.Time) ([]models.HotelOrder, error) {
+	var hotelOrders []models.HotelOrder
+	err := r.db.Where("status = ? AND created_at < ?", "unpaid", createdBefore).Order("id ASC").Find(&hotelOrders).Error
+	return hotelOrders, err
+}
+
 func (r *hotelOrderRepository) GetHotelOrderByID2(id, userID uint) (models.HotelOrderMidtrans, error) {
 	var hotelOrder models.HotelOrderMidtrans
 	if userID == 1 {
@@ -107,6 +143,73 @@ func (r *hotelOrderRepository) GetHotelOrderID(orderId string) (models.HotelOrde
 	return hotelOrder, err
 }
 
+// GetHotelRoomPeakBooking returns the most rooms of the hotel room taken on a single night from dateStart
+// up to the night before dateEnd. Bookings of canceled and refunded orders do not take a room.
+func (r *hotelOrderRepository) GetHotelRoomPeakBooking(hotelRoomID uint, dateStart, dateEnd string) (int, error) {
+	var peak int
+	nights := r.db.Model(&models.HotelRoomBooking{}).
+		Select("SUM(quantity) AS quantity").
+		Where("hotel_room_id = ? AND date >= ? AND date < ?", hotelRoomID, dateStart, dateEnd).
+		Where("hotel_order_id NOT IN (?)", r.db.Model(&models.HotelOrder{}).Select("id").Where("status IN ?", []string{"canceled", "refund"})).
+		Group("date")
+	err := r.db.Table("(?) AS nights", nights).Select("COALESCE(MAX(quantity), 0)").Scan(&peak).Error
+	return peak, err
+}
+
+func (r *hotelOrderRepository) GetHotelRoomBookingsByHotelOrderID(hotelOrderID uint) ([]models.HotelRoomBooking, error) {
+	var hotelRoomBookings []models.HotelRoomBooking
+	err := r.db.Where("hotel_order_id = ?", hotelOrderID).Order("date ASC, id ASC").Find(&hotelRoomBookings).Error
+	return hotelRoomBookings, err
+}
+
+func (r *hotelOrderRepository) GetHotelOrderRoomsByHotelOrderID(hotelOrderID uint) ([]models.HotelOrderRoom, error) {
+	var hotelOrderRooms []models.HotelOrderRoom
+	err := r.db.Preload("HotelRoom").Where("hotel_order_id = ?", hotelOrderID).Order("id ASC").Find(&hotelOrderRooms).Error
+	return hotelOrderRooms, err
+}
+
+func (r *hotelOrderRepository) GetHotelOrderRoomByID(id uint) (models.HotelOrderRoom, error) {
+	var hotelOrderRoom models.HotelOrderRoom
+	err := r.db.Where("id = ?", id).First(&hotelOrderRoom).Error
+	return hotelOrderRoom, err
+}
+
+func (r *hotelOrderRepository) CreateHotelOrderRoom(hotelOrderRoom models.HotelOrderRoom) (models.HotelOrderRoom, error) {
+	err := r.db.Create(&hotelOrderRoom).Error
+	return hotelOrderRoom, err
+}
+
+func (r *hotelOrderRepository) UpdateHotelOrderRoom(hotelOrderRoom models.HotelOrderRoom) (models.HotelOrderRoom, error) {
+	err := r.db.Save(&hotelOrderRoom).Error
+	return hotelOrderRoom, err
+}
+
+// UpdateActiveHotelOrderRoomsStatus closes every room line of the order still active with status.
+func (r *hotelOrderRepository) UpdateActiveHotelOrderRoomsStatus(hotelOrderID uint, status string) error {
+	err := r.db.Model(&models.HotelOrderRoom{}).Where("hotel_order_id = ? AND status = ?", hotelOrderID, "active").Update("status", status).Error
+	return err
+}
+
+// DeleteHotelRoomBookingsByHotelOrderRoomID puts every night taken by the room line back on sale.
+func (r *hotelOrderRepository) DeleteHotelRoomBookingsByHotelOrderRoomID(hotelOrderRoomID uint) error {
+	err := r.db.Unscoped().Where("hotel_order_room_id = ?", hotelOrderRoomID).Delete(&models.HotelRoomBooking{}).Error
+	return err
+}
+
+func (r *hotelOrderRepository) CreateHotelRoomBookings(hotelRoomBookings []models.HotelRoomBooking) error {
+	if len(hotelRoomBookings) == 0 {
+		return nil
+	}
+	err := r.db.Create(&hotelRoomBookings).Error
+	return err
+}
+
+// DeleteHotelRoomBookingsByHotelOrderID puts every night taken by the order back on sale.
+func (r *hotelOrderRepository) DeleteHotelRoomBookingsByHotelOrderID(hotelOrderID uint) error {
+	err := r.db.Unscoped().Where("hotel_order_id = ?", hotelOrderID).Delete(&models.HotelRoomBooking{}).Error
+	return err
+}
+
 func (r *hotelOrderRepository) CreateHotelOrder(hotelOrder models.HotelOrder) (models.HotelOrder, error) {
 	err := r.db.Create(&hotelOrder).Error
 	return hotelOrder, err
@@ -122,6 +225,12 @@ func (r *hotelOrderRepository) UpdateHotelOrder(hotelOrder models.HotelOrder) (m
 	return hotelOrder, err
 }
 
+// UpdateHotelOrderStatus moves the order only while it still has fromStatus and reports whether it did.
+func (r *hotelOrderRepository) UpdateHotelOrderStatus(id uint, fromStatus, toStatus string) (bool, error) {
+	result := r.db.Model(&models.HotelOrder{}).Where("id = ? AND status = ?", id, fromStatus).Update("status", toStatus)
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *hotelOrderRepository) UpdateHotelOrder2(hotelOrder models.HotelOrderMidtrans) (models.HotelOrderMidtrans, error) {
 	err := r.db.Save(hotelOrder).Error
 	return hotelOrder, err
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HotelRefundRepository interface {
+	WithTx(tx *gorm.DB) HotelRefundRepository
+	GetHotelRefunds(page, limit int, userID uint, status string) ([]models.HotelRefund, int, error)
+	GetHotelRefundByID(id uint) (models.HotelRefund, error)
+	GetHotelRefundByIDForUpdate(id uint) (models.HotelRefund, error)
+	GetPendingHotelRefundByHotelOrderID(hotelOrderID uint) (models.HotelRefund, error)
+	CreateHotelRefund(hotelRefund models.HotelRefund) (models.HotelRefund, error)
+	UpdateHotelRefund(hotelRefund models.HotelRefund) (models.HotelRefund, error)
+}
+
+type hotelRefundRepository struct {
+	db *gorm.DB
+}
+
+func NewHotelRefundRepository(db *gorm.DB) HotelRefundRepository {
+	return &hotelRefundRepository{db}
+}
+
+func (r *hotelRefundRepository) WithTx(tx *gorm.DB) HotelRefundRepository {
+	return &hotelRefundRepository{tx}
+}
+
+func (r *hotelRefundRepository) GetHotelRefunds(page, limit int, userID uint, status string) ([]models.HotelRefund, int, error) {
+	var (
+		hotelRefunds []models.HotelRefund
+		count        int64
+	)
+	query := r.db.Model(&models.HotelRefund{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return hotelRefunds, int(count), err
+	}
+
+	offset := (page - 1) * limit
+
+	err = query.Order("id DESC").Limit(limit).Offset(offset).Find(&hotelRefunds).Error
+
+	return hotelRefunds, int(count), err
+}
+
+func (r *hotelRefundRepository) GetHotelRefundByID(id uint) (models.HotelRefund, error) {
+	var hotelRefund models.HotelRefund
+	err := r.db.Where("id = ?", id).First(&hotelRefund).Error
+	return hotelRefund, err
+}
+
+func (r *hotelRefundRepository) GetHotelRefundByIDForUpdate(id uint) (models.HotelRefund, error) {
+	var hotelRefund models.HotelRefund
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&hotelRefund).Error
+	return hotelRefund, err
+}
+
+func (r *hotelRefundRepository) GetPendingHotelRefundByHotelOrderID(hotelOrderID uint) (models.HotelRefund, error) {
+	var hotelRefund models.HotelRefund
+	err := r.db.Where("hotel_order_id = ? AND status = ?", hotelOrderID, "pending").First(&hotelRefund).Error
+	return hotelRefund, err
+}
+
+func (r *hotelRefundRepository) CreateHotelRefund(hotelRefund models.HotelRefund) (models.HotelRefund, error) {
+	err := r.db.Create(&hotelRefund).Error
+	return hotelRefund, err
+}
+
+func (r *hotelRefundRepository) UpdateHotelRefund(hotelRefund models.HotelRefund) (models.HotelRefund, error) {
+	err := r.db.Save(&hotelRefund).Error
+	return hotelRefund, err
+}
@@ -4,13 +4,16 @@ import (
 	"back-end-golang/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type HotelRoomRepository interface {
+	WithTx(tx *gorm.DB) HotelRoomRepository
 	GetAllHotelRooms(page, limit int) ([]models.HotelRoom, int, error)
 	GetAllHotelRoomByHotelID(id uint) ([]models.HotelRoom, error)
 	GetHotelRoomByID(id uint) (models.HotelRoom, error)
 	GetHotelRoomByID2(id uint) (models.HotelRoom, error)
+	GetHotelRoomByIDForUpdate(id uint) (models.HotelRoom, error)
 	GetHotelRoomByHotelID(id uint) (models.HotelRoom, error)
 	GetMinimumPriceHotelRoomByHotelID(id uint) (models.HotelRoom, error)
 	CreateHotelRoom(hotelRoom models.HotelRoom) (models.HotelRoom, error)
@@ -28,6 +31,10 @@ func NewHotelRoomRepository(db *gorm.DB) HotelRoomRepository {
 
 // Implementasi fungsi-fungsi dari interface ItemRepository
 
+func (r *hotelRoomRepository) WithTx(tx *gorm.DB) HotelRoomRepository {
+	return &hotelRoomRepository{tx}
+}
+
 func (r *hotelRoomRepository) GetAllHotelRooms(page, limit int) ([]models.HotelRoom, int, error) {
 	var (
 		hotelrooms []models.HotelRoom
@@ -75,6 +82,14 @@ func (r *hotelRoomRepository) GetHotelRoomByID2(id uint) (models.HotelRoom, erro
 	return hotelRoom, err
 }
 
+// GetHotelRoomByIDForUpdate locks the room row until the surrounding transaction ends, so orders
+// for the same room are created one after another.
+func (r *hotelRoomRepository) GetHotelRoomByIDForUpdate(id uint) (models.HotelRoom, error) {
+	var hotelRoom models.HotelRoom
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&hotelRoom).Error
+	return hotelRoom, err
+}
+
 func (r *hotelRoomRepository) CreateHotelRoom(hotelRoom models.HotelRoom) (models.HotelRoom, error) {
 	err := r.db.Create(&hotelRoom).Error
 	return hotelRoom, err
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type HotelRoomRateRepository interface {
+	GetHotelRoomRatesByHotelRoomID(hotelRoomID uint) ([]models.HotelRoomRate, error)
+	GetHotelRoomRatesByHotelRoomIDAndDates(hotelRoomID uint, dateStart, dateEnd string) ([]models.HotelRoomRate, error)
+	GetHotelRoomRateByID(id uint) (models.HotelRoomRate, error)
+	CreateHotelRoomRate(hotelRoomRate models.HotelRoomRate) (models.HotelRoomRate, error)
+	DeleteHotelRoomRate(hotelRoomRate models.HotelRoomRate) error
+}
+
+type hotelRoomRateRepository struct {
+	db *gorm.DB
+}
+
+func NewHotelRoomRateRepository(db *gorm.DB) HotelRoomRateRepository {
+	return &hotelRoomRateRepository{db}
+}
+
+func (r *hotelRoomRateRepository) GetHotelRoomRatesByHotelRoomID(hotelRoomID uint) ([]models.HotelRoomRate, error) {
+	var hotelRoomRates []models.HotelRoomRate
+	err := r.db.Where("hotel_room_id = ?", hotelRoomID).Order("date_start ASC, id ASC").Find(&hotelRoomRates).Error
+	return hotelRoomRates, err
+}
+
+// GetHotelRoomRatesByHotelRoomIDAndDates returns the rates of the hotel room that may cover a night from
+// dateStart up to the night before dateEnd, newest first. Weekdays are left for the caller to match.
+func (r *hotelRoomRateRepository) GetHotelRoomRatesByHotelRoomIDAndDates(hotelRoomID uint, dateStart, dateEnd string) ([]models.HotelRoomRate, error) {
+	var hotelRoomRates []models.HotelRoomRate
+	err := r.db.Where("hotel_room_id = ?", hotelRoomID).
+		Where("date_start IS NULL OR date_start < ?", dateEnd).
+		Where("date_end IS NULL OR date_end >= ?", dateStart).
+		Order("id DESC").
+		Find(&hotelRoomRates).Error
+	return hotelRoomRates, err
+}
+
+func (r *hotelRoomRateRepository) GetHotelRoomRateByID(id uint) (models.HotelRoomRate, error) {
+	var hotelRoomRate models.HotelRoomRate
+	err := r.db.Where("id = ?", id).First(&hotelRoomRate).Error
+	return hotelRoomRate, err
+}
+
+func (r *hotelRoomRateRepository) CreateHotelRoomRate(hotelRoomRate models.HotelRoomRate) (models.HotelRoomRate, error) {
+	err := r.db.Create(&hotelRoomRate).Error
+	return hotelRoomRate, err
+}
+
+func (r *hotelRoomRateRepository) DeleteHotelRoomRate(hotelRoomRate models.HotelRoomRate) error {
+	err := r.db.Delete(&hotelRoomRate).Error
+	return err
+}
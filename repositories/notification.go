@@ -7,6 +7,7 @@ import (
 )
 
 type NotificationRepository interface {
+	WithTx(tx *gorm.DB) NotificationRepository
 	GetNotificationByUserID(id uint) ([]models.Notification, error)
 	CreateNotification(notification models.Notification) (models.Notification, error)
 }
@@ -21,6 +22,10 @@ func NewNotificationRepository(db *gorm.DB) NotificationRepository {
 
 // Implementasi fungsi-fungsi dari interface ItemRepository
 
+func (r notificationRepository) WithTx(tx *gorm.DB) NotificationRepository {
+	return &notificationRepository{tx}
+}
+
 func (r notificationRepository) GetNotificationByUserID(id uint) ([]models.Notification, error) {
 	var notification []models.Notification
 	err := r.db.Where("user_id = ?", id).Order("created_at DESC").Find(&notification).Error
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistoryRepository interface {
+	WithTx(tx *gorm.DB) OrderStatusHistoryRepository
+	GetOrderStatusHistories(orderType string, orderID uint) ([]models.OrderStatusHistory, error)
+	CreateOrderStatusHistory(orderStatusHistory models.OrderStatusHistory) (models.OrderStatusHistory, error)
+}
+
+type orderStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderStatusHistoryRepository(db *gorm.DB) OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{db}
+}
+
+func (r *orderStatusHistoryRepository) WithTx(tx *gorm.DB) OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{tx}
+}
+
+func (r *orderStatusHistoryRepository) GetOrderStatusHistories(orderType string, orderID uint) ([]models.OrderStatusHistory, error) {
+	var orderStatusHistories []models.OrderStatusHistory
+	err := r.db.Where("order_type = ? AND order_id = ?", orderType, orderID).Order("id ASC").Find(&orderStatusHistories).Error
+	return orderStatusHistories, err
+}
+
+func (r *orderStatusHistoryRepository) CreateOrderStatusHistory(orderStatusHistory models.OrderStatusHistory) (models.OrderStatusHistory, error) {
+	err := r.db.Create(&orderStatusHistory).Error
+	return orderStatusHistory, err
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type RegionRepository interface {
+	GetRegionsByParentID(parentID uint, name string) ([]models.Region, error)
+	GetRegionsByIDs(ids []uint) ([]models.Region, error)
+	GetRegionByID(id uint) (models.Region, error)
+	IsRegionInUse(id uint) (bool, error)
+	CreateRegion(region models.Region) (models.Region, error)
+	UpdateRegion(region models.Region) (models.Region, error)
+	DeleteRegion(region models.Region) error
+}
+
+type regionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegionRepository(db *gorm.DB) RegionRepository {
+	return &regionRepository{db}
+}
+
+func (r *regionRepository) GetRegionsByParentID(parentID uint, name string) ([]models.Region, error) {
+	var regions []models.Region
+	query := r.db.Where("parent_id = ?", parentID)
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	err := query.Order("name ASC").Find(&regions).Error
+	return regions, err
+}
+
+func (r *regionRepository) GetRegionsByIDs(ids []uint) ([]models.Region, error) {
+	var regions []models.Region
+	if len(ids) == 0 {
+		return regions, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&regions).Error
+	return regions, err
+}
+
+func (r *regionRepository) GetRegionByID(id uint) (models.Region, error) {
+	var region models.Region
+	err := r.db.Where("id = ?", id).First(&region).Error
+	return region, err
+}
+
+// IsRegionInUse reports whether a region has regions below it or places a hotel or a station.
+func (r *regionRepository) IsRegionInUse(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Region{}).Where("parent_id = ?", id).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	where := "province_id = ? OR regency_id = ? OR district_id = ?"
+	err = r.db.Model(&models.Hotel{}).Where(where, id, id, id).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.Model(&models.Station{}).Where(where, id, id, id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *regionRepository) CreateRegion(region models.Region) (models.Region, error) {
+	err := r.db.Create(&region).Error
+	return region, err
+}
+
+func (r *regionRepository) UpdateRegion(region models.Region) (models.Region, error) {
+	err := r.db.Save(&region).Error
+	return region, err
+}
+
+func (r *regionRepository) DeleteRegion(region models.Region) error {
+	err := r.db.Delete(&region).Error
+	return err
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type SavedPassengerRepository interface {
+	GetSavedPassengers(page, limit int, userID uint) ([]models.SavedPassenger, int, error)
+	GetSavedPassengerByID(userID, id uint) (models.SavedPassenger, error)
+	CreateSavedPassenger(savedPassenger models.SavedPassenger) (models.SavedPassenger, error)
+	UpdateSavedPassenger(savedPassenger models.SavedPassenger) (models.SavedPassenger, error)
+	DeleteSavedPassenger(savedPassenger models.SavedPassenger) error
+}
+
+type savedPassengerRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedPassengerRepository(db *gorm.DB) SavedPassengerRepository {
+	return &savedPassengerRepository{db}
+}
+
+func (r *savedPassengerRepository) GetSavedPassengers(page, limit int, userID uint) ([]models.SavedPassenger, int, error) {
+	var (
+		savedPassengers []models.SavedPassenger
+		count           int64
+	)
+
+	err := r.db.Model(&models.SavedPassenger{}).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		return savedPassengers, 0, err
+	}
+
+	offset := (page - 1) * limit
+
+	err = r.db.Where("user_id = ?", userID).Order("full_name ASC").Limit(limit).Offset(offset).Find(&savedPassengers).Error
+	return savedPassengers, int(count), err
+}
+
+func (r *savedPassengerRepository) GetSavedPassengerByID(userID, id uint) (models.SavedPassenger, error) {
+	var savedPassenger models.SavedPassenger
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&savedPassenger).Error
+	return savedPassenger, err
+}
+
+func (r *savedPassengerRepository) CreateSavedPassenger(savedPassenger models.SavedPassenger) (models.SavedPassenger, error) {
+	err := r.db.Create(&savedPassenger).Error
+	return savedPassenger, err
+}
+
+func (r *savedPassengerRepository) UpdateSavedPassenger(savedPassenger models.SavedPassenger) (models.SavedPassenger, error) {
+	err := r.db.Save(&savedPassenger).Error
+	return savedPassenger, err
+}
+
+func (r *savedPassengerRepository) DeleteSavedPassenger(savedPassenger models.SavedPassenger) error {
+	err := r.db.Delete(&savedPassenger).Error
+	return err
+}
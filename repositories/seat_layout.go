@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type SeatLayoutRepository interface {
+	WithTx(tx *gorm.DB) SeatLayoutRepository
+	GetAllSeatLayouts(page, limit int) ([]models.SeatLayout, int, error)
+	GetSeatLayoutByID(id uint) (models.SeatLayout, error)
+	CreateSeatLayout(seatLayout models.SeatLayout) (models.SeatLayout, error)
+	UpdateSeatLayout(seatLayout models.SeatLayout) (models.SeatLayout, error)
+	DeleteSeatLayout(id uint) error
+}
+
+type seatLayoutRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatLayoutRepository(db *gorm.DB) SeatLayoutRepository {
+	return &seatLayoutRepository{db}
+}
+
+func (r *seatLayoutRepository) WithTx(tx *gorm.DB) SeatLayoutRepository {
+	return &seatLayoutRepository{tx}
+}
+
+func (r *seatLayoutRepository) GetAllSeatLayouts(page, limit int) ([]models.SeatLayout, int, error) {
+	var (
+		seatLayouts []models.SeatLayout
+		count       int64
+	)
+	err := r.db.Find(&seatLayouts).Count(&count).Error
+	if err != nil {
+		return seatLayouts, int(count), err
+	}
+
+	offset := (page - 1) * limit
+
+	err = r.db.Limit(limit).Offset(offset).Find(&seatLayouts).Error
+
+	return seatLayouts, int(count), err
+}
+
+func (r *seatLayoutRepository) GetSeatLayoutByID(id uint) (models.SeatLayout, error) {
+	var seatLayout models.SeatLayout
+	err := r.db.Where("id = ?", id).First(&seatLayout).Error
+	return seatLayout, err
+}
+
+func (r *seatLayoutRepository) CreateSeatLayout(seatLayout models.SeatLayout) (models.SeatLayout, error) {
+	err := r.db.Create(&seatLayout).Error
+	return seatLayout, err
+}
+
+func (r *seatLayoutRepository) UpdateSeatLayout(seatLayout models.SeatLayout) (models.SeatLayout, error) {
+	err := r.db.Save(&seatLayout).Error
+	return seatLayout, err
+}
+
+func (r *seatLayoutRepository) DeleteSeatLayout(id uint) error {
+	var seatLayout models.SeatLayout
+	err := r.db.Where("id = ?", id).Delete(&seatLayout).Error
+	return err
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"back-end-golang/models"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -11,6 +12,8 @@ type StationRepository interface {
 	GetAllStationsByAdmin(page, limit int, search string) ([]models.Station, int, error)
 	GetStationByID(id uint) (models.Station, error)
 	GetStationByID2(id uint) (models.Station, error)
+	GetStationsByOrigin(origin string) ([]models.Station, error)
+	GetStationsByKeyword(keyword string) ([]models.Station, error)
 	CreateStation(station models.Station) (models.Station, error)
 	UpdateStation(station models.Station) (models.Station, error)
 	DeleteStation(id uint) error
@@ -67,6 +70,40 @@ func (r *stationRepository) GetStationByID2(id uint) (models.Station, error) {
 	return station, err
 }
 
+func (r *stationRepository) GetStationsByOrigin(origin string) ([]models.Station, error) {
+	var stations []models.Station
+	err := r.db.Where("LOWER(origin) = LOWER(?)", strings.TrimSpace(origin)).Order("id ASC").Find(&stations).Error
+	return stations, err
+}
+
+// likeEscaper escapes the LIKE wildcards typed by a user.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// GetStationsByKeyword shortlists the stations whose city, name or initial could match a lowercase keyword:
+// its letters appear there in order, or a word starts with its first letter so a later typo is still found.
+func (r *stationRepository) GetStationsByKeyword(keyword string) ([]models.Station, error) {
+	var stations []models.Station
+
+	var letters []string
+	for _, letter := range keyword {
+		letters = append(letters, likeEscaper.Replace(string(letter)))
+	}
+	if len(letters) == 0 {
+		return stations, nil
+	}
+	inOrder := "%" + strings.Join(letters, "%") + "%"
+
+	var conditions []string
+	var args []interface{}
+	for _, column := range []string{"origin", "name", "initial"} {
+		conditions = append(conditions, "LOWER("+column+") LIKE ? OR LOWER("+column+") LIKE ? OR LOWER("+column+") LIKE ?")
+		args = append(args, inOrder, letters[0]+"%", "% "+letters[0]+"%")
+	}
+
+	err := r.db.Where(strings.Join(conditions, " OR "), args...).Order("id ASC").Find(&stations).Error
+	return stations, err
+}
+
 func (r *stationRepository) CreateStation(station models.Station) (models.Station, error) {
 	err := r.db.Create(&station).Error
 	return station, err
@@ -2,16 +2,24 @@ package repositories
 
 import (
 	"back-end-golang/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TicketOrderRepository interface {
+	BeginTransaction() *gorm.DB
+	WithTx(tx *gorm.DB) TicketOrderRepository
 	GetTicketOrders(page, limit int, status string) ([]models.TicketOrder, int, error)
 	GetTicketOrderByStatusAndID(id, userID uint, status string) (models.TicketOrder, error)
 	GetTicketOrderByID(id, userID uint) (models.TicketOrder, error)
+	GetTicketOrderByIDOnly(id uint) (models.TicketOrder, error)
+	GetTicketOrderByIDForUpdate(id uint) (models.TicketOrder, error)
+	GetUnpaidTicketOrdersCreatedBefore(createdBefore time.Time) ([]models.TicketOrder, error)
 	CreateTicketOrder(ticketOrder models.TicketOrder) (models.TicketOrder, error)
 	UpdateTicketOrder(ticketOrder models.TicketOrder) (models.TicketOrder, error)
+	UpdateTicketOrderStatus(id uint, fromStatus, toStatus string) (bool, error)
 	DeleteTicketOrder(ticketOrder models.TicketOrder) (models.TicketOrder, error)
 }
 
@@ -23,6 +31,14 @@ func NewTicketOrderRepository(db *gorm.DB) TicketOrderRepository {
 	return &ticketOrderRepository{db}
 }
 
+func (r *ticketOrderRepository) BeginTransaction() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *ticketOrderRepository) WithTx(tx *gorm.DB) TicketOrderRepository {
+	return &ticketOrderRepository{tx}
+}
+
 func (r *ticketOrderRepository) GetTicketOrders(page, limit int, status string) ([]models.TicketOrder, int, error) {
 	var (
 		ticketOrders []models.TicketOrder
@@ -60,6 +76,25 @@ func (r *ticketOrderRepository) GetTicketOrderByID(id, userID uint) (models.Tick
 	return ticketOrder, err
 }
 
+// GetTicketOrderByIDOnly returns the order whoever it belongs to, for staff and background work.
+func (r *ticketOrderRepository) GetTicketOrderByIDOnly(id uint) (models.TicketOrder, error) {
+	var ticketOrder models.TicketOrder
+	err := r.db.Where("id = ?", id).First(&ticketOrder).Error
+	return ticketOrder, err
+}
+
+func (r *ticketOrderRepository) GetTicketOrderByIDForUpdate(id uint) (models.TicketOrder, error) {
+	var ticketOrder models.TicketOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&ticketOrder).Error
+	return ticketOrder, err
+}
+
+func (r *ticketOrderRepository) GetUnpaidTicketOrdersCreatedBefore(createdBefore time.Time) ([]models.TicketOrder, error) {
+	var ticketOrders []models.TicketOrder
+	err := r.db.Where("status = ? AND created_at < ?", "unpaid", createdBefore).Order("id ASC").Find(&ticketOrders).Error
+	return ticketOrders, err
+}
+
 func (r *ticketOrderRepository) CreateTicketOrder(ticketOrder models.TicketOrder) (models.TicketOrder, error) {
 	err := r.db.Create(&ticketOrder).Error
 	return ticketOrder, err
@@ -70,6 +105,12 @@ func (r *ticketOrderRepository) UpdateTicketOrder(ticketOrder models.TicketOrder
 	return ticketOrder, err
 }
 
+// UpdateTicketOrderStatus moves the order only while it still has fromStatus and reports whether it did.
+func (r *ticketOrderRepository) UpdateTicketOrderStatus(id uint, fromStatus, toStatus string) (bool, error) {
+	result := r.db.Model(&models.TicketOrder{}).Where("id = ? AND status = ?", id, fromStatus).Update("status", toStatus)
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *ticketOrderRepository) DeleteTicketOrder(ticketOrder models.TicketOrder) (models.TicketOrder, error) {
 	err := r.db.Unscoped().Delete(&ticketOrder).Error
 	return ticketOrder, err
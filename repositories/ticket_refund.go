@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketRefundRepository interface {
+	WithTx(tx *gorm.DB) TicketRefundRepository
+	GetRefundPolicies() ([]models.RefundPolicy, error)
+	GetRefundPolicyByID(id uint) (models.RefundPolicy, error)
+	GetRefundPolicyByClass(class string) (models.RefundPolicy, error)
+	SaveRefundPolicy(refundPolicy models.RefundPolicy) (models.RefundPolicy, error)
+	DeleteRefundPolicy(refundPolicy models.RefundPolicy) error
+	GetRefundPolicyRulesByRefundPolicyID(refundPolicyID uint) ([]models.RefundPolicyRule, error)
+	GetRefundPolicyRuleByRefundPolicyIDAndHours(refundPolicyID uint, hoursBefore int) (models.RefundPolicyRule, error)
+	ReplaceRefundPolicyRules(refundPolicyID uint, refundPolicyRules []models.RefundPolicyRule) error
+	GetTicketRefunds(page, limit int, userID uint, status string) ([]models.TicketRefund, int, error)
+	GetTicketRefundByID(id uint) (models.TicketRefund, error)
+	GetTicketRefundByIDForUpdate(id uint) (models.TicketRefund, error)
+	GetPendingTicketRefundByTicketOrderID(ticketOrderID uint) (models.TicketRefund, error)
+	CreateTicketRefund(ticketRefund models.TicketRefund) (models.TicketRefund, error)
+	UpdateTicketRefund(ticketRefund models.TicketRefund) (models.TicketRefund, error)
+}
+
+type ticketRefundRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketRefundRepository(db *gorm.DB) TicketRefundRepository {
+	return &ticketRefundRepository{db}
+}
+
+func (r *ticketRefundRepository) WithTx(tx *gorm.DB) TicketRefundRepository {
+	return &ticketRefundRepository{tx}
+}
+
+func (r *ticketRefundRepository) GetRefundPolicies() ([]models.RefundPolicy, error) {
+	var refundPolicies []models.RefundPolicy
+	err := r.db.Order("class ASC").Find(&refundPolicies).Error
+	return refundPolicies, err
+}
+
+func (r *ticketRefundRepository) GetRefundPolicyByID(id uint) (models.RefundPolicy, error) {
+	var refundPolicy models.RefundPolicy
+	err := r.db.Where("id = ?", id).First(&refundPolicy).Error
+	return refundPolicy, err
+}
+
+func (r *ticketRefundRepository) GetRefundPolicyByClass(class string) (models.RefundPolicy, error) {
+	var refundPolicy models.RefundPolicy
+	err := r.db.Where("class = ?", class).First(&refundPolicy).Error
+	return refundPolicy, err
+}
+
+func (r *ticketRefundRepository) SaveRefundPolicy(refundPolicy models.RefundPolicy) (models.RefundPolicy, error) {
+	err := r.db.Save(&refundPolicy).Error
+	return refundPolicy, err
+}
+
+func (r *ticketRefundRepository) DeleteRefundPolicy(refundPolicy models.RefundPolicy) error {
+	err := r.db.Unscoped().Where("refund_policy_id = ?", refundPolicy.ID).Delete(&models.RefundPolicyRule{}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Unscoped().Delete(&refundPolicy).Error
+}
+
+func (r *ticketRefundRepository) GetRefundPolicyRulesByRefundPolicyID(refundPolicyID uint) ([]models.RefundPolicyRule, error) {
+	var refundPolicyRules []models.RefundPolicyRule
+	err := r.db.Where("refund_policy_id = ?", refundPolicyID).Order("min_hours_before DESC").Find(&refundPolicyRules).Error
+	return refundPolicyRules, err
+}
+
+func (r *ticketRefundRepository) GetRefundPolicyRuleByRefundPolicyIDAndHours(refundPolicyID uint, hoursBefore int) (models.RefundPolicyRule, error) {
+	var refundPolicyRule models.RefundPolicyRule
+	err := r.db.Where("refund_policy_id = ? AND min_hours_before <= ?", refundPolicyID, hoursBefore).Order("min_hours_before DESC").First(&refundPolicyRule).Error
+	return refundPolicyRule, err
+}
+
+func (r *ticketRefundRepository) ReplaceRefundPolicyRules(refundPolicyID uint, refundPolicyRules []models.RefundPolicyRule) error {
+	err := r.db.Unscoped().Where("refund_policy_id = ?", refundPolicyID).Delete(&models.RefundPolicyRule{}).Error
+	if err != nil || len(refundPolicyRules) == 0 {
+		return err
+	}
+	return r.db.Create(&refundPolicyRules).Error
+}
+
+func (r *ticketRefundRepository) GetTicketRefunds(page, limit int, userID uint, status string) ([]models.TicketRefund, int, error) {
+	var (
+		ticketRefunds []models.TicketRefund
+		count         int64
+	)
+	query := r.db.Model(&models.TicketRefund{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return ticketRefunds, int(count), err
+	}
+
+	offset := (page - 1) * limit
+
+	err = query.Order("id DESC").Limit(limit).Offset(offset).Find(&ticketRefunds).Error
+
+	return ticketRefunds, int(count), err
+}
+
+func (r *ticketRefundRepository) GetTicketRefundByID(id uint) (models.TicketRefund, error) {
+	var ticketRefund models.TicketRefund
+	err := r.db.Where("id = ?", id).First(&ticketRefund).Error
+	return ticketRefund, err
+}
+
+func (r *ticketRefundRepository) GetTicketRefundByIDForUpdate(id uint) (models.TicketRefund, error) {
+	var ticketRefund models.TicketRefund
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&ticketRefund).Error
+	return ticketRefund, err
+}
+
+func (r *ticketRefundRepository) GetPendingTicketRefundByTicketOrderID(ticketOrderID uint) (models.TicketRefund, error) {
+	var ticketRefund models.TicketRefund
+	err := r.db.Where("ticket_order_id = ? AND status = ? AND ticket_reschedule_id = 0", ticketOrderID, "pending").First(&ticketRefund).Error
+	return ticketRefund, err
+}
+
+func (r *ticketRefundRepository) CreateTicketRefund(ticketRefund models.TicketRefund) (models.TicketRefund, error) {
+	err := r.db.Create(&ticketRefund).Error
+	return ticketRefund, err
+}
+
+func (r *ticketRefundRepository) UpdateTicketRefund(ticketRefund models.TicketRefund) (models.TicketRefund, error) {
+	err := r.db.Save(&ticketRefund).Error
+	return ticketRefund, err
+}
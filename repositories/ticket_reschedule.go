@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketRescheduleRepository interface {
+	WithTx(tx *gorm.DB) TicketRescheduleRepository
+	GetTicketReschedules(page, limit int, userID uint, status string) ([]models.TicketReschedule, int, error)
+	GetTicketRescheduleByIDForUpdate(id uint) (models.TicketReschedule, error)
+	GetUnpaidTicketRescheduleByTicketTravelerDetailID(ticketTravelerDetailID uint) (models.TicketReschedule, error)
+	GetTicketRescheduleByOldBoardingTicketCode(boardingTicketCode string) (models.TicketReschedule, error)
+	CreateTicketReschedule(ticketReschedule models.TicketReschedule) (models.TicketReschedule, error)
+	UpdateTicketReschedule(ticketReschedule models.TicketReschedule) (models.TicketReschedule, error)
+}
+
+type ticketRescheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketRescheduleRepository(db *gorm.DB) TicketRescheduleRepository {
+	return &ticketRescheduleRepository{db}
+}
+
+func (r *ticketRescheduleRepository) WithTx(tx *gorm.DB) TicketRescheduleRepository {
+	return &ticketRescheduleRepository{tx}
+}
+
+func (r *ticketRescheduleRepository) GetTicketReschedules(page, limit int, userID uint, status string) ([]models.TicketReschedule, int, error) {
+	var (
+		ticketReschedules []models.TicketReschedule
+		count             int64
+	)
+	query := r.db.Model(&models.TicketReschedule{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return ticketReschedules, int(count), err
+	}
+
+	offset := (page - 1) * limit
+
+	err = query.Order("id DESC").Limit(limit).Offset(offset).Find(&ticketReschedules).Error
+
+	return ticketReschedules, int(count), err
+}
+
+func (r *ticketRescheduleRepository) GetTicketRescheduleByIDForUpdate(id uint) (models.TicketReschedule, error) {
+	var ticketReschedule models.TicketReschedule
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&ticketReschedule).Error
+	return ticketReschedule, err
+}
+
+func (r *ticketRescheduleRepository) GetUnpaidTicketRescheduleByTicketTravelerDetailID(ticketTravelerDetailID uint) (models.TicketReschedule, error) {
+	var ticketReschedule models.TicketReschedule
+	err := r.db.Where("ticket_traveler_detail_id = ? AND status = ?", ticketTravelerDetailID, "unpaid").First(&ticketReschedule).Error
+	return ticketReschedule, err
+}
+
+// GetTicketRescheduleByOldBoardingTicketCode finds the reschedule that voided a boarding ticket code.
+func (r *ticketRescheduleRepository) GetTicketRescheduleByOldBoardingTicketCode(boardingTicketCode string) (models.TicketReschedule, error) {
+	var ticketReschedule models.TicketReschedule
+	err := r.db.Where("old_boarding_ticket_code = ? AND status IN ?", boardingTicketCode, []string{"paid", "credited"}).First(&ticketReschedule).Error
+	return ticketReschedule, err
+}
+
+func (r *ticketRescheduleRepository) CreateTicketReschedule(ticketReschedule models.TicketReschedule) (models.TicketReschedule, error) {
+	err := r.db.Create(&ticketReschedule).Error
+	return ticketReschedule, err
+}
+
+func (r *ticketRescheduleRepository) UpdateTicketReschedule(ticketReschedule models.TicketReschedule) (models.TicketReschedule, error) {
+	err := r.db.Save(&ticketReschedule).Error
+	return ticketReschedule, err
+}
@@ -2,19 +2,31 @@ package repositories
 
 import (
 	"back-end-golang/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TicketTravelerDetailRepository interface {
+	WithTx(tx *gorm.DB) TicketTravelerDetailRepository
 	GetAllTicketTravelerDetails() ([]models.TicketTravelerDetail, int, error)
 	GetTicketTravelerDetailByID(id uint) (models.TicketTravelerDetail, error)
+	GetTicketTravelerDetailByIDForUpdate(id uint) (models.TicketTravelerDetail, error)
+	GetTicketTravelerDetailByBoardingTicketCode(boardingTicketCode string) (models.TicketTravelerDetail, error)
+	MarkTicketTravelerDetailBoarded(id, boardedBy uint, boardedAt time.Time) (bool, error)
 	GetTicketTravelerDetailByTrainSeatID(trainId, trainSeatId uint, date string) (models.TicketTravelerDetail, error)
+	GetTicketTravelerDetailByTrainSeatIDSegment(trainId, trainSeatId uint, date string, originSequence, destinationSequence int) (models.TicketTravelerDetail, error)
 	GetTicketTravelerDetailByTicketOrderID(id uint) ([]models.TicketTravelerDetail, error)
+	GetTicketTravelerDetailsByAccompaniedByID(accompaniedById uint) ([]models.TicketTravelerDetail, error)
+	GetTicketTravelerDetailByTrainCarriageID(trainCarriageId uint) (models.TicketTravelerDetail, error)
 	GetTicketTravelerDetailByTicketOrderIDAndTrainID(ticketOrderId, trainId uint) (models.TicketTravelerDetail, error)
 	CreateTicketTravelerDetail(ticketTravelerDetail models.TicketTravelerDetail) (models.TicketTravelerDetail, error)
 	UpdateTicketTravelerDetail(ticketTravelerDetail models.TicketTravelerDetail) (models.TicketTravelerDetail, error)
 	DeleteTicketTravelerDetail(ticketTravelerDetail models.TicketTravelerDetail) (models.TicketTravelerDetail, error)
+	CreateTrainSeatBookings(trainSeatBookings []models.TrainSeatBooking) error
+	DeleteTrainSeatBookingsByTicketOrderID(ticketOrderId uint) error
+	DeleteTrainSeatBookingsByTicketTravelerDetailID(ticketTravelerDetailId uint) error
 }
 
 type ticketTravelerDetailRepository struct {
@@ -25,6 +37,10 @@ func NewTicketTravelerDetailRepository(db *gorm.DB) TicketTravelerDetailReposito
 	return &ticketTravelerDetailRepository{db}
 }
 
+func (r *ticketTravelerDetailRepository) WithTx(tx *gorm.DB) TicketTravelerDetailRepository {
+	return &ticketTravelerDetailRepository{tx}
+}
+
 func (r *ticketTravelerDetailRepository) GetAllTicketTravelerDetails() ([]models.TicketTravelerDetail, int, error) {
 	var (
 		ticketTravelerDetails []models.TicketTravelerDetail
@@ -45,18 +61,68 @@ func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByID(id uint) (m
 	return ticketTravelerDetail, err
 }
 
+// GetTicketTravelerDetailByIDForUpdate locks the ticket row until the surrounding transaction ends, so two
+// reschedules of the same ticket cannot both move it.
+func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByIDForUpdate(id uint) (models.TicketTravelerDetail, error) {
+	var ticketTravelerDetail models.TicketTravelerDetail
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&ticketTravelerDetail).Error
+	return ticketTravelerDetail, err
+}
+
+func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByBoardingTicketCode(boardingTicketCode string) (models.TicketTravelerDetail, error) {
+	var ticketTravelerDetail models.TicketTravelerDetail
+	err := r.db.Where("boarding_ticket_code = ?", boardingTicketCode).First(&ticketTravelerDetail).Error
+	return ticketTravelerDetail, err
+}
+
+// MarkTicketTravelerDetailBoarded stamps the ticket as boarded unless it already is, it reports false when
+// another scan got there first.
+func (r *ticketTravelerDetailRepository) MarkTicketTravelerDetailBoarded(id, boardedBy uint, boardedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.TicketTravelerDetail{}).Where("id = ? AND boarded_at IS NULL", id).Updates(map[string]interface{}{"boarded_at": boardedAt, "boarded_by": boardedBy})
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByTrainSeatID(trainId, trainSeatId uint, date string) (models.TicketTravelerDetail, error) {
 	var ticketTravelerDetail models.TicketTravelerDetail
 	err := r.db.Where("train_id = ? AND train_seat_id = ? AND date_of_departure = ?", trainId, trainSeatId, date).First(&ticketTravelerDetail).Error
 	return ticketTravelerDetail, err
 }
 
+// GetTicketTravelerDetailByTrainSeatIDSegment returns a ticket on the service departing on date whose
+// segment overlaps the given stop sequence range. Tickets without a stored sequence occupy the seat
+// for the whole trip, tickets of canceled or refunded orders release it.
+func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByTrainSeatIDSegment(trainId, trainSeatId uint, date string, originSequence, destinationSequence int) (models.TicketTravelerDetail, error) {
+	var ticketTravelerDetail models.TicketTravelerDetail
+	err := r.db.Where("train_id = ? AND train_seat_id = ? AND COALESCE(service_date, date_of_departure) = ?", trainId, trainSeatId, date).
+		Where("accompanied_by_id = 0").
+		Where("destination_sequence = 0 OR (origin_sequence < ? AND destination_sequence > ?)", destinationSequence, originSequence).
+		Where("ticket_order_id NOT IN (?)", r.db.Model(&models.TicketOrder{}).Select("id").Where("status IN ?", []string{"canceled", "refund"})).
+		First(&ticketTravelerDetail).Error
+	return ticketTravelerDetail, err
+}
+
 func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByTicketOrderID(id uint) ([]models.TicketTravelerDetail, error) {
 	var ticketTravelerDetail []models.TicketTravelerDetail
 	err := r.db.Where("ticket_order_id = ?", id).Find(&ticketTravelerDetail).Error
 	return ticketTravelerDetail, err
 }
 
+// GetTicketTravelerDetailsByAccompaniedByID returns the infant tickets riding with the given adult ticket.
+func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailsByAccompaniedByID(accompaniedById uint) ([]models.TicketTravelerDetail, error) {
+	var ticketTravelerDetails []models.TicketTravelerDetail
+	err := r.db.Where("accompanied_by_id = ?", accompaniedById).Find(&ticketTravelerDetails).Error
+	return ticketTravelerDetails, err
+}
+
+// GetTicketTravelerDetailByTrainCarriageID returns a ticket of the carriage from an order that will still travel.
+func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByTrainCarriageID(trainCarriageId uint) (models.TicketTravelerDetail, error) {
+	var ticketTravelerDetail models.TicketTravelerDetail
+	err := r.db.Where("train_carriage_id = ?", trainCarriageId).
+		Where("ticket_order_id IN (?)", r.db.Model(&models.TicketOrder{}).Select("id").Where("status IN ?", []string{"unpaid", "paid"})).
+		First(&ticketTravelerDetail).Error
+	return ticketTravelerDetail, err
+}
+
 func (r *ticketTravelerDetailRepository) GetTicketTravelerDetailByTicketOrderIDAndTrainID(ticketOrderId, trainId uint) (models.TicketTravelerDetail, error) {
 	var ticketTravelerDetail models.TicketTravelerDetail
 	err := r.db.Where("ticket_order_id = ? AND train_id = ?", ticketOrderId, trainId).First(&ticketTravelerDetail).Error
@@ -77,3 +143,24 @@ func (r *ticketTravelerDetailRepository) DeleteTicketTravelerDetail(ticketTravel
 	err := r.db.Unscoped().Delete(&ticketTravelerDetail).Error
 	return ticketTravelerDetail, err
 }
+
+func (r *ticketTravelerDetailRepository) CreateTrainSeatBookings(trainSeatBookings []models.TrainSeatBooking) error {
+	if len(trainSeatBookings) == 0 {
+		return nil
+	}
+	err := r.db.Create(&trainSeatBookings).Error
+	return err
+}
+
+// DeleteTrainSeatBookingsByTicketOrderID releases every seat hop claimed by the order. The rows are
+// removed for good, a soft deleted row would keep the unique index taken.
+func (r *ticketTravelerDetailRepository) DeleteTrainSeatBookingsByTicketOrderID(ticketOrderId uint) error {
+	err := r.db.Unscoped().Where("ticket_order_id = ?", ticketOrderId).Delete(&models.TrainSeatBooking{}).Error
+	return err
+}
+
+// DeleteTrainSeatBookingsByTicketTravelerDetailID releases the seat hops of a single ticket.
+func (r *ticketTravelerDetailRepository) DeleteTrainSeatBookingsByTicketTravelerDetailID(ticketTravelerDetailId uint) error {
+	err := r.db.Unscoped().Where("ticket_traveler_detail_id = ?", ticketTravelerDetailId).Delete(&models.TrainSeatBooking{}).Error
+	return err
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"back-end-golang/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,8 +16,10 @@ type TrainRepository interface {
 	GetTrainByID2(id uint) (models.Train, error)
 	TrainStationByTrainID(id uint) (models.TrainStation, error)
 	GetTrainStationByTrainID(id uint) ([]models.TrainStation, error)
+	GetAllTrainStationsAvailable() ([]models.TrainStation, error)
 	GetTrainCarriageByTrainID(id uint) ([]models.TrainCarriage, error)
 	SearchTrainAvailable(trainId, originId, destinationId uint) ([]models.TrainStation, error)
+	SearchTrainAvailablePaginated(filter TrainSearchFilter, page, limit int) ([]TrainSearchRow, int, error)
 	GetStationByID(id uint) (models.Station, error)
 	GetStationByID2(id uint) (models.Station, error)
 	GetStationByID3(id uint) (models.Station, error)
@@ -25,6 +29,55 @@ type TrainRepository interface {
 	ForceDeleteTrain(id uint) error
 }
 
+// TrainSearchFilter narrows a train search. PurchaseDate is the day the fare is
+// quoted on, an empty TravelDate skips the operating day check and prices the
+// fare for a service on PurchaseDate.
+type TrainSearchFilter struct {
+	StationOriginIDs      []uint
+	StationDestinationIDs []uint
+	TrainID               uint
+	Class                 string
+	TravelDate            string
+	PurchaseDate          string
+	SortByPrice           string
+	SortByArriveTime      string
+}
+
+// TrainSearchRow is one carriage class of a train between an origin and a
+// destination station.
+type TrainSearchRow struct {
+	TrainCarriageID       uint
+	TrainID               uint
+	CodeTrain             string
+	Name                  string
+	Class                 string
+	CarriagePrice         int
+	Status                string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	OriginStationID       uint
+	OriginOrigin          string
+	OriginName            string
+	OriginInitial         string
+	OriginArriveTime      string
+	OriginSequence        int
+	OriginDayOffset       int
+	OriginDistanceKm      int
+	DestinationStationID  uint
+	DestinationOrigin     string
+	DestinationName       string
+	DestinationInitial    string
+	DestinationArriveTime string
+	DestinationSequence   int
+	DestinationDayOffset  int
+	DestinationDistanceKm int
+	ServiceDate           time.Time
+	BaseFare              int
+	PeakSurcharge         int
+	AdvanceDiscount       int
+	Price                 int
+}
+
 type trainRepository struct {
 	db *gorm.DB
 }
@@ -127,10 +180,19 @@ func (r *trainRepository) GetTrainByID2(id uint) (models.Train, error) {
 
 func (r *trainRepository) GetTrainStationByTrainID(id uint) ([]models.TrainStation, error) {
 	var train []models.TrainStation
-	err := r.db.Where("train_id = ?", id).Find(&train).Error
+	err := r.db.Where("train_id = ?", id).Order("sequence ASC").Find(&train).Error
 	return train, err
 }
 
+func (r *trainRepository) GetAllTrainStationsAvailable() ([]models.TrainStation, error) {
+	var trainStations []models.TrainStation
+	err := r.db.Joins("JOIN trains ON trains.id = train_stations.train_id").
+		Where("trains.status = ? AND trains.deleted_at IS NULL", "available").
+		Order("train_stations.train_id ASC, train_stations.sequence ASC").
+		Find(&trainStations).Error
+	return trainStations, err
+}
+
 func (r *trainRepository) GetTrainCarriageByTrainID(id uint) ([]models.TrainCarriage, error) {
 	var train []models.TrainCarriage
 	err := r.db.Where("train_id = ?", id).Find(&train).Error
@@ -145,7 +207,7 @@ func (r *trainRepository) TrainStationByTrainID(id uint) (models.TrainStation, e
 
 func (r *trainRepository) SearchTrainAvailable(trainID, originID, destinationID uint) ([]models.TrainStation, error) {
 	var train []models.TrainStation
-	err := r.db.Where("train_id = ? AND station_id IN (?, ?)", trainID, originID, destinationID).Find(&train).Error
+	err := r.db.Where("train_id = ? AND station_id IN (?, ?)", trainID, originID, destinationID).Order("sequence ASC").Find(&train).Error
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +215,132 @@ func (r *trainRepository) SearchTrainAvailable(trainID, originID, destinationID
 	return train, nil
 }
 
+// SearchTrainAvailablePaginated matches, filters, prices, sorts and pages the
+// available trains in the database. The price and its parts mirror the adult fare quote.
+func (r *trainRepository) SearchTrainAvailablePaginated(filter TrainSearchFilter, page, limit int) ([]TrainSearchRow, int, error) {
+	var (
+		rows  []TrainSearchRow
+		count int64
+	)
+	if len(filter.StationOriginIDs) == 0 || len(filter.StationDestinationIDs) == 0 {
+		return rows, 0, nil
+	}
+
+	serviceDate, serviceDateArgs := "CAST(? AS DATE)", []interface{}{filter.PurchaseDate}
+	if filter.TravelDate != "" {
+		serviceDate, serviceDateArgs = "DATE_SUB(CAST(? AS DATE), INTERVAL tso.day_offset DAY)", []interface{}{filter.TravelDate}
+	}
+
+	var args []interface{}
+	args = append(args, serviceDateArgs...)
+	args = append(args, filter.StationOriginIDs, filter.StationDestinationIDs)
+	where := ""
+	if filter.TrainID != 0 {
+		where += " AND t.id = ?"
+		args = append(args, filter.TrainID)
+	}
+	if filter.Class != "" {
+		where += " AND LOWER(tc.class) = LOWER(?)"
+		args = append(args, filter.Class)
+	}
+
+	searchQuery := `
+		SELECT s.*,
+			s.base_fare * COALESCE((
+				SELECT MAX(tfp.surcharge_percent)
+				FROM train_fare_peaks tfp
+				WHERE tfp.train_id = s.train_id AND tfp.deleted_at IS NULL
+					AND tfp.date_start <= s.service_date AND tfp.date_end >= s.service_date
+			), 0) DIV 100 AS peak_surcharge,
+			s.base_fare * COALESCE((
+				SELECT MAX(tfa.discount_percent)
+				FROM train_fare_advances tfa
+				WHERE tfa.train_id = s.train_id AND tfa.deleted_at IS NULL
+					AND tfa.min_days_before <= DATEDIFF(s.service_date, ?)
+			), 0) DIV 100 AS advance_discount
+		FROM (
+			SELECT tc.id AS train_carriage_id, t.id AS train_id, t.code_train, t.name, tc.class,
+				tc.price AS carriage_price, t.status, t.created_at, t.updated_at,
+				tso.station_id AS origin_station_id, so.origin AS origin_origin, so.name AS origin_name,
+				so.initial AS origin_initial, tso.arrive_time AS origin_arrive_time, tso.sequence AS origin_sequence,
+				tso.day_offset AS origin_day_offset, tso.distance_km AS origin_distance_km,
+				tsd.station_id AS destination_station_id, sd.origin AS destination_origin, sd.name AS destination_name,
+				sd.initial AS destination_initial, tsd.arrive_time AS destination_arrive_time, tsd.sequence AS destination_sequence,
+				tsd.day_offset AS destination_day_offset, tsd.distance_km AS destination_distance_km,
+				` + serviceDate + ` AS service_date,
+				CASE
+					WHEN tf.id IS NULL THEN tc.price
+					WHEN tf.basis = 'distance' THEN tf.base_fare + tf.rate_per_unit * GREATEST(tsd.distance_km - tso.distance_km, 0)
+					ELSE tf.base_fare + tf.rate_per_unit * (tsd.sequence - tso.sequence)
+				END AS base_fare,
+				tsc.id AS schedule_id, tsc.operating_days, tsc.effective_from, tsc.effective_until
+			FROM train_carriages tc
+			JOIN trains t ON t.id = tc.train_id AND t.deleted_at IS NULL
+			JOIN train_stations tso ON tso.train_id = t.id AND tso.deleted_at IS NULL AND tso.station_id IN ?
+			JOIN train_stations tsd ON tsd.train_id = t.id AND tsd.deleted_at IS NULL AND tsd.station_id IN ?
+				AND tsd.sequence > tso.sequence
+			JOIN stations so ON so.id = tso.station_id
+			JOIN stations sd ON sd.id = tsd.station_id
+			LEFT JOIN train_fares tf ON tf.train_id = t.id AND tf.class = tc.class AND tf.deleted_at IS NULL
+			LEFT JOIN train_schedules tsc ON tsc.train_id = t.id AND tsc.deleted_at IS NULL
+			WHERE tc.deleted_at IS NULL AND t.status = 'available'` + where + ` AND tc.id IN (
+				SELECT MIN(id)
+				FROM train_carriages
+				WHERE deleted_at IS NULL
+				GROUP BY class, train_id
+			)
+		) s`
+	searchArgs := append([]interface{}{filter.PurchaseDate}, args...)
+
+	// An exception for the date wins over the weekly operating days
+	if filter.TravelDate != "" {
+		searchQuery += `
+		WHERE COALESCE((
+			SELECT tse.is_running
+			FROM train_schedule_exceptions tse
+			WHERE tse.train_id = s.train_id AND tse.date = s.service_date AND tse.deleted_at IS NULL
+			ORDER BY tse.id ASC
+			LIMIT 1
+		), s.schedule_id IS NULL OR (
+			(s.effective_from IS NULL OR s.service_date >= s.effective_from)
+			AND (s.effective_until IS NULL OR s.service_date <= s.effective_until)
+			AND FIND_IN_SET(LOWER(DAYNAME(s.service_date)), s.operating_days) > 0
+		)) = 1`
+	}
+	searchQuery = `
+		SELECT p.*, GREATEST(p.base_fare + p.peak_surcharge - p.advance_discount, 0) AS price
+		FROM (` + searchQuery + `
+		) p`
+
+	err := r.db.Raw("SELECT COUNT(*) FROM ("+searchQuery+") c", searchArgs...).Scan(&count).Error
+	if err != nil {
+		return rows, 0, err
+	}
+
+	// A stop past midnight of the service day departs on a later date than its clock time suggests
+	departureAt := "TIMESTAMP(DATE_ADD(service_date, INTERVAL origin_day_offset DAY), origin_arrive_time)"
+	orderBy := ""
+	switch strings.ToLower(filter.SortByArriveTime) {
+	case "asc":
+		orderBy += departureAt + " ASC, "
+	case "desc":
+		orderBy += departureAt + " DESC, "
+	}
+	switch strings.ToLower(filter.SortByPrice) {
+	case "asc":
+		orderBy += "price ASC, "
+	case "desc":
+		orderBy += "price DESC, "
+	}
+	orderBy += "train_carriage_id ASC, origin_station_id ASC, destination_station_id ASC"
+
+	offset := (page - 1) * limit
+	searchArgs = append(searchArgs, limit, offset)
+	err = r.db.Raw(searchQuery+" ORDER BY "+orderBy+" LIMIT ? OFFSET ?", searchArgs...).Scan(&rows).Error
+
+	return rows, int(count), err
+}
+
 func (r *trainRepository) GetStationByID(id uint) (models.Station, error) {
 	var station models.Station
 	err := r.db.Where("id = ?", id).Find(&station).Error
@@ -7,6 +7,8 @@ import (
 )
 
 type TrainCarriageRepository interface {
+	BeginTransaction() *gorm.DB
+	WithTx(tx *gorm.DB) TrainCarriageRepository
 	GetAllTrainCarriages(page, limit int) ([]models.TrainCarriage, int, error)
 	GetAllTrainCarriages2() ([]models.TrainCarriage, error)
 	GetTrainCarriageByID(id uint) (models.TrainCarriage, error)
@@ -15,6 +17,10 @@ type TrainCarriageRepository interface {
 	GetTrainByID2(id uint) (models.Train, error)
 	GetStationByID2(id uint) (models.Station, error)
 	GetTrainSeatsByClass(class string) ([]models.TrainSeat, error)
+	GetTrainSeatsByTrainCarriageID(trainCarriageID uint) ([]models.TrainSeat, error)
+	GetTrainCarriagesBySeatLayoutID(seatLayoutID uint) ([]models.TrainCarriage, error)
+	CreateTrainSeats(trainSeats []models.TrainSeat) error
+	DeleteTrainSeatsByTrainCarriageID(trainCarriageID uint) error
 	CreateTrainCarriage(trainCarriage models.TrainCarriage) (models.TrainCarriage, error)
 	UpdateTrainCarriage(trainCarriage models.TrainCarriage) (models.TrainCarriage, error)
 	DeleteTrainCarriage(id uint) error
@@ -28,6 +34,14 @@ func NewTrainCarriageRepository(db *gorm.DB) TrainCarriageRepository {
 	return &trainCarriageRepository{db}
 }
 
+func (r *trainCarriageRepository) BeginTransaction() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *trainCarriageRepository) WithTx(tx *gorm.DB) TrainCarriageRepository {
+	return &trainCarriageRepository{tx}
+}
+
 // Implementasi fungsi-fungsi dari interface ItemRepository
 
 func (r *trainCarriageRepository) GetAllTrainCarriages(page, limit int) ([]models.TrainCarriage, int, error) {
@@ -91,10 +105,32 @@ func (r *trainCarriageRepository) GetStationByID2(id uint) (models.Station, erro
 
 func (r *trainCarriageRepository) GetTrainSeatsByClass(class string) ([]models.TrainSeat, error) {
 	var trainSeats []models.TrainSeat
-	err := r.db.Where("class = ?", class).Find(&trainSeats).Error
+	err := r.db.Where("class = ? AND train_carriage_id = ?", class, 0).Find(&trainSeats).Error
 	return trainSeats, err
 }
 
+func (r *trainCarriageRepository) GetTrainSeatsByTrainCarriageID(trainCarriageID uint) ([]models.TrainSeat, error) {
+	var trainSeats []models.TrainSeat
+	err := r.db.Where("train_carriage_id = ?", trainCarriageID).Order("`row` ASC, `column` ASC").Find(&trainSeats).Error
+	return trainSeats, err
+}
+
+func (r *trainCarriageRepository) GetTrainCarriagesBySeatLayoutID(seatLayoutID uint) ([]models.TrainCarriage, error) {
+	var trainCarriages []models.TrainCarriage
+	err := r.db.Where("seat_layout_id = ?", seatLayoutID).Find(&trainCarriages).Error
+	return trainCarriages, err
+}
+
+func (r *trainCarriageRepository) CreateTrainSeats(trainSeats []models.TrainSeat) error {
+	err := r.db.Create(&trainSeats).Error
+	return err
+}
+
+func (r *trainCarriageRepository) DeleteTrainSeatsByTrainCarriageID(trainCarriageID uint) error {
+	err := r.db.Unscoped().Where("train_carriage_id = ?", trainCarriageID).Delete(&models.TrainSeat{}).Error
+	return err
+}
+
 func (r *trainCarriageRepository) CreateTrainCarriage(trainCarriage models.TrainCarriage) (models.TrainCarriage, error) {
 	err := r.db.Create(&trainCarriage).Error
 	return trainCarriage, err
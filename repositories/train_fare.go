@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type TrainFareRepository interface {
+	GetTrainFaresByTrainID(trainID uint) ([]models.TrainFare, error)
+	GetTrainFareByTrainIDAndClass(trainID uint, class string) (models.TrainFare, error)
+	SaveTrainFare(trainFare models.TrainFare) (models.TrainFare, error)
+	GetTrainFarePeaksByTrainID(trainID uint) ([]models.TrainFarePeak, error)
+	GetTrainFarePeakByID(id uint) (models.TrainFarePeak, error)
+	GetTrainFarePeakByTrainIDAndDate(trainID uint, date string) (models.TrainFarePeak, error)
+	CreateTrainFarePeak(trainFarePeak models.TrainFarePeak) (models.TrainFarePeak, error)
+	DeleteTrainFarePeak(trainFarePeak models.TrainFarePeak) error
+	GetTrainFareAdvancesByTrainID(trainID uint) ([]models.TrainFareAdvance, error)
+	GetTrainFareAdvanceByID(id uint) (models.TrainFareAdvance, error)
+	GetTrainFareAdvanceByTrainIDAndDays(trainID uint, daysBefore int) (models.TrainFareAdvance, error)
+	CreateTrainFareAdvance(trainFareAdvance models.TrainFareAdvance) (models.TrainFareAdvance, error)
+	DeleteTrainFareAdvance(trainFareAdvance models.TrainFareAdvance) error
+}
+
+type trainFareRepository struct {
+	db *gorm.DB
+}
+
+func NewTrainFareRepository(db *gorm.DB) TrainFareRepository {
+	return &trainFareRepository{db}
+}
+
+func (r *trainFareRepository) GetTrainFaresByTrainID(trainID uint) ([]models.TrainFare, error) {
+	var trainFares []models.TrainFare
+	err := r.db.Where("train_id = ?", trainID).Order("class ASC").Find(&trainFares).Error
+	return trainFares, err
+}
+
+func (r *trainFareRepository) GetTrainFareByTrainIDAndClass(trainID uint, class string) (models.TrainFare, error) {
+	var trainFare models.TrainFare
+	err := r.db.Where("train_id = ? AND class = ?", trainID, class).First(&trainFare).Error
+	return trainFare, err
+}
+
+func (r *trainFareRepository) SaveTrainFare(trainFare models.TrainFare) (models.TrainFare, error) {
+	err := r.db.Save(&trainFare).Error
+	return trainFare, err
+}
+
+func (r *trainFareRepository) GetTrainFarePeaksByTrainID(trainID uint) ([]models.TrainFarePeak, error) {
+	var trainFarePeaks []models.TrainFarePeak
+	err := r.db.Where("train_id = ?", trainID).Order("date_start ASC").Find(&trainFarePeaks).Error
+	return trainFarePeaks, err
+}
+
+func (r *trainFareRepository) GetTrainFarePeakByID(id uint) (models.TrainFarePeak, error) {
+	var trainFarePeak models.TrainFarePeak
+	err := r.db.Where("id = ?", id).First(&trainFarePeak).Error
+	return trainFarePeak, err
+}
+
+// GetTrainFarePeakByTrainIDAndDate returns the highest surcharge of the peak periods covering date.
+func (r *trainFareRepository) GetTrainFarePeakByTrainIDAndDate(trainID uint, date string) (models.TrainFarePeak, error) {
+	var trainFarePeak models.TrainFarePeak
+	err := r.db.Where("train_id = ? AND date_start <= ? AND date_end >= ?", trainID, date, date).Order("surcharge_percent DESC").First(&trainFarePeak).Error
+	return trainFarePeak, err
+}
+
+func (r *trainFareRepository) CreateTrainFarePeak(trainFarePeak models.TrainFarePeak) (models.TrainFarePeak, error) {
+	err := r.db.Create(&trainFarePeak).Error
+	return trainFarePeak, err
+}
+
+func (r *trainFareRepository) DeleteTrainFarePeak(trainFarePeak models.TrainFarePeak) error {
+	err := r.db.Delete(&trainFarePeak).Error
+	return err
+}
+
+func (r *trainFareRepository) GetTrainFareAdvancesByTrainID(trainID uint) ([]models.TrainFareAdvance, error) {
+	var trainFareAdvances []models.TrainFareAdvance
+	err := r.db.Where("train_id = ?", trainID).Order("min_days_before ASC").Find(&trainFareAdvances).Error
+	return trainFareAdvances, err
+}
+
+func (r *trainFareRepository) GetTrainFareAdvanceByID(id uint) (models.TrainFareAdvance, error) {
+	var trainFareAdvance models.TrainFareAdvance
+	err := r.db.Where("id = ?", id).First(&trainFareAdvance).Error
+	return trainFareAdvance, err
+}
+
+// GetTrainFareAdvanceByTrainIDAndDays returns the highest discount a purchase daysBefore the service qualifies for.
+func (r *trainFareRepository) GetTrainFareAdvanceByTrainIDAndDays(trainID uint, daysBefore int) (models.TrainFareAdvance, error) {
+	var trainFareAdvance models.TrainFareAdvance
+	err := r.db.Where("train_id = ? AND min_days_before <= ?", trainID, daysBefore).Order("discount_percent DESC").First(&trainFareAdvance).Error
+	return trainFareAdvance, err
+}
+
+func (r *trainFareRepository) CreateTrainFareAdvance(trainFareAdvance models.TrainFareAdvance) (models.TrainFareAdvance, error) {
+	err := r.db.Create(&trainFareAdvance).Error
+	return trainFareAdvance, err
+}
+
+func (r *trainFareRepository) DeleteTrainFareAdvance(trainFareAdvance models.TrainFareAdvance) error {
+	err := r.db.Delete(&trainFareAdvance).Error
+	return err
+}
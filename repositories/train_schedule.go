@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type TrainScheduleRepository interface {
+	GetTrainScheduleByTrainID(trainID uint) (models.TrainSchedule, error)
+	SaveTrainSchedule(trainSchedule models.TrainSchedule) (models.TrainSchedule, error)
+	GetTrainScheduleExceptionsByTrainID(trainID uint) ([]models.TrainScheduleException, error)
+	GetTrainScheduleExceptionByID(id uint) (models.TrainScheduleException, error)
+	GetTrainScheduleExceptionByTrainIDAndDate(trainID uint, date string) (models.TrainScheduleException, error)
+	CreateTrainScheduleException(trainScheduleException models.TrainScheduleException) (models.TrainScheduleException, error)
+	DeleteTrainScheduleException(trainScheduleException models.TrainScheduleException) error
+}
+
+type trainScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewTrainScheduleRepository(db *gorm.DB) TrainScheduleRepository {
+	return &trainScheduleRepository{db}
+}
+
+func (r *trainScheduleRepository) GetTrainScheduleByTrainID(trainID uint) (models.TrainSchedule, error) {
+	var trainSchedule models.TrainSchedule
+	err := r.db.Where("train_id = ?", trainID).First(&trainSchedule).Error
+	return trainSchedule, err
+}
+
+func (r *trainScheduleRepository) SaveTrainSchedule(trainSchedule models.TrainSchedule) (models.TrainSchedule, error) {
+	err := r.db.Save(&trainSchedule).Error
+	return trainSchedule, err
+}
+
+func (r *trainScheduleRepository) GetTrainScheduleExceptionsByTrainID(trainID uint) ([]models.TrainScheduleException, error) {
+	var trainScheduleExceptions []models.TrainScheduleException
+	err := r.db.Where("train_id = ?", trainID).Order("date ASC").Find(&trainScheduleExceptions).Error
+	return trainScheduleExceptions, err
+}
+
+func (r *trainScheduleRepository) GetTrainScheduleExceptionByID(id uint) (models.TrainScheduleException, error) {
+	var trainScheduleException models.TrainScheduleException
+	err := r.db.Where("id = ?", id).First(&trainScheduleException).Error
+	return trainScheduleException, err
+}
+
+func (r *trainScheduleRepository) GetTrainScheduleExceptionByTrainIDAndDate(trainID uint, date string) (models.TrainScheduleException, error) {
+	var trainScheduleException models.TrainScheduleException
+	err := r.db.Where("train_id = ? AND date = ?", trainID, date).First(&trainScheduleException).Error
+	return trainScheduleException, err
+}
+
+func (r *trainScheduleRepository) CreateTrainScheduleException(trainScheduleException models.TrainScheduleException) (models.TrainScheduleException, error) {
+	err := r.db.Create(&trainScheduleException).Error
+	return trainScheduleException, err
+}
+
+func (r *trainScheduleRepository) DeleteTrainScheduleException(trainScheduleException models.TrainScheduleException) error {
+	err := r.db.Unscoped().Delete(&trainScheduleException).Error
+	return err
+}
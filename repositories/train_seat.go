@@ -4,10 +4,13 @@ import (
 	"back-end-golang/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TrainSeatRepository interface {
+	WithTx(tx *gorm.DB) TrainSeatRepository
 	GetTrainSeatByID(id uint) (models.TrainSeat, error)
+	GetTrainSeatByIDForUpdate(id uint) (models.TrainSeat, error)
 	GetTrainSeatByClass(class string) ([]models.TrainSeat, error)
 }
 
@@ -19,14 +22,26 @@ func NewTrainSeatRepository(db *gorm.DB) TrainSeatRepository {
 	return &trainSeatRepository{db}
 }
 
+func (r *trainSeatRepository) WithTx(tx *gorm.DB) TrainSeatRepository {
+	return &trainSeatRepository{tx}
+}
+
 func (r *trainSeatRepository) GetTrainSeatByID(id uint) (models.TrainSeat, error) {
 	var trainSeat models.TrainSeat
 	err := r.db.Where("id = ?", id).First(&trainSeat).Error
 	return trainSeat, err
 }
 
+// GetTrainSeatByIDForUpdate locks the seat row until the surrounding transaction ends, so holding or
+// selling the seat checks and claims it one request at a time.
+func (r *trainSeatRepository) GetTrainSeatByIDForUpdate(id uint) (models.TrainSeat, error) {
+	var trainSeat models.TrainSeat
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&trainSeat).Error
+	return trainSeat, err
+}
+
 func (r *trainSeatRepository) GetTrainSeatByClass(class string) ([]models.TrainSeat, error) {
 	var trainSeat []models.TrainSeat
-	err := r.db.Where("class = ?", class).Find(&trainSeat).Error
+	err := r.db.Where("class = ? AND train_carriage_id = ?", class, 0).Find(&trainSeat).Error
 	return trainSeat, err
 }
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrainSeatHoldRepository interface {
+	BeginTransaction() *gorm.DB
+	WithTx(tx *gorm.DB) TrainSeatHoldRepository
+	GetTrainSeatHoldByID(id uint) (models.TrainSeatHold, error)
+	GetTrainSeatHoldsByUserID(userID uint) ([]models.TrainSeatHold, error)
+	GetTrainSeatHoldBySegment(exceptUserID, trainID, trainSeatID uint, date string, originSequence, destinationSequence int) (models.TrainSeatHold, error)
+	CreateTrainSeatHold(trainSeatHold models.TrainSeatHold) (models.TrainSeatHold, error)
+	DeleteTrainSeatHold(trainSeatHold models.TrainSeatHold) error
+	DeleteTrainSeatHoldsByUserIDAndTrainSeatID(userID, trainID, trainSeatID uint, date string) error
+	DeleteExpiredTrainSeatHolds() error
+}
+
+type trainSeatHoldRepository struct {
+	db *gorm.DB
+}
+
+func NewTrainSeatHoldRepository(db *gorm.DB) TrainSeatHoldRepository {
+	return &trainSeatHoldRepository{db}
+}
+
+func (r *trainSeatHoldRepository) BeginTransaction() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *trainSeatHoldRepository) WithTx(tx *gorm.DB) TrainSeatHoldRepository {
+	return &trainSeatHoldRepository{tx}
+}
+
+func (r *trainSeatHoldRepository) GetTrainSeatHoldByID(id uint) (models.TrainSeatHold, error) {
+	var trainSeatHold models.TrainSeatHold
+	err := r.db.Where("id = ?", id).First(&trainSeatHold).Error
+	return trainSeatHold, err
+}
+
+func (r *trainSeatHoldRepository) GetTrainSeatHoldsByUserID(userID uint) ([]models.TrainSeatHold, error) {
+	var trainSeatHolds []models.TrainSeatHold
+	err := r.db.Where("user_id = ? AND expired_at > ?", userID, time.Now()).Order("expired_at ASC").Find(&trainSeatHolds).Error
+	return trainSeatHolds, err
+}
+
+// GetTrainSeatHoldBySegment returns an active hold of another user on the service departing on date
+// whose segment overlaps the given stop sequence range. An exceptUserID of zero matches every user.
+func (r *trainSeatHoldRepository) GetTrainSeatHoldBySegment(exceptUserID, trainID, trainSeatID uint, date string, originSequence, destinationSequence int) (models.TrainSeatHold, error) {
+	var trainSeatHold models.TrainSeatHold
+	err := r.db.Where("train_id = ? AND train_seat_id = ? AND service_date = ? AND expired_at > ?", trainID, trainSeatID, date, time.Now()).
+		Where("origin_sequence < ? AND destination_sequence > ?", destinationSequence, originSequence).
+		Where("user_id <> ?", exceptUserID).
+		First(&trainSeatHold).Error
+	return trainSeatHold, err
+}
+
+func (r *trainSeatHoldRepository) CreateTrainSeatHold(trainSeatHold models.TrainSeatHold) (models.TrainSeatHold, error) {
+	err := r.db.Create(&trainSeatHold).Error
+	return trainSeatHold, err
+}
+
+func (r *trainSeatHoldRepository) DeleteTrainSeatHold(trainSeatHold models.TrainSeatHold) error {
+	err := r.db.Unscoped().Delete(&trainSeatHold).Error
+	return err
+}
+
+func (r *trainSeatHoldRepository) DeleteTrainSeatHoldsByUserIDAndTrainSeatID(userID, trainID, trainSeatID uint, date string) error {
+	err := r.db.Unscoped().Where("user_id = ? AND train_id = ? AND train_seat_id = ? AND service_date = ?", userID, trainID, trainSeatID, date).Delete(&models.TrainSeatHold{}).Error
+	return err
+}
+
+func (r *trainSeatHoldRepository) DeleteExpiredTrainSeatHolds() error {
+	err := r.db.Unscoped().Where("expired_at <= ?", time.Now()).Delete(&models.TrainSeatHold{}).Error
+	return err
+}
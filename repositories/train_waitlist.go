@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"back-end-golang/models"
+
+	"gorm.io/gorm"
+)
+
+type TrainWaitlistRepository interface {
+	WithTx(tx *gorm.DB) TrainWaitlistRepository
+	GetTrainWaitlistByID(id uint) (models.TrainWaitlist, error)
+	GetTrainWaitlistsByUserID(userID uint) ([]models.TrainWaitlist, error)
+	GetActiveTrainWaitlist(userID, trainID uint, class, date string, originSequence, destinationSequence int) (models.TrainWaitlist, error)
+	GetWaitingTrainWaitlists() ([]models.TrainWaitlist, error)
+	GetWaitingTrainWaitlistsByTrain(trainID uint, class, date string) ([]models.TrainWaitlist, error)
+	GetOfferedTrainWaitlists() ([]models.TrainWaitlist, error)
+	CountWaitingTrainWaitlistsBefore(trainWaitlist models.TrainWaitlist) (int, error)
+	CreateTrainWaitlist(trainWaitlist models.TrainWaitlist) (models.TrainWaitlist, error)
+	UpdateTrainWaitlist(trainWaitlist models.TrainWaitlist) (models.TrainWaitlist, error)
+}
+
+type trainWaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewTrainWaitlistRepository(db *gorm.DB) TrainWaitlistRepository {
+	return &trainWaitlistRepository{db}
+}
+
+func (r *trainWaitlistRepository) WithTx(tx *gorm.DB) TrainWaitlistRepository {
+	return &trainWaitlistRepository{tx}
+}
+
+func (r *trainWaitlistRepository) GetTrainWaitlistByID(id uint) (models.TrainWaitlist, error) {
+	var trainWaitlist models.TrainWaitlist
+	err := r.db.Where("id = ?", id).First(&trainWaitlist).Error
+	return trainWaitlist, err
+}
+
+func (r *trainWaitlistRepository) GetTrainWaitlistsByUserID(userID uint) ([]models.TrainWaitlist, error) {
+	var trainWaitlists []models.TrainWaitlist
+	err := r.db.Where("user_id = ?", userID).Order("service_date DESC, id DESC").Find(&trainWaitlists).Error
+	return trainWaitlists, err
+}
+
+// GetActiveTrainWaitlist returns the waiting or offered entry of the user for the same train, class,
+// service date and segment.
+func (r *trainWaitlistRepository) GetActiveTrainWaitlist(userID, trainID uint, class, date string, originSequence, destinationSequence int) (models.TrainWaitlist, error) {
+	var trainWaitlist models.TrainWaitlist
+	err := r.db.Where("user_id = ? AND train_id = ? AND class = ? AND service_date = ?", userID, trainID, class, date).
+		Where("origin_sequence = ? AND destination_sequence = ?", originSequence, destinationSequence).
+		Where("status IN ?", []string{"waiting", "offered"}).
+		First(&trainWaitlist).Error
+	return trainWaitlist, err
+}
+
+// GetWaitingTrainWaitlists returns every waiting entry in queue order.
+func (r *trainWaitlistRepository) GetWaitingTrainWaitlists() ([]models.TrainWaitlist, error) {
+	var trainWaitlists []models.TrainWaitlist
+	err := r.db.Where("status = ?", "waiting").Order("created_at ASC, id ASC").Find(&trainWaitlists).Error
+	return trainWaitlists, err
+}
+
+// GetWaitingTrainWaitlistsByTrain returns the waiting entries of a class of a train on a service date
+// in queue order.
+func (r *trainWaitlistRepository) GetWaitingTrainWaitlistsByTrain(trainID uint, class, date string) ([]models.TrainWaitlist, error) {
+	var trainWaitlists []models.TrainWaitlist
+	err := r.db.Where("status = ? AND train_id = ? AND class = ? AND service_date = ?", "waiting", trainID, class, date).
+		Order("created_at ASC, id ASC").
+		Find(&trainWaitlists).Error
+	return trainWaitlists, err
+}
+
+func (r *trainWaitlistRepository) GetOfferedTrainWaitlists() ([]models.TrainWaitlist, error) {
+	var trainWaitlists []models.TrainWaitlist
+	err := r.db.Where("status = ?", "offered").Find(&trainWaitlists).Error
+	return trainWaitlists, err
+}
+
+// CountWaitingTrainWaitlistsBefore counts the entries queued ahead of trainWaitlist for the same class
+// of the train on the same service date.
+func (r *trainWaitlistRepository) CountWaitingTrainWaitlistsBefore(trainWaitlist models.TrainWaitlist) (int, error) {
+	var count int64
+	err := r.db.Model(&models.TrainWaitlist{}).
+		Where("status = ? AND train_id = ? AND class = ? AND service_date = ?", "waiting", trainWaitlist.TrainID, trainWaitlist.Class, trainWaitlist.ServiceDate).
+		Where("created_at < ? OR (created_at = ? AND id < ?)", trainWaitlist.CreatedAt, trainWaitlist.CreatedAt, trainWaitlist.ID).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *trainWaitlistRepository) CreateTrainWaitlist(trainWaitlist models.TrainWaitlist) (models.TrainWaitlist, error) {
+	err := r.db.Create(&trainWaitlist).Error
+	return trainWaitlist, err
+}
+
+func (r *trainWaitlistRepository) UpdateTrainWaitlist(trainWaitlist models.TrainWaitlist) (models.TrainWaitlist, error) {
+	err := r.db.Save(&trainWaitlist).Error
+	return trainWaitlist, err
+}
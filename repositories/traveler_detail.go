@@ -7,6 +7,7 @@ import (
 )
 
 type TravelerDetailRepository interface {
+	WithTx(tx *gorm.DB) TravelerDetailRepository
 	GetAllTravelerDetails(page, limit int) ([]models.TravelerDetail, int, error)
 	GetTravelerDetailByID(id uint) (models.TravelerDetail, error)
 	GetTravelerDetailByTicketOrderID2(ticketOrderID uint) ([]models.TravelerDetail, error)
@@ -23,6 +24,10 @@ func NewTravelerDetailRepository(db *gorm.DB) TravelerDetailRepository {
 	return &travelerDetailRepository{db}
 }
 
+func (r *travelerDetailRepository) WithTx(tx *gorm.DB) TravelerDetailRepository {
+	return &travelerDetailRepository{tx}
+}
+
 func (r *travelerDetailRepository) GetAllTravelerDetails(page, limit int) ([]models.TravelerDetail, int, error) {
 	var (
 		travelerDetails []models.TravelerDetail
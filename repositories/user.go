@@ -36,14 +36,14 @@ func (r *userRepository) UserGetAll(page, limit int, search string) ([]models.Us
 
 	offset := (page - 1) * limit
 
-	err = r.db.Unscoped().Where("role = 'user' AND full_name LIKE ? OR role = 'user' AND email LIKE ? OR role = 'user' AND phone_number LIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&users).Error
+	err = r.db.Unscoped().Where("role IN ('user', 'staff') AND (full_name LIKE ? OR email LIKE ? OR phone_number LIKE ?)", "%"+search+"%", "%"+search+"%", "%"+search+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&users).Error
 
 	return users, int(count), err
 }
 
 func (r *userRepository) UserGetDetail(id uint, isDeleted bool) (models.User, error) {
 	var user models.User
-	err := r.db.Unscoped().Where("id = ? AND role = 'user'", id).First(&user).Error
+	err := r.db.Unscoped().Where("id = ? AND role IN ('user', 'staff')", id).First(&user).Error
 	if isDeleted {
 		user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
 		err = r.db.Where("id = ?", id).Save(user).Error
@@ -62,7 +62,7 @@ func (r *userRepository) UserGetById(id uint) (models.User, error) {
 
 func (r *userRepository) UserGetById2(id uint) (models.User, error) {
 	var user models.User
-	err := r.db.Unscoped().Where("id = ? AND role = 'user'", id).First(&user).Error
+	err := r.db.Unscoped().Where("id = ? AND role IN ('user', 'staff')", id).First(&user).Error
 	return user, err
 }
 
@@ -7,6 +7,7 @@ import (
 	"back-end-golang/usecases"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -38,8 +39,12 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	cloudinaryUsecase := usecases.NewMediaUpload()
 	cloudinaryController := controllers.NewCloudinaryController(cloudinaryUsecase)
 
+	regionRepository := repositories.NewRegionRepository(db)
+	regionUsecase := usecases.NewRegionUsecase(regionRepository)
+	regionController := controllers.NewRegionController(regionUsecase)
+
 	stationRepository := repositories.NewStationRepository(db)
-	stationUsecase := usecases.NewStationUsecase(stationRepository)
+	stationUsecase := usecases.NewStationUsecase(stationRepository, regionRepository)
 	stationController := controllers.NewStationController(stationUsecase)
 
 	historySeenStationRepository := repositories.NewHistorySeenStationRepository(db)
@@ -52,14 +57,30 @@ func Init(e *echo.Echo, db *gorm.DB) {
 
 	ticketTravelerDetailRepository := repositories.NewTicketTravelerDetailRepository(db)
 
+	trainScheduleRepository := repositories.NewTrainScheduleRepository(db)
+	trainFareRepository := repositories.NewTrainFareRepository(db)
+	orderStatusHistoryRepository := repositories.NewOrderStatusHistoryRepository(db)
+
 	trainRepository := repositories.NewTrainRepository(db)
-	trainUsecase := usecases.NewTrainUsecase(trainRepository, trainStationRepository, historySeenStationUsecase)
+	trainUsecase := usecases.NewTrainUsecase(trainRepository, trainStationRepository, historySeenStationUsecase, trainScheduleRepository, trainFareRepository, stationRepository)
 	trainController := controllers.NewTrainController(trainUsecase)
 
+	trainScheduleUsecase := usecases.NewTrainScheduleUsecase(trainScheduleRepository, trainRepository)
+	trainScheduleController := controllers.NewTrainScheduleController(trainScheduleUsecase)
+
+	trainFareUsecase := usecases.NewTrainFareUsecase(trainFareRepository, trainRepository)
+	trainFareController := controllers.NewTrainFareController(trainFareUsecase)
+
+	seatLayoutRepository := repositories.NewSeatLayoutRepository(db)
+	trainSeatHoldRepository := repositories.NewTrainSeatHoldRepository(db)
+
 	trainCarriageRepository := repositories.NewTrainCarriageRepository(db)
-	trainCarriageUsecase := usecases.NewTrainCarriageUsecase(trainCarriageRepository, trainRepository, ticketTravelerDetailRepository)
+	trainCarriageUsecase := usecases.NewTrainCarriageUsecase(trainCarriageRepository, trainRepository, ticketTravelerDetailRepository, seatLayoutRepository, trainSeatHoldRepository, trainFareRepository)
 	trainCarriageController := controllers.NewTrainCarriageController(trainCarriageUsecase)
 
+	seatLayoutUsecase := usecases.NewSeatLayoutUsecase(seatLayoutRepository, trainCarriageRepository, ticketTravelerDetailRepository)
+	seatLayoutController := controllers.NewSeatLayoutController(seatLayoutUsecase)
+
 	travelerDetailRepository := repositories.NewTravelerDetailRepository(db)
 
 	trainSeatRepository := repositories.NewTrainSeatRepository(db)
@@ -73,9 +94,41 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	historySearchController := controllers.NewHistorySearchController(historySearchUsecase)
 
 	ticketOrderRepository := repositories.NewTicketOrderRepository(db)
-	ticketOrderUsecase := usecases.NewTicketOrderUsecase(ticketOrderRepository, ticketTravelerDetailRepository, travelerDetailRepository, trainCarriageRepository, trainRepository, trainSeatRepository, stationRepository, trainStationRepository, paymentRepository, userRepository, notificationRepository)
+	hotelOrderRepository := repositories.NewHotelOrderRepository(db)
+	hotelRatingsRepository := repositories.NewHotelRatingsRepository(db)
+
+	trainWaitlistRepository := repositories.NewTrainWaitlistRepository(db)
+	trainWaitlistUsecase := usecases.NewTrainWaitlistUsecase(trainWaitlistRepository, trainSeatHoldRepository, ticketTravelerDetailRepository, trainCarriageRepository, trainRepository, trainSeatRepository, stationRepository, trainStationRepository, trainScheduleRepository, notificationRepository, templateMessageRepository)
+	trainWaitlistController := controllers.NewTrainWaitlistController(trainWaitlistUsecase)
+
+	orderStateMachine := usecases.NewOrderStateMachine(ticketOrderRepository, ticketTravelerDetailRepository, trainSeatHoldRepository, hotelOrderRepository, hotelRatingsRepository, notificationRepository, orderStatusHistoryRepository, trainWaitlistUsecase)
+	orderStatusHistoryUsecase := usecases.NewOrderStatusHistoryUsecase(orderStatusHistoryRepository, ticketOrderRepository, hotelOrderRepository, orderStateMachine)
+	orderStatusHistoryController := controllers.NewOrderStatusHistoryController(orderStatusHistoryUsecase)
+
+	orderExpiryUsecase := usecases.NewOrderExpiryUsecase(ticketOrderRepository, hotelOrderRepository, trainSeatHoldRepository, orderStateMachine, trainWaitlistUsecase)
+	orderExpiryUsecase.Start(time.Minute)
+
+	savedPassengerRepository := repositories.NewSavedPassengerRepository(db)
+	savedPassengerUsecase := usecases.NewSavedPassengerUsecase(savedPassengerRepository)
+	savedPassengerController := controllers.NewSavedPassengerController(savedPassengerUsecase)
+
+	ticketOrderUsecase := usecases.NewTicketOrderUsecase(ticketOrderRepository, ticketTravelerDetailRepository, travelerDetailRepository, trainCarriageRepository, trainRepository, trainSeatRepository, stationRepository, trainStationRepository, paymentRepository, userRepository, notificationRepository, trainScheduleRepository, trainSeatHoldRepository, trainFareRepository, orderStateMachine, savedPassengerRepository)
 	ticketOrderController := controllers.NewTicketOrderController(ticketOrderUsecase)
 
+	ticketRefundRepository := repositories.NewTicketRefundRepository(db)
+	ticketRefundUsecase := usecases.NewTicketRefundUsecase(ticketRefundRepository, ticketOrderRepository, ticketTravelerDetailRepository, trainCarriageRepository, orderStateMachine)
+	ticketRefundController := controllers.NewTicketRefundController(ticketRefundUsecase)
+
+	ticketRescheduleRepository := repositories.NewTicketRescheduleRepository(db)
+	ticketRescheduleUsecase := usecases.NewTicketRescheduleUsecase(ticketRescheduleRepository, ticketOrderRepository, ticketTravelerDetailRepository, travelerDetailRepository, trainCarriageRepository, trainRepository, trainSeatRepository, trainStationRepository, trainScheduleRepository, trainSeatHoldRepository, trainFareRepository, paymentRepository, ticketRefundRepository)
+	ticketRescheduleController := controllers.NewTicketRescheduleController(ticketRescheduleUsecase)
+
+	boardingPassUsecase := usecases.NewBoardingPassUsecase(ticketOrderRepository, ticketTravelerDetailRepository, travelerDetailRepository, trainRepository, trainCarriageRepository, trainSeatRepository, stationRepository, trainStationRepository, ticketRescheduleRepository, orderStateMachine)
+	boardingPassController := controllers.NewBoardingPassController(boardingPassUsecase)
+
+	trainSeatHoldUsecase := usecases.NewTrainSeatHoldUsecase(trainSeatHoldRepository, ticketTravelerDetailRepository, trainCarriageRepository, trainRepository, trainSeatRepository, stationRepository, trainStationRepository, trainScheduleRepository, trainFareRepository)
+	trainSeatHoldController := controllers.NewTrainSeatHoldController(trainSeatHoldUsecase)
+
 	hotelRepository := repositories.NewHotelRepository(db)
 	hotelRoomRepository := repositories.NewHotelRoomRepository(db)
 	hotelRoomImageRepository := repositories.NewHotelRoomImageRepository(db)
@@ -88,11 +141,15 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	hotelRoomUsecase := usecases.NewHotelRoomUsecase(hotelRepository, hotelRoomRepository, hotelRoomImageRepository, hotelRoomFacilitiesRepository)
 	hotelRoomController := controllers.NewHotelRoomController(hotelRoomUsecase)
 
-	hotelOrderRepository := repositories.NewHotelOrderRepository(db)
-	hotelRatingsRepository := repositories.NewHotelRatingsRepository(db)
+	hotelRoomRateRepository := repositories.NewHotelRoomRateRepository(db)
+	hotelRoomRateUsecase := usecases.NewHotelRoomRateUsecase(hotelRoomRateRepository, hotelRoomRepository)
+	hotelRoomRateController := controllers.NewHotelRoomRateController(hotelRoomRateUsecase)
 
-	hotelOrderUsecase := usecases.NewHotelOrderUsecase(hotelOrderRepository, hotelRepository, hotelImageRepository, hotelFacilitiesRepository, hotelPolicyRepository, hotelRoomRepository, hotelRoomImageRepository, hotelRoomFacilitiesRepository, travelerDetailRepository, paymentRepository, userRepository, notificationRepository, hotelRatingsRepository)
+	hotelOrderUsecase := usecases.NewHotelOrderUsecase(hotelOrderRepository, hotelRepository, hotelImageRepository, hotelFacilitiesRepository, hotelPolicyRepository, hotelRoomRepository, hotelRoomImageRepository, hotelRoomFacilitiesRepository, travelerDetailRepository, paymentRepository, userRepository, notificationRepository, hotelRatingsRepository, orderStateMachine, savedPassengerRepository, hotelRoomRateRepository)
 	hotelOrderController := controllers.NewHotelOrderController(hotelOrderUsecase)
+	hotelRefundRepository := repositories.NewHotelRefundRepository(db)
+	hotelRefundUsecase := usecases.NewHotelRefundUsecase(hotelRefundRepository, hotelOrderRepository, hotelPolicyRepository, orderStateMachine)
+	hotelRefundController := controllers.NewHotelRefundController(hotelRefundUsecase)
 
 	hotelRatingsUsecase := usecases.NewHotelRatingsUsecase(hotelRatingsRepository, hotelRepository, userRepository, hotelOrderRepository, notificationRepository)
 	hotelRatingsController := controllers.NewHotelRatingsController(hotelRatingsUsecase)
@@ -101,7 +158,7 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	historySeenHotelUsecase := usecases.NewHistorySeenHotelUsecase(historySeenHotelRepository, hotelRepository, hotelImageRepository, hotelFacilitiesRepository, hotelPolicyRepository)
 	historySeenHotelController := controllers.NewHistorySeenHotelController(historySeenHotelUsecase)
 
-	hotelUsecase := usecases.NewHotelUsecase(hotelRepository, hotelRoomRepository, hotelRoomImageRepository, hotelRoomFacilitiesRepository, hotelImageRepository, hotelFacilitiesRepository, hotelPolicyRepository, historySearchRepository, hotelRatingsRepository, userRepository, historySeenHotelUsecase)
+	hotelUsecase := usecases.NewHotelUsecase(hotelRepository, hotelRoomRepository, hotelRoomImageRepository, hotelRoomFacilitiesRepository, hotelImageRepository, hotelFacilitiesRepository, hotelPolicyRepository, historySearchRepository, hotelRatingsRepository, userRepository, hotelOrderRepository, stationRepository, ticketOrderRepository, ticketTravelerDetailRepository, regionRepository, historySeenHotelUsecase)
 	hotelController := controllers.NewHotelController(hotelUsecase)
 
 	dashboardRepository := repositories.NewDashboardRepository(db)
@@ -112,7 +169,7 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	articleUsecase := usecases.NewArticleUsecase(articleRepository)
 	articleController := controllers.NewArticleController(articleUsecase)
 
-	notificationUsecase := usecases.NewNotificationUsecase(notificationRepository, templateMessageRepository, userRepository, hotelOrderRepository, ticketOrderRepository)
+	notificationUsecase := usecases.NewNotificationUsecase(notificationRepository, templateMessageRepository, userRepository, hotelOrderRepository, ticketOrderRepository, trainWaitlistRepository, trainRepository, trainCarriageRepository, trainSeatRepository)
 	notificationController := controllers.NewNotificationController(notificationUsecase)
 
 	// Middleware CORS
@@ -144,26 +201,53 @@ func Init(e *echo.Echo, db *gorm.DB) {
 
 	// train ka
 	user.GET("/train/search", trainController.SearchTrainAvailable)
+	user.GET("/train/journey", trainController.SearchTrainJourney)
+	user.GET("/train/seat-hold", trainSeatHoldController.GetTrainSeatHolds)
+	user.POST("/train/seat-hold", trainSeatHoldController.CreateTrainSeatHold)
+	user.DELETE("/train/seat-hold/:id", trainSeatHoldController.DeleteTrainSeatHold)
+	user.GET("/train/waitlist", trainWaitlistController.GetTrainWaitlists)
+	user.POST("/train/waitlist", trainWaitlistController.CreateTrainWaitlist)
+	user.DELETE("/train/waitlist/:id", trainWaitlistController.DeleteTrainWaitlist)
 	user.POST("/train/order", ticketOrderController.CreateTicketOrder)
 	user.POST("/train/order/midtrans", ticketOrderController.CreateTicketOrderMidtrans)
 	user.PATCH("/train/order", ticketOrderController.UpdateTicketOrder)
+	user.GET("/train/order/refund", ticketRefundController.QuoteTicketRefund)
+	user.POST("/train/order/refund", ticketRefundController.RequestTicketRefund)
+	user.POST("/train/order/reschedule/quote", ticketRescheduleController.QuoteTicketReschedule)
+	user.POST("/train/order/reschedule", ticketRescheduleController.CreateTicketReschedule)
+	user.PATCH("/train/order/reschedule", ticketRescheduleController.UpdateTicketReschedule)
 
 	user.GET("/hotel/search", hotelController.SearchHotelAvailable)
+	user.GET("/hotel/search/facet", hotelController.GetHotelSearchFacets)
 	user.GET("/order/ticket", ticketOrderController.GetTicketOrders)
 	user.GET("/order/ticket/detail", ticketOrderController.GetTicketOrderByID)
+	user.GET("/order/ticket/boarding-pass", boardingPassController.GetBoardingPass)
+	user.GET("/order/ticket/refund", ticketRefundController.GetTicketRefunds)
+	user.GET("/order/ticket/reschedule", ticketRescheduleController.GetTicketReschedules)
+	user.GET("/order/ticket/nearby-hotel", hotelController.GetHotelsNearTicketOrder)
+	user.GET("/order/status-history", orderStatusHistoryController.GetOrderStatusHistories)
 
 	user.POST("/hotel/order", hotelOrderController.CreateHotelOrder)
 	user.POST("/hotel/order/midtrans", hotelOrderController.CreateHotelOrder2)
 	user.PATCH("/hotel/order", hotelOrderController.UpdateHotelOrder)
+	user.PATCH("/hotel/order/room", hotelOrderController.CancelHotelOrderRoom)
+	user.POST("/hotel/order/refund", hotelRefundController.RequestHotelRefund)
 	public.GET("/transaction", controllers.CheckTransaction)
 
 	user.GET("/order/hotel", hotelOrderController.GetHotelOrders)
 	user.GET("/order/hotel/detail", hotelOrderController.GetHotelOrderByID)
+	user.GET("/order/hotel/refund", hotelRefundController.GetHotelRefunds)
 
 	user.GET("/history-search", historySearchController.HistorySearchGetAll)
 	user.POST("/history-search", historySearchController.HistorySearchCreate)
 	user.DELETE("/history-search/:id", historySearchController.HistorySearchDelete)
 
+	user.GET("/saved-passenger", savedPassengerController.GetSavedPassengers)
+	user.GET("/saved-passenger/:id", savedPassengerController.GetSavedPassengerByID)
+	user.POST("/saved-passenger", savedPassengerController.CreateSavedPassenger)
+	user.PUT("/saved-passenger/:id", savedPassengerController.UpdateSavedPassenger)
+	user.DELETE("/saved-passenger/:id", savedPassengerController.DeleteSavedPassenger)
+
 	user.GET("/history-seen-station", historySeenStationController.GetAllHistorySeenStations)
 
 	user.GET("/history-seen-hotel", historySeenHotelController.GetAllHistorySeenHotels)
@@ -176,6 +260,12 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	user.GET("/hotel-ratings-order/:id", hotelRatingsController.GetHotelRatingsByIdOrders)
 	user.GET("/hotel-ratings-all/:id", hotelRatingsController.GetAllHotelRatingsByIdHotels)
 
+	// STAFF
+	staff := api.Group("/staff")
+	staff.Use(middlewares.JWTMiddleware, middlewares.RoleMiddleware("staff", "admin"))
+
+	staff.POST("/boarding/check-in", boardingPassController.CheckInBoardingPass)
+
 	// ADMIN
 	admin := api.Group("/admin")
 	admin.Use(middlewares.JWTMiddleware, middlewares.RoleMiddleware("admin"))
@@ -190,16 +280,36 @@ func Init(e *echo.Echo, db *gorm.DB) {
 
 	admin.GET("/order/ticket", ticketOrderController.GetTicketOrdersByAdmin)
 	admin.GET("/order/ticket/detail", ticketOrderController.GetTicketOrderDetailByAdmin)
+	admin.PATCH("/order/ticket/:id/status", orderStatusHistoryController.UpdateTicketOrderStatusByAdmin)
+	admin.GET("/order/status-history", orderStatusHistoryController.GetOrderStatusHistoriesByAdmin)
+	admin.GET("/order/ticket/refund", ticketRefundController.GetTicketRefundsByAdmin)
+	admin.PUT("/order/ticket/refund/:id/approve", ticketRefundController.ApproveTicketRefund)
+	admin.PUT("/order/ticket/refund/:id/reject", ticketRefundController.RejectTicketRefund)
+	admin.GET("/order/ticket/reschedule", ticketRescheduleController.GetTicketReschedulesByAdmin)
+	admin.PUT("/order/ticket/reschedule/:id/paid", ticketRescheduleController.ConfirmTicketReschedule)
 
 	admin.GET("/order/hotel", hotelOrderController.GetHotelOrdersByAdmin)
 	admin.GET("/order/hotel/detail", hotelOrderController.GetHotelOrderDetailByAdmin)
+	admin.PATCH("/order/hotel/:id/status", orderStatusHistoryController.UpdateHotelOrderStatusByAdmin)
 	admin.POST("/order/hotel/csv", hotelOrderController.CsvHotelOrder)
+	admin.GET("/order/hotel/refund", hotelRefundController.GetHotelRefundsByAdmin)
+	admin.PUT("/order/hotel/refund/:id/approve", hotelRefundController.ApproveHotelRefund)
+	admin.PUT("/order/hotel/refund/:id/reject", hotelRefundController.RejectHotelRefund)
+
+	// crud region
+	public.GET("/region", regionController.GetRegions)
+	public.GET("/region/:id", regionController.GetRegionByID)
+	admin.POST("/region", regionController.CreateRegion)
+	admin.PUT("/region/:id", regionController.UpdateRegion)
+	admin.DELETE("/region/:id", regionController.DeleteRegion)
 
 	// crud station
 	public.GET("/station", stationController.GetAllStations)
+	public.GET("/station/autocomplete", stationController.AutocompleteStation)
 	public.GET("/station/:id", stationController.GetStationByID)
 	admin.GET("/station", stationController.GetAllStationsByAdmin)
 	admin.PUT("/station/:id", stationController.UpdateStation)
+	admin.PUT("/station/:id/location", stationController.UpdateStationLocation)
 	admin.POST("/station", stationController.CreateStation)
 	admin.DELETE("/station/:id", stationController.DeleteStation)
 
@@ -210,6 +320,19 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	admin.PUT("/train/:id", trainController.UpdateTrain)
 	admin.POST("/train", trainController.CreateTrain)
 	admin.DELETE("/train/:id", trainController.DeleteTrain)
+	admin.GET("/train/:id/schedule", trainScheduleController.GetTrainSchedule)
+	admin.PUT("/train/:id/schedule", trainScheduleController.UpdateTrainSchedule)
+	admin.POST("/train/:id/schedule/exception", trainScheduleController.CreateTrainScheduleException)
+	admin.DELETE("/train/:id/schedule/exception/:exception_id", trainScheduleController.DeleteTrainScheduleException)
+	admin.GET("/train/:id/fare", trainFareController.GetTrainFare)
+	admin.PUT("/train/:id/fare", trainFareController.UpdateTrainFare)
+	admin.POST("/train/:id/fare/peak", trainFareController.CreateTrainFarePeak)
+	admin.DELETE("/train/:id/fare/peak/:peak_id", trainFareController.DeleteTrainFarePeak)
+	admin.POST("/train/:id/fare/advance", trainFareController.CreateTrainFareAdvance)
+	admin.DELETE("/train/:id/fare/advance/:advance_id", trainFareController.DeleteTrainFareAdvance)
+	admin.GET("/train/refund-policy", ticketRefundController.GetRefundPolicies)
+	admin.PUT("/train/refund-policy", ticketRefundController.UpdateRefundPolicy)
+	admin.DELETE("/train/refund-policy/:id", ticketRefundController.DeleteRefundPolicy)
 
 	public.GET("/train-carriage", trainCarriageController.GetAllTrainCarriages)
 	public.GET("/train-carriage/:id", trainCarriageController.GetTrainCarriageByID)
@@ -217,6 +340,13 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	admin.POST("/train-carriage", trainCarriageController.CreateTrainCarriage)
 	admin.DELETE("/train-carriage/:id", trainCarriageController.DeleteTrainCarriage)
 
+	// crud seat layout
+	admin.GET("/seat-layout", seatLayoutController.GetAllSeatLayouts)
+	admin.GET("/seat-layout/:id", seatLayoutController.GetSeatLayoutByID)
+	admin.PUT("/seat-layout/:id", seatLayoutController.UpdateSeatLayout)
+	admin.POST("/seat-layout", seatLayoutController.CreateSeatLayout)
+	admin.DELETE("/seat-layout/:id", seatLayoutController.DeleteSeatLayout)
+
 	public.GET("/article", articleController.GetAllArticles)
 	public.GET("/article/:id", articleController.GetArticleByID)
 	admin.PUT("/article/:id", articleController.UpdateArticle)
@@ -232,6 +362,7 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	public.GET("/hotel", hotelController.GetAllHotels)
 	public.GET("/hotel/:id", hotelController.GetHotelByID)
 	admin.PUT("/hotel/:id", hotelController.UpdateHotel)
+	admin.PUT("/hotel/:id/location", hotelController.UpdateHotelLocation)
 	admin.POST("/hotel", hotelController.CreateHotel)
 	admin.DELETE("/hotel/:id", hotelController.DeleteHotel)
 
@@ -240,6 +371,9 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	admin.PUT("/hotel-room/:id", hotelRoomController.UpdateHotelRoom)
 	admin.POST("/hotel-room", hotelRoomController.CreateHotelRoom)
 	admin.DELETE("/hotel-room/:id", hotelRoomController.DeleteHotelRoom)
+	admin.GET("/hotel-room/:id/rate", hotelRoomRateController.GetHotelRoomRates)
+	admin.POST("/hotel-room/:id/rate", hotelRoomRateController.CreateHotelRoomRate)
+	admin.DELETE("/hotel-room/:id/rate/:rate_id", hotelRoomRateController.DeleteHotelRoomRate)
 
 	public.GET("/template-message", templateMessageController.GetAllTemplateMessages)
 	public.GET("/template-message/:id", templateMessageController.GetTemplateMessageByID)
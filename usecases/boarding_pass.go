@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+)
+
+type BoardingPassUsecase interface {
+	GetBoardingPass(userID, ticketOrderID, ticketTravelerDetailID uint) ([]byte, error)
+	CheckInBoardingPass(staffID uint, boardingCheckInInput dtos.BoardingCheckInInput) (dtos.BoardingCheckInResponse, error)
+}
+
+type boardingPassUsecase struct {
+	ticketOrderRepo          repositories.TicketOrderRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	travelerDetailRepo       repositories.TravelerDetailRepository
+	trainRepo                repositories.TrainRepository
+	trainCarriageRepo        repositories.TrainCarriageRepository
+	trainSeatRepo            repositories.TrainSeatRepository
+	stationRepo              repositories.StationRepository
+	trainStationRepo         repositories.TrainStationRepository
+	ticketRescheduleRepo     repositories.TicketRescheduleRepository
+	orderStateMachine        OrderStateMachine
+}
+
+func NewBoardingPassUsecase(ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, travelerDetailRepo repositories.TravelerDetailRepository, trainRepo repositories.TrainRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainSeatRepo repositories.TrainSeatRepository, stationRepo repositories.StationRepository, trainStationRepo repositories.TrainStationRepository, ticketRescheduleRepo repositories.TicketRescheduleRepository, orderStateMachine OrderStateMachine) BoardingPassUsecase {
+	return &boardingPassUsecase{ticketOrderRepo, ticketTravelerDetailRepo, travelerDetailRepo, trainRepo, trainCarriageRepo, trainSeatRepo, stationRepo, trainStationRepo, ticketRescheduleRepo, orderStateMachine}
+}
+
+// GetBoardingPass godoc
+// @Summary      Get boarding pass
+// @Description  Download the boarding pass PDF of one passenger, or of every passenger of the order when no ticket traveler detail is given
+// @Tags         User - Order
+// @Accept       json
+// @Produce      application/pdf
+// @Param ticket_order_id query int true "Ticket order ID"
+// @Param ticket_traveler_detail_id query int false "Ticket traveler detail ID"
+// @Success      200 {file} file
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/order/ticket/boarding-pass [get]
+// @Security BearerAuth
+func (u *boardingPassUsecase) GetBoardingPass(userID, ticketOrderID, ticketTravelerDetailID uint) ([]byte, error) {
+	ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByID(ticketOrderID, userID)
+	if err != nil {
+		return nil, errors.New("Failed to get ticket order")
+	}
+	if ticketOrder.Status != "paid" && ticketOrder.Status != "done" {
+		return nil, errors.New("Boarding passes are only issued for paid ticket orders")
+	}
+
+	ticketTravelerDetails, err := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTicketOrderID(ticketOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var boardingPasses []helpers.BoardingPass
+	for _, ticketTravelerDetail := range ticketTravelerDetails {
+		if ticketTravelerDetailID != 0 && ticketTravelerDetail.ID != ticketTravelerDetailID {
+			continue
+		}
+
+		getTravelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(ticketTravelerDetail.TravelerDetailID)
+		if err != nil {
+			return nil, err
+		}
+		getTrain, err := u.trainRepo.GetTrainByID2(ticketTravelerDetail.TrainID)
+		if err != nil {
+			return nil, err
+		}
+		getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(ticketTravelerDetail.TrainCarriageID)
+		if err != nil {
+			return nil, err
+		}
+		getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(ticketTravelerDetail.TrainSeatID)
+		if err != nil {
+			return nil, err
+		}
+		getStationOrigin, err := u.stationRepo.GetStationByID2(ticketTravelerDetail.StationOriginID)
+		if err != nil {
+			return nil, err
+		}
+		getStationDestination, err := u.stationRepo.GetStationByID2(ticketTravelerDetail.StationDestinationID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Overnight trains arrive on a later day than they leave the origin
+		arrivalDate := ticketTravelerDetail.DateOfDeparture
+		trainStationOrigin, _ := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, getStationOrigin.ID)
+		trainStationDestination, _ := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, getStationDestination.ID)
+		if trainStationOrigin.ID > 0 && trainStationDestination.ID > 0 {
+			arrivalDate = arrivalDate.AddDate(0, 0, trainStationDestination.DayOffset-trainStationOrigin.DayOffset)
+		}
+
+		passengerType := getTravelerDetail.PassengerType
+		if passengerType == "" {
+			passengerType = passengerTypeAdult
+		}
+
+		boardingPasses = append(boardingPasses, helpers.BoardingPass{
+			TicketOrderCode:    ticketOrder.TicketOrderCode,
+			BoardingTicketCode: ticketTravelerDetail.BoardingTicketCode,
+			FullName:           getTravelerDetail.Title + " " + getTravelerDetail.FullName,
+			PassengerType:      passengerType,
+			TrainName:          getTrain.Name,
+			CodeTrain:          getTrain.CodeTrain,
+			Class:              getTrainCarriage.Class,
+			TrainCarriage:      getTrainCarriage.Name,
+			TrainSeat:          getTrainSeat.Name,
+			StationOrigin:      getStationOrigin.Name + " (" + getStationOrigin.Initial + ")",
+			StationDestination: getStationDestination.Name + " (" + getStationDestination.Initial + ")",
+			DepartureDate:      helpers.FormatDateToYMD(&ticketTravelerDetail.DateOfDeparture),
+			DepartureTime:      ticketTravelerDetail.DepartureTime,
+			ArrivalDate:        helpers.FormatDateToYMD(&arrivalDate),
+			ArrivalTime:        ticketTravelerDetail.ArrivalTime,
+		})
+	}
+	if len(boardingPasses) == 0 {
+		return nil, errors.New("Failed to get ticket traveler detail")
+	}
+
+	return helpers.NewBoardingPassPDF(boardingPasses)
+}
+
+// CheckInBoardingPass godoc
+// @Summary      Check in boarding pass
+// @Description  Validate a scanned boarding pass at the gate of a station and mark the passenger as boarded, a code is accepted once
+// @Tags         Staff - Boarding
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.BoardingCheckInInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.BoardingCheckInStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /staff/boarding/check-in [post]
+// @Security BearerAuth
+func (u *boardingPassUsecase) CheckInBoardingPass(staffID uint, boardingCheckInInput dtos.BoardingCheckInInput) (dtos.BoardingCheckInResponse, error) {
+	var boardingCheckInResponse dtos.BoardingCheckInResponse
+
+	if boardingCheckInInput.BoardingTicketCode == "" || boardingCheckInInput.StationID < 1 {
+		return boardingCheckInResponse, errors.New("Failed to check in boarding pass")
+	}
+
+	boardingTicketCode, valid := helpers.VerifyBoardingTicketCode(strings.TrimSpace(boardingCheckInInput.BoardingTicketCode))
+	if !valid {
+		return boardingCheckInResponse, errors.New("Boarding pass signature is invalid")
+	}
+
+	ticketTravelerDetail, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByBoardingTicketCode(boardingTicketCode)
+	if ticketTravelerDetail.ID < 1 {
+		// A rescheduled ticket gets a new code, the printed pass of the old one must not board
+		ticketReschedule, _ := u.ticketRescheduleRepo.GetTicketRescheduleByOldBoardingTicketCode(boardingTicketCode)
+		if ticketReschedule.ID > 0 {
+			return boardingCheckInResponse, errors.New("Boarding pass has been voided by a reschedule")
+		}
+		return boardingCheckInResponse, errors.New("Boarding pass not found")
+	}
+
+	ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByIDOnly(ticketTravelerDetail.TicketOrderID)
+	if err != nil {
+		return boardingCheckInResponse, errors.New("Failed to get ticket order")
+	}
+	// A done order may still have an infant to board, see finishBoardedTicketOrder
+	if ticketOrder.Status != "paid" && ticketOrder.Status != "done" {
+		return boardingCheckInResponse, errors.New("Ticket order is not paid")
+	}
+
+	now := time.Now()
+	if helpers.FormatDateToYMD(&ticketTravelerDetail.DateOfDeparture) != now.Format("2006-01-02") {
+		return boardingCheckInResponse, errors.New("Boarding pass is not valid for today")
+	}
+	if ticketTravelerDetail.StationOriginID != boardingCheckInInput.StationID {
+		return boardingCheckInResponse, errors.New("Boarding pass is not valid for this station")
+	}
+	if ticketTravelerDetail.BoardedAt != nil {
+		return boardingCheckInResponse, errors.New("Boarding pass has already been used")
+	}
+
+	boarded, err := u.ticketTravelerDetailRepo.MarkTicketTravelerDetailBoarded(ticketTravelerDetail.ID, staffID, now)
+	if err != nil {
+		return boardingCheckInResponse, err
+	}
+	if !boarded {
+		return boardingCheckInResponse, errors.New("Boarding pass has already been used")
+	}
+
+	err = u.finishBoardedTicketOrder(ticketOrder.ID)
+	if err != nil {
+		return boardingCheckInResponse, err
+	}
+
+	getTravelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(ticketTravelerDetail.TravelerDetailID)
+	if err != nil {
+		return boardingCheckInResponse, err
+	}
+	passengerType := getTravelerDetail.PassengerType
+	if passengerType == "" {
+		passengerType = passengerTypeAdult
+	}
+
+	boardingCheckInResponse = dtos.BoardingCheckInResponse{
+		TicketOrderID:          ticketTravelerDetail.TicketOrderID,
+		TicketTravelerDetailID: ticketTravelerDetail.ID,
+		BoardingTicketCode:     ticketTravelerDetail.BoardingTicketCode,
+		FullName:               getTravelerDetail.FullName,
+		PassengerType:          passengerType,
+		TrainID:                ticketTravelerDetail.TrainID,
+		TrainCarriageID:        ticketTravelerDetail.TrainCarriageID,
+		TrainSeatID:            ticketTravelerDetail.TrainSeatID,
+		StationOriginID:        ticketTravelerDetail.StationOriginID,
+		StationDestinationID:   ticketTravelerDetail.StationDestinationID,
+		DepartureTime:          ticketTravelerDetail.DepartureTime,
+		Date:                   ticketTravelerDetail.DateOfDeparture,
+		BoardedAt:              now,
+		BoardedBy:              staffID,
+	}
+	return boardingCheckInResponse, nil
+}
+
+// finishBoardedTicketOrder moves a paid order to done once every passenger holding a seat has boarded every
+// leg. Infants ride on a lap and are not waited for, so the order is locked to finish it only once.
+func (u *boardingPassUsecase) finishBoardedTicketOrder(ticketOrderID uint) error {
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+
+	ticketOrder, err := u.ticketOrderRepo.WithTx(tx).GetTicketOrderByIDForUpdate(ticketOrderID)
+	if err != nil {
+		return err
+	}
+	if ticketOrder.Status != "paid" {
+		return nil
+	}
+
+	ticketTravelerDetails, err := u.ticketTravelerDetailRepo.WithTx(tx).GetTicketTravelerDetailByTicketOrderID(ticketOrder.ID)
+	if err != nil {
+		return err
+	}
+	for _, ticketTravelerDetail := range ticketTravelerDetails {
+		if ticketTravelerDetail.AccompaniedByID == 0 && ticketTravelerDetail.BoardedAt == nil {
+			return nil
+		}
+	}
+
+	_, err = u.orderStateMachine.WithTx(tx).TransitionTicketOrder(ticketOrder, "done", orderActorSystem, 0, "Every passenger boarded")
+	if err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
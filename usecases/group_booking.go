@@ -0,0 +1,568 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ticketTravelerItinerary is the journey booked for one traveler who takes a seat, flattened into legs.
+type ticketTravelerItinerary struct {
+	TravelerIndex int
+	Legs          []ticketTravelerDetailLeg
+}
+
+// ticketJourneyState is the last leg booked on an itinerary, a transfer leg is checked against it.
+type ticketJourneyState struct {
+	leg  journeyLeg
+	date time.Time
+}
+
+// trainSeatPosition is a free seat of a carriage placed on the seat map.
+type trainSeatPosition struct {
+	ID     uint
+	Row    int
+	Column int
+}
+
+// attachTicketOrderInfants places every infant with an adult or senior of the order, each adult holds one
+// infant at most. It returns the infant index per adult index.
+func attachTicketOrderInfants(travelerDetails []dtos.TravelerDetailInput, travelerIdentities []travelerIdentity) (map[int]int, error) {
+	infantOf := make(map[int]int)
+	isAdult := func(index int) bool {
+		passengerType := travelerIdentities[index].PassengerType
+		return passengerType == passengerTypeAdult || passengerType == passengerTypeSenior
+	}
+
+	// Infants naming their adult go first, so the rest cannot take that lap
+	for index, travelerDetail := range travelerDetails {
+		if travelerIdentities[index].PassengerType != passengerTypeInfant || travelerDetail.AccompaniedBy == nil {
+			continue
+		}
+		adultIndex := *travelerDetail.AccompaniedBy
+		if adultIndex < 0 || adultIndex >= len(travelerDetails) || !isAdult(adultIndex) {
+			return infantOf, errors.New(travelerDetail.FullName + ": accompanied by must point to an adult passenger")
+		}
+		if _, ok := infantOf[adultIndex]; ok {
+			return infantOf, errors.New(travelerDetails[adultIndex].FullName + " can only travel with one infant")
+		}
+		infantOf[adultIndex] = index
+	}
+
+	for index, travelerDetail := range travelerDetails {
+		if travelerIdentities[index].PassengerType != passengerTypeInfant || travelerDetail.AccompaniedBy != nil {
+			continue
+		}
+		attached := false
+		for adultIndex := range travelerDetails {
+			if _, ok := infantOf[adultIndex]; ok || !isAdult(adultIndex) {
+				continue
+			}
+			infantOf[adultIndex] = index
+			attached = true
+			break
+		}
+		if !attached {
+			return infantOf, errors.New("Every infant must travel with an adult")
+		}
+	}
+	return infantOf, nil
+}
+
+// assignTicketTravelerSeats pairs every traveler who takes a seat with one itinerary. Itineraries name
+// their traveler with traveler_index, the others go to the remaining travelers in order. A single
+// itinerary without seats is booked for the whole group.
+func assignTicketTravelerSeats(itineraries []dtos.TicketTravelerDetailInput, travelerDetails []dtos.TravelerDetailInput, seatedIndexes []int) ([]ticketTravelerItinerary, error) {
+	var assigned []ticketTravelerItinerary
+
+	if len(itineraries) == 1 && itineraries[0].TravelerIndex == nil && len(seatedIndexes) > 1 {
+		for _, leg := range expandTicketTravelerDetailLegs(itineraries) {
+			if leg.TrainSeatID > 0 {
+				return assigned, errors.New("Pick a seat for every passenger or leave the seats empty")
+			}
+		}
+		for range seatedIndexes[1:] {
+			itineraries = append(itineraries, itineraries[0])
+		}
+	}
+
+	if len(itineraries) != len(seatedIndexes) {
+		return assigned, errors.New("Every passenger with a seat needs exactly one ticket traveler detail")
+	}
+
+	isSeated := make(map[int]bool)
+	for _, index := range seatedIndexes {
+		isSeated[index] = true
+	}
+	taken := make(map[int]bool)
+	travelerIndexes := make([]int, len(itineraries))
+	for i, itinerary := range itineraries {
+		travelerIndexes[i] = -1
+		if itinerary.TravelerIndex == nil {
+			continue
+		}
+		index := *itinerary.TravelerIndex
+		if !isSeated[index] {
+			return assigned, fmt.Errorf("Traveler index %d is not a passenger with a seat", index)
+		}
+		if taken[index] {
+			return assigned, fmt.Errorf("Traveler index %d has more than one ticket traveler detail", index)
+		}
+		taken[index] = true
+		travelerIndexes[i] = index
+	}
+
+	next := 0
+	for i, itinerary := range itineraries {
+		if travelerIndexes[i] < 0 {
+			for taken[seatedIndexes[next]] {
+				next++
+			}
+			travelerIndexes[i] = seatedIndexes[next]
+			taken[seatedIndexes[next]] = true
+		}
+		assigned = append(assigned, ticketTravelerItinerary{
+			TravelerIndex: travelerIndexes[i],
+			Legs:          expandTicketTravelerDetailLegs([]dtos.TicketTravelerDetailInput{itinerary}),
+		})
+	}
+
+	// Keep the tickets in the order the travelers were given
+	sort.SliceStable(assigned, func(i, j int) bool {
+		return assigned[i].TravelerIndex < assigned[j].TravelerIndex
+	})
+	return assigned, nil
+}
+
+// allocateTicketTravelerSeats fills the legs booked without a seat. Legs on the same carriage, segment
+// and date are seated together, next to each other where the carriage allows it.
+func (u *ticketOrderUsecase) allocateTicketTravelerSeats(userID uint, itineraries []ticketTravelerItinerary) error {
+	type legRef struct{ itinerary, leg int }
+	var keys []string
+	unseated := make(map[string][]legRef)
+	picked := make(map[string]map[uint]bool)
+
+	for i, itinerary := range itineraries {
+		for j, leg := range itinerary.Legs {
+			key := fmt.Sprintf("%d|%d|%d|%s", leg.TrainCarriageID, leg.StationOriginID, leg.StationDestinationID, leg.Date)
+			if leg.TrainSeatID > 0 {
+				if picked[key] == nil {
+					picked[key] = make(map[uint]bool)
+				}
+				picked[key][uint(leg.TrainSeatID)] = true
+				continue
+			}
+			if _, ok := unseated[key]; !ok {
+				keys = append(keys, key)
+			}
+			unseated[key] = append(unseated[key], legRef{i, j})
+		}
+	}
+
+	for _, key := range keys {
+		refs := unseated[key]
+		leg := itineraries[refs[0].itinerary].Legs[refs[0].leg]
+		if leg.TrainCarriageID < 1 || leg.StationOriginID < 1 || leg.StationDestinationID < 1 || leg.Date == "" {
+			return errors.New("Failed to create ticket order")
+		}
+
+		freeSeats, err := u.getFreeTrainSeats(userID, leg, picked[key])
+		if err != nil {
+			return err
+		}
+		seats := pickContiguousTrainSeats(freeSeats, len(refs))
+		if len(seats) < len(refs) {
+			return errors.New("Not enough seats available in this train carriage")
+		}
+		for k, ref := range refs {
+			itineraries[ref.itinerary].Legs[ref.leg].TrainSeatID = int(seats[k].ID)
+		}
+	}
+	return nil
+}
+
+// getFreeTrainSeats lists the seats of the carriage nobody holds or booked on the segment of leg, sorted
+// by their place on the seat map.
+func (u *ticketOrderUsecase) getFreeTrainSeats(userID uint, leg ticketTravelerDetailLeg, exclude map[uint]bool) ([]trainSeatPosition, error) {
+	var freeSeats []trainSeatPosition
+
+	getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(uint(leg.TrainCarriageID))
+	if err != nil {
+		return freeSeats, errors.New("Failed to get train carriage id")
+	}
+	trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrainCarriage.TrainID, uint(leg.StationOriginID))
+	if err != nil {
+		return freeSeats, errors.New("Failed to get train station")
+	}
+	trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrainCarriage.TrainID, uint(leg.StationDestinationID))
+	if err != nil {
+		return freeSeats, errors.New("Failed to get train station")
+	}
+	dateDepartureParse, err := helpers.FormatStringToDate(leg.Date)
+	if err != nil {
+		return freeSeats, errors.New("Failed to parsing date")
+	}
+	serviceDate := trainServiceDate(dateDepartureParse, trainStationOrigin)
+
+	var trainSeats []models.TrainSeat
+	if getTrainCarriage.SeatLayoutID > 0 {
+		trainSeats, err = u.trainCarriageRepo.GetTrainSeatsByTrainCarriageID(getTrainCarriage.ID)
+	} else {
+		trainSeats, err = u.trainCarriageRepo.GetTrainSeatsByClass(getTrainCarriage.Class)
+	}
+	if err != nil {
+		return freeSeats, err
+	}
+
+	for _, trainSeat := range trainSeats {
+		if exclude[trainSeat.ID] || !isTrainSeatInCarriage(trainSeat, getTrainCarriage) {
+			continue
+		}
+		trainOrder, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatIDSegment(getTrainCarriage.TrainID, trainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+		if trainOrder.ID > 0 {
+			continue
+		}
+		trainSeatHold, _ := u.trainSeatHoldRepo.GetTrainSeatHoldBySegment(userID, getTrainCarriage.TrainID, trainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+		if trainSeatHold.ID > 0 {
+			continue
+		}
+
+		row, column := trainSeat.Row, trainSeat.Column
+		if getTrainCarriage.SeatLayoutID == 0 {
+			row, column = parseTrainSeatName(trainSeat.Name)
+		}
+		freeSeats = append(freeSeats, trainSeatPosition{ID: trainSeat.ID, Row: row, Column: column})
+	}
+
+	sort.SliceStable(freeSeats, func(i, j int) bool {
+		if freeSeats[i].Row != freeSeats[j].Row {
+			return freeSeats[i].Row < freeSeats[j].Row
+		}
+		return freeSeats[i].Column < freeSeats[j].Column
+	})
+	return freeSeats, nil
+}
+
+// pickContiguousTrainSeats takes count seats in a row of the sorted free seats, choosing the run that
+// spans the fewest rows and then leaves the fewest empty places between seats of the same row.
+func pickContiguousTrainSeats(freeSeats []trainSeatPosition, count int) []trainSeatPosition {
+	if count < 1 || len(freeSeats) < count {
+		return nil
+	}
+
+	best, bestScore := 0, -1
+	for start := 0; start+count <= len(freeSeats); start++ {
+		window := freeSeats[start : start+count]
+		score := (window[count-1].Row - window[0].Row) * 1000
+		for i := 1; i < count; i++ {
+			if window[i].Row == window[i-1].Row {
+				score += window[i].Column - window[i-1].Column - 1
+			}
+		}
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = start, score
+		}
+	}
+	return freeSeats[best : best+count]
+}
+
+// createTicketOrderTickets writes the travelers of the order and one ticket per traveler and leg, and
+// returns the tickets with the total price. Infants get a ticket on the seat of their adult at the
+// infant fare without claiming the seat.
+func (u *ticketOrderUsecase) createTicketOrderTickets(tx *gorm.DB, userID uint, ticketOrder models.TicketOrder, ticketOrderInput dtos.TicketOrderInput, travelerIdentities []travelerIdentity) ([]dtos.TicketTravelerDetailResponse, int, error) {
+	var ticketTravelerDetailResponses []dtos.TicketTravelerDetailResponse
+	sumTrainPrice := 0
+
+	infantOf, err := attachTicketOrderInfants(ticketOrderInput.TravelerDetail, travelerIdentities)
+	if err != nil {
+		return ticketTravelerDetailResponses, 0, err
+	}
+	var seatedIndexes []int
+	for index, identity := range travelerIdentities {
+		if identity.PassengerType != passengerTypeInfant {
+			seatedIndexes = append(seatedIndexes, index)
+		}
+	}
+
+	departureItineraries, err := assignTicketTravelerSeats(ticketOrderInput.TicketTravelerDetailDeparture, ticketOrderInput.TravelerDetail, seatedIndexes)
+	if err != nil {
+		return ticketTravelerDetailResponses, 0, err
+	}
+	err = u.allocateTicketTravelerSeats(userID, departureItineraries)
+	if err != nil {
+		return ticketTravelerDetailResponses, 0, err
+	}
+
+	var returnItineraries []ticketTravelerItinerary
+	if ticketOrder.WithReturn {
+		if len(ticketOrderInput.TicketTravelerDetailReturn) == 0 {
+			return ticketTravelerDetailResponses, 0, errors.New("Ticket traveler detail return is required")
+		}
+		returnItineraries, err = assignTicketTravelerSeats(ticketOrderInput.TicketTravelerDetailReturn, ticketOrderInput.TravelerDetail, seatedIndexes)
+		if err != nil {
+			return ticketTravelerDetailResponses, 0, err
+		}
+		err = u.allocateTicketTravelerSeats(userID, returnItineraries)
+		if err != nil {
+			return ticketTravelerDetailResponses, 0, err
+		}
+	}
+
+	travelerDetailRepo := u.travelerDetailRepo.WithTx(tx)
+	createTravelerDetails := make([]models.TravelerDetail, len(ticketOrderInput.TravelerDetail))
+	for travelerIndex, travelerDetail := range ticketOrderInput.TravelerDetail {
+		identity := travelerIdentities[travelerIndex]
+		createTravelerDetail := models.TravelerDetail{
+			UserID:        userID,
+			TicketOrderID: &ticketOrder.ID,
+			Title:         travelerDetail.Title,
+			FullName:      travelerDetail.FullName,
+			IDType:        identity.IDType,
+			IDCardNumber:  &identity.IDNumber,
+			BirthDate:     identity.BirthDate,
+			PassengerType: identity.PassengerType,
+		}
+		createTravelerDetail, err = travelerDetailRepo.CreateTravelerDetail(createTravelerDetail)
+		if err != nil {
+			return ticketTravelerDetailResponses, 0, err
+		}
+		createTravelerDetails[travelerIndex] = createTravelerDetail
+	}
+
+	for direction, itineraries := range [][]ticketTravelerItinerary{departureItineraries, returnItineraries} {
+		isReturn := direction == 1
+		for _, itinerary := range itineraries {
+			infantIndex, withInfant := infantOf[itinerary.TravelerIndex]
+			var previous, infantPrevious ticketJourneyState
+			for _, leg := range itinerary.Legs {
+				createTicketTravelerDetail, ticketTravelerDetailResponse, err := u.createTicketTravelerDetail(tx, userID, ticketOrder.ID, createTravelerDetails[itinerary.TravelerIndex], 0, leg, &previous, isReturn)
+				if err != nil {
+					return ticketTravelerDetailResponses, 0, err
+				}
+				sumTrainPrice += createTicketTravelerDetail.TrainPrice
+				ticketTravelerDetailResponses = append(ticketTravelerDetailResponses, ticketTravelerDetailResponse)
+
+				if !withInfant {
+					continue
+				}
+				infantTicketTravelerDetail, infantTicketTravelerDetailResponse, err := u.createTicketTravelerDetail(tx, userID, ticketOrder.ID, createTravelerDetails[infantIndex], createTicketTravelerDetail.ID, leg, &infantPrevious, isReturn)
+				if err != nil {
+					return ticketTravelerDetailResponses, 0, err
+				}
+				sumTrainPrice += infantTicketTravelerDetail.TrainPrice
+				ticketTravelerDetailResponses = append(ticketTravelerDetailResponses, infantTicketTravelerDetailResponse)
+			}
+		}
+	}
+
+	return ticketTravelerDetailResponses, sumTrainPrice, nil
+}
+
+// createTicketTravelerDetail books one leg for one traveler inside the order transaction. The seat is
+// checked against other tickets and holds and then claimed hop by hop, except for infants, who ride on
+// the seat of the ticket given by accompaniedByID.
+func (u *ticketOrderUsecase) createTicketTravelerDetail(tx *gorm.DB, userID, ticketOrderID uint, travelerDetail models.TravelerDetail, accompaniedByID uint, leg ticketTravelerDetailLeg, previous *ticketJourneyState, isReturn bool) (models.TicketTravelerDetail, dtos.TicketTravelerDetailResponse, error) {
+	var (
+		createTicketTravelerDetail   models.TicketTravelerDetail
+		ticketTravelerDetailResponse dtos.TicketTravelerDetailResponse
+	)
+	ticketTravelerDetailRepo := u.ticketTravelerDetailRepo.WithTx(tx)
+	trainSeatHoldRepo := u.trainSeatHoldRepo.WithTx(tx)
+	passengerType := travelerDetail.PassengerType
+
+	if leg.TrainCarriageID < 1 || leg.TrainSeatID < 1 || leg.StationOriginID < 1 || leg.StationDestinationID < 1 || leg.Date == "" {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to create ticket order")
+	}
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	if today > leg.Date {
+		if isReturn {
+			return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Return date must not be in the past")
+		}
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Departure date must not be in the past")
+	}
+
+	dateDepartureParse, err := helpers.FormatStringToDate(leg.Date)
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to parsing date")
+	}
+
+	getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(uint(leg.TrainCarriageID))
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get train carriage id")
+	}
+
+	getTrain, err := u.trainRepo.GetTrainByID2(uint(getTrainCarriage.TrainID))
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get train id")
+	}
+
+	getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, uint(leg.StationOriginID), uint(leg.StationDestinationID))
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get train station")
+	}
+
+	if getTrain.Status != "available" {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get train")
+	}
+
+	// Check if the train stops at the origin before the destination
+	if !isForwardRoute(getTrainStation, uint(leg.StationOriginID), uint(leg.StationDestinationID)) {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Train does not travel from station origin to station destination")
+	}
+
+	getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(uint(leg.TrainSeatID))
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get train seat id")
+	}
+	if !isTrainSeatInCarriage(getTrainSeat, getTrainCarriage) {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Train seat is not available in this train carriage")
+	}
+	getStationOrigin, err := u.stationRepo.GetStationByID2(uint(leg.StationOriginID))
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get station origin id")
+	}
+	getStationDestination, err := u.stationRepo.GetStationByID2(uint(leg.StationDestinationID))
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get station destination id")
+	}
+
+	trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationOrigin.ID)
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, err
+	}
+	trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationDestination.ID)
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, err
+	}
+
+	currentLeg, err := newJourneyLeg(getTrain.ID, trainStationOrigin, trainStationDestination)
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to parsing train schedule")
+	}
+	if leg.Transfer {
+		// An overnight connection boards a day later, its times are counted from the date of the previous leg
+		connectingLeg := currentLeg
+		dayShift := int(dateDepartureParse.Sub(previous.date).Hours()/24) * minutesPerDay
+		connectingLeg.Origin.Minute += dayShift
+		connectingLeg.Destination.Minute += dayShift
+		err = validateJourneyLegs([]journeyLeg{previous.leg, connectingLeg}, defaultMinConnectionTime)
+		if err != nil {
+			return createTicketTravelerDetail, ticketTravelerDetailResponse, err
+		}
+	}
+	previous.leg = currentLeg
+	previous.date = dateDepartureParse
+
+	// Check if the train runs on the date of departure
+	serviceDate := trainServiceDate(dateDepartureParse, trainStationOrigin)
+	if !isTrainRunning(u.trainScheduleRepo, getTrain.ID, serviceDate) {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Train does not run on this date")
+	}
+
+	// Price the ticket with the same fare engine the search quotes from
+	trainPrice := quoteTrainFare(u.trainFareRepo, getTrainCarriage, trainStationOrigin, trainStationDestination, serviceDate, now, passengerType).Total
+
+	if passengerType != passengerTypeInfant {
+		// Lock the seat so a concurrent seat hold waits until this ticket is checked and claimed
+		if _, err := u.trainSeatRepo.WithTx(tx).GetTrainSeatByIDForUpdate(getTrainSeat.ID); err != nil {
+			return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Failed to get train seat id")
+		}
+
+		// Check if the seat is already taken on an overlapping segment
+		trainOrder, _ := ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatIDSegment(getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+		if trainOrder.ID > 0 {
+			return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Train seat is not available")
+		}
+
+		// Check if the seat is held by another user during checkout
+		trainSeatHold, _ := trainSeatHoldRepo.GetTrainSeatHoldBySegment(userID, getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+		if trainSeatHold.ID > 0 {
+			return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Train seat is held by another user")
+		}
+	}
+
+	createTicketTravelerDetail = models.TicketTravelerDetail{
+		TicketOrderID:        ticketOrderID,
+		TravelerDetailID:     travelerDetail.ID,
+		TrainID:              uint(getTrain.ID),
+		TrainPrice:           trainPrice,
+		TrainCarriageID:      uint(getTrainCarriage.ID),
+		TrainSeatID:          uint(getTrainSeat.ID),
+		StationOriginID:      uint(getStationOrigin.ID),
+		DepartureTime:        trainStationOrigin.ArriveTime,
+		StationDestinationID: uint(getStationDestination.ID),
+		ArrivalTime:          trainStationDestination.ArriveTime,
+		OriginSequence:       trainStationOrigin.Sequence,
+		DestinationSequence:  trainStationDestination.Sequence,
+		ServiceDate:          &serviceDate,
+		DateOfDeparture:      dateDepartureParse,
+		BoardingTicketCode:   "boarding-ticket-" + uuid.New().String(),
+		AccompaniedByID:      accompaniedByID,
+	}
+	createTicketTravelerDetail, err = ticketTravelerDetailRepo.CreateTicketTravelerDetail(createTicketTravelerDetail)
+	if err != nil {
+		return createTicketTravelerDetail, ticketTravelerDetailResponse, err
+	}
+
+	// Claim the seat hops, a concurrent order for the same seat fails on the unique index.
+	// Infants ride on the lap of an adult and claim no seat of their own.
+	if passengerType != passengerTypeInfant {
+		err = ticketTravelerDetailRepo.CreateTrainSeatBookings(newTrainSeatBookings(createTicketTravelerDetail))
+		if err != nil {
+			return createTicketTravelerDetail, ticketTravelerDetailResponse, errors.New("Train seat is not available")
+		}
+
+		// The seat is sold now, so the hold of the user is no longer needed
+		_ = trainSeatHoldRepo.DeleteTrainSeatHoldsByUserIDAndTrainSeatID(userID, getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate))
+	}
+
+	ticketTravelerDetailResponse = dtos.TicketTravelerDetailResponse{
+		TicketTravelerDetailID: int(createTicketTravelerDetail.ID),
+		TravelerDetail: dtos.TravelerDetailResponse{
+			ID:            int(travelerDetail.ID),
+			Title:         travelerDetail.Title,
+			FullName:      travelerDetail.FullName,
+			IDCardNumber:  *travelerDetail.IDCardNumber,
+			PassengerType: travelerDetail.PassengerType,
+		},
+		Train: dtos.TrainResponsesSimply{
+			TrainID:         getTrain.ID,
+			CodeTrain:       getTrain.CodeTrain,
+			Name:            getTrain.Name,
+			Class:           getTrainCarriage.Class,
+			TrainPrice:      trainPrice,
+			TrainCarriageID: getTrainCarriage.ID,
+			TrainCarriage:   getTrainCarriage.Name,
+			TrainSeatID:     getTrainSeat.ID,
+			TrainSeat:       getTrainSeat.Name,
+		},
+		StationOrigin: dtos.StationResponseSimply{
+			StationID:  getStationOrigin.ID,
+			Origin:     getStationOrigin.Origin,
+			Name:       getStationOrigin.Name,
+			Initial:    getStationOrigin.Initial,
+			ArriveTime: createTicketTravelerDetail.DepartureTime,
+		},
+		StationDestination: dtos.StationResponseSimply{
+			StationID:  getStationDestination.ID,
+			Origin:     getStationDestination.Origin,
+			Name:       getStationDestination.Name,
+			Initial:    getStationDestination.Initial,
+			ArriveTime: createTicketTravelerDetail.ArrivalTime,
+		},
+		Date:               createTicketTravelerDetail.DateOfDeparture,
+		BoardingTicketCode: createTicketTravelerDetail.BoardingTicketCode,
+		AccompaniedByID:    int(createTicketTravelerDetail.AccompaniedByID),
+	}
+	return createTicketTravelerDetail, ticketTravelerDetailResponse, nil
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"reflect"
+	"testing"
+)
+
+func TestPickContiguousTrainSeats(t *testing.T) {
+	seat := func(id uint, row, column int) trainSeatPosition {
+		return trainSeatPosition{ID: id, Row: row, Column: column}
+	}
+
+	tests := []struct {
+		name      string
+		freeSeats []trainSeatPosition
+		count     int
+		want      []uint
+	}{
+		{
+			name:      "not enough free seats",
+			freeSeats: []trainSeatPosition{seat(1, 1, 1)},
+			count:     2,
+			want:      nil,
+		},
+		{
+			name:      "no seats asked",
+			freeSeats: []trainSeatPosition{seat(1, 1, 1)},
+			count:     0,
+			want:      nil,
+		},
+		{
+			name:      "side by side before a gap",
+			freeSeats: []trainSeatPosition{seat(1, 1, 1), seat(3, 1, 3), seat(4, 1, 4), seat(5, 2, 1), seat(6, 2, 2)},
+			count:     2,
+			want:      []uint{3, 4},
+		},
+		{
+			name:      "one row before spanning two",
+			freeSeats: []trainSeatPosition{seat(1, 1, 1), seat(2, 1, 2), seat(5, 2, 1), seat(6, 2, 2), seat(7, 2, 3)},
+			count:     3,
+			want:      []uint{5, 6, 7},
+		},
+		{
+			name:      "fewest empty places within the row",
+			freeSeats: []trainSeatPosition{seat(1, 1, 1), seat(4, 1, 4), seat(5, 2, 1), seat(7, 2, 3)},
+			count:     2,
+			want:      []uint{5, 7},
+		},
+		{
+			name:      "spans rows when no row fits",
+			freeSeats: []trainSeatPosition{seat(4, 1, 4), seat(5, 2, 1)},
+			count:     2,
+			want:      []uint{4, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []uint
+			for _, trainSeat := range pickContiguousTrainSeats(tt.freeSeats, tt.count) {
+				got = append(got, trainSeat.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pickContiguousTrainSeats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachTicketOrderInfants(t *testing.T) {
+	accompaniedBy := func(index int) *int { return &index }
+
+	tests := []struct {
+		name           string
+		passengerTypes []string
+		accompaniedBy  map[int]*int
+		want           map[int]int
+		wantErr        bool
+	}{
+		{
+			name:           "infant rides with the first adult",
+			passengerTypes: []string{passengerTypeAdult, passengerTypeInfant},
+			want:           map[int]int{0: 1},
+		},
+		{
+			name:           "senior can hold an infant",
+			passengerTypes: []string{passengerTypeChild, passengerTypeSenior, passengerTypeInfant},
+			want:           map[int]int{1: 2},
+		},
+		{
+			name:           "infant naming its adult",
+			passengerTypes: []string{passengerTypeAdult, passengerTypeAdult, passengerTypeInfant},
+			accompaniedBy:  map[int]*int{2: accompaniedBy(1)},
+			want:           map[int]int{1: 2},
+		},
+		{
+			name:           "named infants are placed before the others",
+			passengerTypes: []string{passengerTypeAdult, passengerTypeInfant, passengerTypeAdult, passengerTypeInfant},
+			accompaniedBy:  map[int]*int{3: accompaniedBy(0)},
+			want:           map[int]int{0: 3, 2: 1},
+		},
+		{
+			name:           "infant naming a child",
+			passengerTypes: []string{passengerTypeChild, passengerTypeAdult, passengerTypeInfant},
+			accompaniedBy:  map[int]*int{2: accompaniedBy(0)},
+			wantErr:        true,
+		},
+		{
+			name:           "infant naming a traveler outside the order",
+			passengerTypes: []string{passengerTypeAdult, passengerTypeInfant},
+			accompaniedBy:  map[int]*int{1: accompaniedBy(5)},
+			wantErr:        true,
+		},
+		{
+			name:           "two infants naming one adult",
+			passengerTypes: []string{passengerTypeAdult, passengerTypeInfant, passengerTypeInfant},
+			accompaniedBy:  map[int]*int{1: accompaniedBy(0), 2: accompaniedBy(0)},
+			wantErr:        true,
+		},
+		{
+			name:           "more infants than adults",
+			passengerTypes: []string{passengerTypeAdult, passengerTypeInfant, passengerTypeInfant},
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			travelerDetails := make([]dtos.TravelerDetailInput, len(tt.passengerTypes))
+			travelerIdentities := make([]travelerIdentity, len(tt.passengerTypes))
+			for index, passengerType := range tt.passengerTypes {
+				travelerDetails[index] = dtos.TravelerDetailInput{FullName: passengerType, AccompaniedBy: tt.accompaniedBy[index]}
+				travelerIdentities[index] = travelerIdentity{PassengerType: passengerType}
+			}
+
+			got, err := attachTicketOrderInfants(travelerDetails, travelerIdentities)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("attachTicketOrderInfants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("attachTicketOrderInfants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
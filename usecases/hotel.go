@@ -17,25 +17,34 @@ type HotelUsecase interface {
 	UpdateHotel(id uint, hotelInput dtos.HotelInput) (dtos.HotelResponse, error)
 	DeleteHotel(id uint) error
 
-	SearchHotelAvailable(userId, page, limit, minimumPrice, maximumPrice, ratingClass int, address, name, sortByPrice string) ([]dtos.HotelResponse, int, error)
+	UpdateHotelLocation(userId, id uint, locationInput dtos.LocationInput) (dtos.HotelByIDResponse, error)
+
+	SearchHotelAvailable(userId, page, limit, minimumPrice, maximumPrice, ratingClass, guest, stationId, regionId int, latitude, longitude *float64, radius float64, address, name, sortByPrice, checkIn, checkOut string, sortByDistance bool) ([]dtos.HotelResponse, int, error)
+	GetHotelSearchFacets(stationId, regionId int, latitude, longitude *float64, radius float64, address, name string) (dtos.HotelSearchFacetResponse, error)
+	GetHotelsNearTicketOrder(userId, ticketOrderId uint, page, limit int, radius float64) ([]dtos.HotelResponse, int, error)
 }
 
 type hotelUsecase struct {
-	hotelRepo               repositories.HotelRepository
-	hotelRoomRepo           repositories.HotelRoomRepository
-	hotelRoomImageRepo      repositories.HotelRoomImageRepository
-	hotelRoomFacilitiesRepo repositories.HotelRoomFacilitiesRepository
-	hotelImageRepo          repositories.HotelImageRepository
-	hotelFacilitiesRepo     repositories.HotelFacilitiesRepository
-	hotelPoliciesRepo       repositories.HotelPoliciesRepository
-	historySearchRepo       repositories.HistorySearchRepository
-	hotelRatingRepo         repositories.HotelRatingsRepository
-	userRepo                repositories.UserRepository
-	historySeenHotelUsecase HistorySeenHotelUsecase
+	hotelRepo                repositories.HotelRepository
+	hotelRoomRepo            repositories.HotelRoomRepository
+	hotelRoomImageRepo       repositories.HotelRoomImageRepository
+	hotelRoomFacilitiesRepo  repositories.HotelRoomFacilitiesRepository
+	hotelImageRepo           repositories.HotelImageRepository
+	hotelFacilitiesRepo      repositories.HotelFacilitiesRepository
+	hotelPoliciesRepo        repositories.HotelPoliciesRepository
+	historySearchRepo        repositories.HistorySearchRepository
+	hotelRatingRepo          repositories.HotelRatingsRepository
+	userRepo                 repositories.UserRepository
+	hotelOrderRepo           repositories.HotelOrderRepository
+	stationRepo              repositories.StationRepository
+	ticketOrderRepo          repositories.TicketOrderRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	regionRepo               repositories.RegionRepository
+	historySeenHotelUsecase  HistorySeenHotelUsecase
 }
 
-func NewHotelUsecase(hotelRepo repositories.HotelRepository, hotelRoomRepo repositories.HotelRoomRepository, hotelRoomImageRepo repositories.HotelRoomImageRepository, hotelRoomFacilitiesRepo repositories.HotelRoomFacilitiesRepository, hotelImageRepo repositories.HotelImageRepository, hotelFacilitiesRepo repositories.HotelFacilitiesRepository, hotelPoliciesRepo repositories.HotelPoliciesRepository, historySearchRepo repositories.HistorySearchRepository, hotelRatingRepo repositories.HotelRatingsRepository, userRepo repositories.UserRepository, historySeenHotelUsecase HistorySeenHotelUsecase) HotelUsecase {
-	return &hotelUsecase{hotelRepo, hotelRoomRepo, hotelRoomImageRepo, hotelRoomFacilitiesRepo, hotelImageRepo, hotelFacilitiesRepo, hotelPoliciesRepo, historySearchRepo, hotelRatingRepo, userRepo, historySeenHotelUsecase}
+func NewHotelUsecase(hotelRepo repositories.HotelRepository, hotelRoomRepo repositories.HotelRoomRepository, hotelRoomImageRepo repositories.HotelRoomImageRepository, hotelRoomFacilitiesRepo repositories.HotelRoomFacilitiesRepository, hotelImageRepo repositories.HotelImageRepository, hotelFacilitiesRepo repositories.HotelFacilitiesRepository, hotelPoliciesRepo repositories.HotelPoliciesRepository, historySearchRepo repositories.HistorySearchRepository, hotelRatingRepo repositories.HotelRatingsRepository, userRepo repositories.UserRepository, hotelOrderRepo repositories.HotelOrderRepository, stationRepo repositories.StationRepository, ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, regionRepo repositories.RegionRepository, historySeenHotelUsecase HistorySeenHotelUsecase) HotelUsecase {
+	return &hotelUsecase{hotelRepo, hotelRoomRepo, hotelRoomImageRepo, hotelRoomFacilitiesRepo, hotelImageRepo, hotelFacilitiesRepo, hotelPoliciesRepo, historySearchRepo, hotelRatingRepo, userRepo, hotelOrderRepo, stationRepo, ticketOrderRepo, ticketTravelerDetailRepo, regionRepo, historySeenHotelUsecase}
 }
 
 // =============================== ADMIN ================================== \\
@@ -51,7 +60,7 @@ func NewHotelUsecase(hotelRepo repositories.HotelRepository, hotelRoomRepo repos
 // @Param minimum_price query int false "Filter minimum price"
 // @Param maximum_price query int false "Filter maximum price"
 // @Param rating_class query int false "Filter rating class" Enums(1,2,3,4,5)
-// @Param address query string false "Search address hotel"
+// @Param address query string false "Search address hotel, or the name of its province, regency or district"
 // @Param name query string false "Search name hotel"
 // @Param sort_by_price query string false "Filter by price" Enums(asc, desc)
 // @Param recomendation query bool false "Recomendation filter"
@@ -63,14 +72,15 @@ func NewHotelUsecase(hotelRepo repositories.HotelRepository, hotelRoomRepo repos
 // @Failure      500 {object} dtos.InternalServerErrorResponse
 // @Router       /public/hotel [get]
 func (u *hotelUsecase) GetAllHotels(page, limit, minimumPrice, maximumPrice, ratingClass int, address, name, sortByPrice string, recomendation bool) ([]dtos.HotelResponse, int, error) {
-	hotels, count, err := u.hotelRepo.SearchHotelAvailable(page, limit, address, name)
+	hotels, count, err := u.hotelRepo.SearchHotelAvailable(repositories.HotelSearchFilter{Address: address, Name: name}, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	var hotelResponses []dtos.HotelResponse
 
-	for _, hotel := range hotels {
+	for _, hotelSearchRow := range hotels {
+		hotel := hotelSearchRow.Hotel
 		getMinimumPriceRoom, err := u.hotelRoomRepo.GetMinimumPriceHotelRoomByHotelID(hotel.ID)
 		if err != nil {
 			continue
@@ -140,6 +150,10 @@ func (u *hotelUsecase) GetAllHotels(page, limit, minimumPrice, maximumPrice, rat
 			PhoneNumber:     hotel.PhoneNumber,
 			Email:           hotel.Email,
 			Address:         hotel.Address,
+			Latitude:        hotel.Latitude,
+			Longitude:       hotel.Longitude,
+			PostalCode:      hotel.PostalCode,
+			Region:          getRegionResponses(u.regionRepo, hotelRegionPath(hotel)),
 			HotelRoomStart:  getMinimumPriceRoom.DiscountPrice,
 			HotelImage:      hotelImageResponses,
 			HotelFacilities: hotelFacilitiesResponses,
@@ -344,6 +358,10 @@ func (u *hotelUsecase) GetHotelByID(userId, id uint) (dtos.HotelByIDResponse, er
 		PhoneNumber:     hotel.PhoneNumber,
 		Email:           hotel.Email,
 		Address:         hotel.Address,
+		Latitude:        hotel.Latitude,
+		Longitude:       hotel.Longitude,
+		PostalCode:      hotel.PostalCode,
+		Region:          getRegionResponses(u.regionRepo, hotelRegionPath(hotel)),
 		HotelRoom:       hotelRoomResponses,
 		HotelImage:      hotelImageResponses,
 		HotelFacilities: hotelFacilitiesResponses,
@@ -375,6 +393,13 @@ func (u *hotelUsecase) CreateHotel(hotel *dtos.HotelInput) (dtos.HotelResponse,
 	if hotel.Name == "" || hotel.Email == "" || hotel.Address == "" || hotel.PhoneNumber == "" || hotel.Class == 0 || hotel.Description == "" || hotel.HotelFacilities == nil || hotel.HotelImage == nil || hotel.HotelPolicy == nil {
 		return hotelResponse, errors.New("failed to create hotel")
 	}
+	if _, err := validateLocation(hotel.Latitude, hotel.Longitude); err != nil {
+		return hotelResponse, err
+	}
+	hotelRegion, err := resolveRegionPath(u.regionRepo, hotel.RegionID)
+	if err != nil {
+		return hotelResponse, err
+	}
 
 	createHotel := models.Hotel{
 		Name:        hotel.Name,
@@ -383,6 +408,12 @@ func (u *hotelUsecase) CreateHotel(hotel *dtos.HotelInput) (dtos.HotelResponse,
 		PhoneNumber: hotel.PhoneNumber,
 		Email:       hotel.Email,
 		Address:     hotel.Address,
+		Latitude:    hotel.Latitude,
+		Longitude:   hotel.Longitude,
+		ProvinceID:  hotelRegion.ProvinceID,
+		RegencyID:   hotelRegion.RegencyID,
+		DistrictID:  hotelRegion.DistrictID,
+		PostalCode:  hotel.PostalCode,
 	}
 
 	createdHotel, err := u.hotelRepo.CreateHotel(createHotel)
@@ -501,6 +532,10 @@ func (u *hotelUsecase) CreateHotel(hotel *dtos.HotelInput) (dtos.HotelResponse,
 		PhoneNumber:     createdHotel.PhoneNumber,
 		Email:           createdHotel.Email,
 		Address:         createdHotel.Address,
+		Latitude:        createdHotel.Latitude,
+		Longitude:       createdHotel.Longitude,
+		PostalCode:      createdHotel.PostalCode,
+		Region:          getRegionResponses(u.regionRepo, hotelRegionPath(createdHotel)),
 		HotelImage:      hotelImageResponses,
 		HotelFacilities: hotelFacilitiesResponses,
 		HotelPolicy:     hotelPoliciesResponses,
@@ -533,8 +568,16 @@ func (u *hotelUsecase) UpdateHotel(id uint, hotel dtos.HotelInput) (dtos.HotelRe
 	if hotel.Name == "" || hotel.Email == "" || hotel.Address == "" || hotel.PhoneNumber == "" || hotel.Class == 0 || hotel.Description == "" || hotel.HotelFacilities == nil || hotel.HotelImage == nil || hotel.HotelPolicy == nil {
 		return hotelResponse, errors.New("failed to update hotel")
 	}
+	hasLocation, err := validateLocation(hotel.Latitude, hotel.Longitude)
+	if err != nil {
+		return hotelResponse, err
+	}
+	hotelRegion, err := resolveRegionPath(u.regionRepo, hotel.RegionID)
+	if err != nil {
+		return hotelResponse, err
+	}
 
-	hotels, err := u.hotelRepo.GetHotelByID(id)
+	hotels, err = u.hotelRepo.GetHotelByID(id)
 	if err != nil {
 		return hotelResponse, err
 	}
@@ -545,6 +588,16 @@ func (u *hotelUsecase) UpdateHotel(id uint, hotel dtos.HotelInput) (dtos.HotelRe
 	hotels.PhoneNumber = hotel.PhoneNumber
 	hotels.Email = hotel.Email
 	hotels.Address = hotel.Address
+	hotels.PostalCode = hotel.PostalCode
+	if hasLocation {
+		hotels.Latitude = hotel.Latitude
+		hotels.Longitude = hotel.Longitude
+	}
+	if hotel.RegionID != 0 {
+		hotels.ProvinceID = hotelRegion.ProvinceID
+		hotels.RegencyID = hotelRegion.RegencyID
+		hotels.DistrictID = hotelRegion.DistrictID
+	}
 
 	updatedHotel, err := u.hotelRepo.UpdateHotel(hotels)
 	if err != nil {
@@ -666,6 +719,10 @@ func (u *hotelUsecase) UpdateHotel(id uint, hotel dtos.HotelInput) (dtos.HotelRe
 		PhoneNumber:     updatedHotel.PhoneNumber,
 		Email:           updatedHotel.Email,
 		Address:         updatedHotel.Address,
+		Latitude:        updatedHotel.Latitude,
+		Longitude:       updatedHotel.Longitude,
+		PostalCode:      updatedHotel.PostalCode,
+		Region:          getRegionResponses(u.regionRepo, hotelRegionPath(updatedHotel)),
 		HotelImage:      hotelImageResponses,
 		HotelFacilities: hotelFacilitiesResponses,
 		HotelPolicy:     hotelPoliciesResponses,
@@ -675,6 +732,43 @@ func (u *hotelUsecase) UpdateHotel(id uint, hotel dtos.HotelInput) (dtos.HotelRe
 	return hotelResponse, nil
 }
 
+// UpdateHotelLocation godoc
+// @Summary      Update hotel location
+// @Description  Set the coordinates of a hotel, or clear them when neither latitude nor longitude is given
+// @Tags         Admin - Hotel
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID Hotel"
+// @Param        request body dtos.LocationInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.HotelByIDStatusOKResponses
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/hotel/{id}/location [put]
+// @Security BearerAuth
+func (u *hotelUsecase) UpdateHotelLocation(userId, id uint, locationInput dtos.LocationInput) (dtos.HotelByIDResponse, error) {
+	var hotelResponse dtos.HotelByIDResponse
+	if _, err := validateLocation(locationInput.Latitude, locationInput.Longitude); err != nil {
+		return hotelResponse, err
+	}
+
+	hotel, err := u.hotelRepo.GetHotelByID(id)
+	if err != nil {
+		return hotelResponse, err
+	}
+
+	hotel.Latitude = locationInput.Latitude
+	hotel.Longitude = locationInput.Longitude
+	_, err = u.hotelRepo.UpdateHotel(hotel)
+	if err != nil {
+		return hotelResponse, err
+	}
+
+	return u.GetHotelByID(userId, id)
+}
+
 // DeleteHotel godoc
 // @Summary      Delete a hotel
 // @Description  Delete a hotel
@@ -715,9 +809,18 @@ func (u *hotelUsecase) DeleteHotel(id uint) error {
 // @Param minimum_price query int false "Filter minimum price"
 // @Param maximum_price query int false "Filter maximum price"
 // @Param rating_class query int false "Filter rating class" Enums(1,2,3,4,5)
-// @Param address query string false "Search address hotel"
+// @Param address query string false "Search address hotel, or the name of its province, regency or district"
 // @Param name query string false "Search name hotel"
 // @Param sort_by_price query string false "Filter by price" Enums(asc, desc)
+// @Param check_in query string false "Check in date (yyyy-mm-dd), required with check_out"
+// @Param check_out query string false "Check out date (yyyy-mm-dd), required with check_in"
+// @Param guest query int false "Filter rooms that fit this many guests"
+// @Param latitude query number false "Search around this latitude, required with longitude"
+// @Param longitude query number false "Search around this longitude, required with latitude"
+// @Param station_id query int false "Search around this station instead of latitude and longitude"
+// @Param region_id query int false "Filter hotels in this province, regency or district"
+// @Param radius query number false "Only hotels within this many kilometres of the searched point"
+// @Param sort_by_distance query bool false "Nearest hotel first"
 // @Success      200 {object} dtos.GetAllHotelStatusOKResponses
 // @Failure      400 {object} dtos.BadRequestResponse
 // @Failure      401 {object} dtos.UnauthorizedResponse
@@ -726,8 +829,28 @@ func (u *hotelUsecase) DeleteHotel(id uint) error {
 // @Failure      500 {object} dtos.InternalServerErrorResponse
 // @Router       /user/hotel/search [get]
 // @Security BearerAuth
-func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, maximumPrice, ratingClass int, address, name, sortByPrice string) ([]dtos.HotelResponse, int, error) {
-	hotels, count, err := u.hotelRepo.SearchHotelAvailable(page, limit, address, name)
+func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, maximumPrice, ratingClass, guest, stationId, regionId int, latitude, longitude *float64, radius float64, address, name, sortByPrice, checkIn, checkOut string, sortByDistance bool) ([]dtos.HotelResponse, int, error) {
+	isStayDate := checkIn != "" || checkOut != ""
+	if isStayDate {
+		if _, _, err := parseHotelStayDates(checkIn, checkOut); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	hotelSearchFilter, err := u.newHotelSearchFilter(stationId, regionId, latitude, longitude, radius, address, name, sortByDistance)
+	if err != nil {
+		return nil, 0, err
+	}
+	// The room filters run in the search query so the count and the pages agree
+	hotelSearchFilter.Guest = guest
+	hotelSearchFilter.CheckIn = checkIn
+	hotelSearchFilter.CheckOut = checkOut
+	hotelSearchFilter.MinimumPrice = minimumPrice
+	hotelSearchFilter.MaximumPrice = maximumPrice
+	hotelSearchFilter.Class = ratingClass
+	hotelSearchFilter.SortByPrice = sortByPrice
+
+	hotels, count, err := u.hotelRepo.SearchHotelAvailable(hotelSearchFilter, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -744,7 +867,8 @@ func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, m
 
 	var hotelResponses []dtos.HotelResponse
 
-	for _, hotel := range hotels {
+	for _, hotelSearchRow := range hotels {
+		hotel := hotelSearchRow.Hotel
 
 		getHotelRoom, err := u.hotelRoomRepo.GetAllHotelRoomByHotelID(hotel.ID)
 		if err != nil {
@@ -753,11 +877,24 @@ func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, m
 
 		var hotelRoomResponses []dtos.HotelRoomHotelIDResponse
 		for _, hotelRoom := range getHotelRoom {
+			if guest > 0 && hotelRoom.NumberOfGuest < guest {
+				continue // Skip the room if it does not fit the guests
+			}
+
+			availableRoom := 0
+			if isStayDate {
+				availableRoom, err = getHotelRoomAvailability(u.hotelOrderRepo, hotelRoom, checkIn, checkOut)
+				if err != nil || availableRoom < 1 {
+					continue // Skip the room if it is sold out on any night of the stay
+				}
+			}
+
 			hotelRoomResponse := dtos.HotelRoomHotelIDResponse{
 				HotelRoomID:      hotelRoom.ID,
 				Name:             hotelRoom.Name,
 				SizeOfRoom:       hotelRoom.SizeOfRoom,
 				QuantityOfRoom:   hotelRoom.QuantityOfRoom,
+				AvailableRoom:    availableRoom,
 				Description:      hotelRoom.Description,
 				NormalPrice:      hotelRoom.NormalPrice,
 				Discount:         hotelRoom.Discount,
@@ -768,19 +905,6 @@ func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, m
 			hotelRoomResponses = append(hotelRoomResponses, hotelRoomResponse)
 		}
 
-		getMinimumPriceRoom, err := u.hotelRoomRepo.GetMinimumPriceHotelRoomByHotelID(hotel.ID)
-		if err != nil {
-			continue
-		}
-
-		if minimumPrice > 0 && getMinimumPriceRoom.DiscountPrice < minimumPrice {
-			continue // Skip the hotel if its minimum price is below the specified minimumPrice
-		}
-
-		if maximumPrice > 0 && getMinimumPriceRoom.DiscountPrice > maximumPrice {
-			continue // Skip the hotel if its minimum price is above the specified maximumPrice
-		}
-
 		getImage, err := u.hotelImageRepo.GetAllHotelImageByID(hotel.ID)
 		if err != nil {
 			continue
@@ -837,7 +961,12 @@ func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, m
 			PhoneNumber:     hotel.PhoneNumber,
 			Email:           hotel.Email,
 			Address:         hotel.Address,
-			HotelRoomStart:  getMinimumPriceRoom.DiscountPrice,
+			Latitude:        hotel.Latitude,
+			Longitude:       hotel.Longitude,
+			DistanceKm:      hotelSearchRow.DistanceKm,
+			PostalCode:      hotel.PostalCode,
+			Region:          getRegionResponses(u.regionRepo, hotelRegionPath(hotel)),
+			HotelRoomStart:  hotelSearchRow.HotelRoomStart,
 			HotelRoom:       hotelRoomResponses,
 			HotelImage:      hotelImageResponses,
 			HotelFacilities: hotelFacilitiesResponses,
@@ -846,23 +975,7 @@ func (u *hotelUsecase) SearchHotelAvailable(userId, page, limit, minimumPrice, m
 			UpdatedAt:       hotel.UpdatedAt,
 		}
 
-		if ratingClass != 0 && hotelResponse.Class != ratingClass {
-			continue // Skip the hotel if its rating class is below the specified ratingClass
-		}
-
 		hotelResponses = append(hotelResponses, hotelResponse)
-
-		// Sort hotelResponses based on price
-		if strings.ToLower(sortByPrice) == "asc" {
-			sort.SliceStable(hotelResponses, func(i, j int) bool {
-				return hotelResponses[i].HotelRoomStart < hotelResponses[j].HotelRoomStart
-			})
-		} else if strings.ToLower(sortByPrice) == "desc" {
-			sort.SliceStable(hotelResponses, func(i, j int) bool {
-				return hotelResponses[i].HotelRoomStart > hotelResponses[j].HotelRoomStart
-			})
-		}
-
 	}
 
 	return hotelResponses, count, nil
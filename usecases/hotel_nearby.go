@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/models"
+	"errors"
+	"sort"
+)
+
+// hotelNearbyRadiusKm is how far from a station hotels are looked for when no radius is given.
+const hotelNearbyRadiusKm = 10
+
+// ticketOrderDestinationStationID returns the station a ticket order takes its travelers to. The legs of
+// one traveler are followed from the first as long as each departs where the previous one arrived, so the
+// legs of a return trip, which head back to a station already passed, are left out.
+func ticketOrderDestinationStationID(ticketTravelerDetails []models.TicketTravelerDetail) (uint, error) {
+	if len(ticketTravelerDetails) == 0 {
+		return 0, errors.New("Ticket order has no tickets")
+	}
+	sort.Slice(ticketTravelerDetails, func(i, j int) bool {
+		return ticketTravelerDetails[i].ID < ticketTravelerDetails[j].ID
+	})
+
+	travelerDetailID := ticketTravelerDetails[0].TravelerDetailID
+	stationDestinationID := ticketTravelerDetails[0].StationDestinationID
+	visitedStation := map[uint]bool{
+		ticketTravelerDetails[0].StationOriginID: true,
+		stationDestinationID:                     true,
+	}
+	for _, ticketTravelerDetail := range ticketTravelerDetails[1:] {
+		if ticketTravelerDetail.TravelerDetailID != travelerDetailID {
+			continue
+		}
+		if ticketTravelerDetail.StationOriginID != stationDestinationID || visitedStation[ticketTravelerDetail.StationDestinationID] {
+			break
+		}
+		stationDestinationID = ticketTravelerDetail.StationDestinationID
+		visitedStation[stationDestinationID] = true
+	}
+	return stationDestinationID, nil
+}
+
+// GetHotelsNearTicketOrder godoc
+// @Summary      Get hotels near ticket order destination
+// @Description  Hotels around the station a train ticket order arrives at, nearest first
+// @Tags         User - Hotel
+// @Accept       json
+// @Produce      json
+// @Param ticket_order_id query int true "Ticket order ID"
+// @Param radius query number false "Only hotels within this many kilometres of the station, 10 by default"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success      200 {object} dtos.GetAllHotelStatusOKResponses
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/order/ticket/nearby-hotel [get]
+// @Security BearerAuth
+func (u *hotelUsecase) GetHotelsNearTicketOrder(userId, ticketOrderId uint, page, limit int, radius float64) ([]dtos.HotelResponse, int, error) {
+	ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByID(ticketOrderId, userId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ticketTravelerDetails, err := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTicketOrderID(ticketOrder.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	stationDestinationID, err := ticketOrderDestinationStationID(ticketTravelerDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if radius == 0 {
+		radius = hotelNearbyRadiusKm
+	}
+	return u.SearchHotelAvailable(int(userId), page, limit, 0, 0, 0, 0, int(stationDestinationID), 0, nil, nil, radius, "", "", "", "", "", true)
+}
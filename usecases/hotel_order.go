@@ -21,6 +21,7 @@ type HotelOrderUsecase interface {
 	CreateHotelOrder(userID uint, hotelOrderInput dtos.HotelOrderInput) (dtos.HotelOrderResponse, error)
 	CreateHotelOrderMidtrans(userID uint, hotelOrderInput dtos.HotelOrderInput) (dtos.HotelOrderResponse2, error)
 	UpdateHotelOrder(userID, hotelOrderID uint, status string) (dtos.HotelOrderResponse, error)
+	CancelHotelOrderRoom(userID, hotelOrderID, hotelOrderRoomID uint) (dtos.HotelOrderResponse, error)
 	CsvHotelOrder() ([]dtos.CsvHotelOrder, error)
 }
 
@@ -38,10 +39,13 @@ type hotelOrderUsecase struct {
 	userRepo                repositories.UserRepository
 	notificationRepo        repositories.NotificationRepository
 	hotelRatingRepo         repositories.HotelRatingsRepository
+	orderStateMachine       OrderStateMachine
+	savedPassengerRepo      repositories.SavedPassengerRepository
+	hotelRoomRateRepo       repositories.HotelRoomRateRepository
 }
 
-func NewHotelOrderUsecase(hotelOrderRepo repositories.HotelOrderRepository, hotelRepo repositories.HotelRepository, hotelImageRepo repositories.HotelImageRepository, hotelFacilitiesRepo repositories.HotelFacilitiesRepository, hotelPoliciesRepo repositories.HotelPoliciesRepository, hotelRoomRepo repositories.HotelRoomRepository, hotelRoomImageRepo repositories.HotelRoomImageRepository, hotelRoomFacilitiesRepo repositories.HotelRoomFacilitiesRepository, travelerDetailRepo repositories.TravelerDetailRepository, paymentRepo repositories.PaymentRepository, userRepo repositories.UserRepository, notificationRepo repositories.NotificationRepository, hotelRatingRepo repositories.HotelRatingsRepository) HotelOrderUsecase {
-	return &hotelOrderUsecase{hotelOrderRepo, hotelRepo, hotelImageRepo, hotelFacilitiesRepo, hotelPoliciesRepo, hotelRoomRepo, hotelRoomImageRepo, hotelRoomFacilitiesRepo, travelerDetailRepo, paymentRepo, userRepo, notificationRepo, hotelRatingRepo}
+func NewHotelOrderUsecase(hotelOrderRepo repositories.HotelOrderRepository, hotelRepo repositories.HotelRepository, hotelImageRepo repositories.HotelImageRepository, hotelFacilitiesRepo repositories.HotelFacilitiesRepository, hotelPoliciesRepo repositories.HotelPoliciesRepository, hotelRoomRepo repositories.HotelRoomRepository, hotelRoomImageRepo repositories.HotelRoomImageRepository, hotelRoomFacilitiesRepo repositories.HotelRoomFacilitiesRepository, travelerDetailRepo repositories.TravelerDetailRepository, paymentRepo repositories.PaymentRepository, userRepo repositories.UserRepository, notificationRepo repositories.NotificationRepository, hotelRatingRepo repositories.HotelRatingsRepository, orderStateMachine OrderStateMachine, savedPassengerRepo repositories.SavedPassengerRepository, hotelRoomRateRepo repositories.HotelRoomRateRepository) HotelOrderUsecase {
+	return &hotelOrderUsecase{hotelOrderRepo, hotelRepo, hotelImageRepo, hotelFacilitiesRepo, hotelPoliciesRepo, hotelRoomRepo, hotelRoomImageRepo, hotelRoomFacilitiesRepo, travelerDetailRepo, paymentRepo, userRepo, notificationRepo, hotelRatingRepo, orderStateMachine, savedPassengerRepo, hotelRoomRateRepo}
 }
 
 // GetHotelOrders godoc
@@ -657,6 +661,11 @@ func (u *hotelOrderUsecase) GetHotelOrdersDetailByAdmin(hotelOrderId uint) (dtos
 		travelerDetailResponses = append(travelerDetailResponses, travelerDetailResponse)
 	}
 
+	hotelOrderRoomResponses, err := getHotelOrderRoomResponses(u.hotelOrderRepo, hotelOrder.ID)
+	if err != nil {
+		return hotelOrderResponses, err
+	}
+
 	hotelOrderResponses = dtos.HotelOrderResponse{
 		HotelOrderID:     int(hotelOrder.ID),
 		QuantityAdult:    hotelOrder.QuantityAdult,
@@ -666,6 +675,7 @@ func (u *hotelOrderUsecase) GetHotelOrdersDetailByAdmin(hotelOrderId uint) (dtos
 		DateEnd:          helpers.FormatDateToYMD(&hotelOrder.DateEnd),
 		Price:            hotelOrder.Price,
 		TotalAmount:      hotelOrder.TotalAmount,
+		Rooms:            hotelOrderRoomResponses,
 		NameOrder:        hotelOrder.NameOrder,
 		EmailOrder:       hotelOrder.EmailOrder,
 		PhoneNumberOrder: hotelOrder.PhoneNumberOrder,
@@ -757,15 +767,20 @@ func (u *hotelOrderUsecase) GetHotelOrderByID(userID, hotelOrderId uint, isCheck
 
 	InitiateCoreApiClient()
 
-	if hotelOrder.PaymentID == 0 {
+	// Only an unpaid order follows Midtrans, a refunded or finished order must not be reopened
+	if hotelOrder.PaymentID == 0 && hotelOrder.Status == "unpaid" {
 		res, _ := c.CheckTransaction(hotelOrder.HotelOrderCode)
 		if res.TransactionStatus == "settlement" {
-			hotelOrder.Status = "paid"
-			_, _ = u.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+			hotelOrder, err = u.orderStateMachine.TransitionHotelOrder(hotelOrder, "paid", orderActorSystem, 0, "Midtrans settlement")
+			if err != nil {
+				return hotelOrderResponses, err
+			}
 		}
 		if res.TransactionStatus == "expire" {
-			hotelOrder.Status = "canceled"
-			_, _ = u.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+			hotelOrder, err = u.orderStateMachine.TransitionHotelOrder(hotelOrder, "canceled", orderActorSystem, 0, "Midtrans transaction expired")
+			if err != nil {
+				return hotelOrderResponses, err
+			}
 		}
 	}
 
@@ -776,8 +791,11 @@ func (u *hotelOrderUsecase) GetHotelOrderByID(userID, hotelOrderId uint, isCheck
 
 	if hotelOrder.IsCheckIn == true && hotelOrder.IsCheckOut == false && isCheckOut == true {
 		hotelOrder.IsCheckOut = true
-		hotelOrder.Status = "done"
 		_, _ = u.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+		hotelOrder, err = u.orderStateMachine.TransitionHotelOrder(hotelOrder, "done", orderActorSystem, userID, "Checked out")
+		if err != nil {
+			return hotelOrderResponses, err
+		}
 	}
 
 	getHotel, err := u.hotelRepo.GetHotelByID2(hotelOrder.HotelID)
@@ -885,6 +903,11 @@ func (u *hotelOrderUsecase) GetHotelOrderByID(userID, hotelOrderId uint, isCheck
 		}
 	}
 
+	hotelOrderRoomResponses, err := getHotelOrderRoomResponses(u.hotelOrderRepo, hotelOrder.ID)
+	if err != nil {
+		return hotelOrderResponses, err
+	}
+
 	hotelOrderResponses = dtos.HotelOrderResponse{
 		HotelOrderID:     int(hotelOrder.ID),
 		QuantityAdult:    hotelOrder.QuantityAdult,
@@ -894,6 +917,7 @@ func (u *hotelOrderUsecase) GetHotelOrderByID(userID, hotelOrderId uint, isCheck
 		DateEnd:          helpers.FormatDateToYMD(&hotelOrder.DateEnd),
 		Price:            hotelOrder.Price,
 		TotalAmount:      hotelOrder.TotalAmount,
+		Rooms:            hotelOrderRoomResponses,
 		NameOrder:        hotelOrder.NameOrder,
 		EmailOrder:       hotelOrder.EmailOrder,
 		PhoneNumberOrder: hotelOrder.PhoneNumberOrder,
@@ -972,17 +996,15 @@ func (u *hotelOrderUsecase) GetHotelOrderByID(userID, hotelOrderId uint, isCheck
 func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.HotelOrderInput) (dtos.HotelOrderResponse, error) {
 	var hotelOrderResponse dtos.HotelOrderResponse
 	sumHotelPrice := 0
-	if hotelOrderInput.HotelRoomID < 1 || hotelOrderInput.QuantityAdult < 1 || hotelOrderInput.DateStart == "" || hotelOrderInput.DateEnd == "" || hotelOrderInput.PaymentID < 1 || hotelOrderInput.NameOrder == "" || hotelOrderInput.EmailOrder == "" || hotelOrderInput.PhoneNumberOrder == "" || hotelOrderInput.TravelerDetail == nil {
+	if (hotelOrderInput.HotelRoomID < 1 && len(hotelOrderInput.Rooms) == 0) || hotelOrderInput.DateStart == "" || hotelOrderInput.DateEnd == "" || hotelOrderInput.PaymentID < 1 || hotelOrderInput.NameOrder == "" || hotelOrderInput.EmailOrder == "" || hotelOrderInput.PhoneNumberOrder == "" || hotelOrderInput.TravelerDetail == nil {
 		return hotelOrderResponse, errors.New("Failed to create hotel order")
 	}
-	getHotelRooms, err := u.hotelRoomRepo.GetHotelRoomByID(uint(hotelOrderInput.HotelRoomID))
-	if err != nil {
-		return hotelOrderResponse, err
-	}
-	getHotels, err := u.hotelRepo.GetHotelByID(getHotelRooms.HotelID)
+
+	travelerDetails, err := resolveSavedPassengers(u.savedPassengerRepo, userID, hotelOrderInput.TravelerDetail)
 	if err != nil {
 		return hotelOrderResponse, err
 	}
+	hotelOrderInput.TravelerDetail = travelerDetails
 
 	dateNow := "2006-01-02"
 	dateStartParse, err := time.Parse(dateNow, *&hotelOrderInput.DateStart)
@@ -1012,12 +1034,28 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 		return hotelOrderResponse, errors.New("Failed to date start cannot be larger than date end")
 	}
 
+	hotelOrderRoomLines, err := u.prepareHotelOrderRoomLines(hotelOrderInput, dateStartParse, dateEndParse)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+	getHotels, err := u.hotelRepo.GetHotelByID(hotelOrderRoomLines[0].HotelRoom.HotelID)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
+	// The order keeps the first room and every guest of its lines
+	quantityAdult, quantityInfant := 0, 0
+	for _, hotelOrderRoomLine := range hotelOrderRoomLines {
+		quantityAdult += hotelOrderRoomLine.QuantityAdult
+		quantityInfant += hotelOrderRoomLine.QuantityInfant
+	}
+
 	createHotelOrder := models.HotelOrder{
 		UserID:           userID,
 		HotelID:          getHotels.ID,
-		HotelRoomID:      uint(hotelOrderInput.HotelRoomID),
-		QuantityAdult:    hotelOrderInput.QuantityAdult,
-		QuantityInfant:   hotelOrderInput.QuantityInfant,
+		HotelRoomID:      hotelOrderRoomLines[0].HotelRoom.ID,
+		QuantityAdult:    quantityAdult,
+		QuantityInfant:   quantityInfant,
 		NumberOfNight:    days,
 		DateStart:        dateStartParse,
 		DateEnd:          dateEndParse,
@@ -1037,7 +1075,25 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 		return hotelOrderResponse, errors.New("failed to get payment id")
 	}
 
-	createHotelOrder, err = u.hotelOrderRepo.CreateHotelOrder(createHotelOrder)
+	// Everything below is written in one transaction, so a failed order leaves no rows behind
+	tx := u.hotelOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	hotelOrderRepo := u.hotelOrderRepo.WithTx(tx)
+	hotelRoomRepo := u.hotelRoomRepo.WithTx(tx)
+	travelerDetailRepo := u.travelerDetailRepo.WithTx(tx)
+	notificationRepo := u.notificationRepo.WithTx(tx)
+
+	createHotelOrder, err = hotelOrderRepo.CreateHotelOrder(createHotelOrder)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
+	err = createHotelOrderRoomLines(hotelOrderRepo, hotelRoomRepo, createHotelOrder, hotelOrderRoomLines)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
+	err = u.orderStateMachine.WithTx(tx).RecordHotelOrder(createHotelOrder, userID)
 	if err != nil {
 		return hotelOrderResponse, err
 	}
@@ -1050,7 +1106,7 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 			TicketOrderID: 0,
 		}
 
-		_, err = u.notificationRepo.CreateNotification(createNotification)
+		_, err = notificationRepo.CreateNotification(createNotification)
 		if err != nil {
 			return hotelOrderResponse, errors.New("Create Notification")
 		}
@@ -1058,14 +1114,21 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 
 	var travelerDetailResponses []dtos.TravelerDetailResponse
 	for _, travelerDetail := range hotelOrderInput.TravelerDetail {
+		idType := normalizeTravelerIDType(travelerDetail.IDType, travelerDetail.IDCardNumber)
+		idNumber := strings.ToUpper(strings.TrimSpace(travelerDetail.IDCardNumber))
+		err = validateHotelGuestDocument(idType, idNumber)
+		if err != nil {
+			return hotelOrderResponse, errors.New(travelerDetail.FullName + ": " + err.Error())
+		}
 		travelerDetailResponse := models.TravelerDetail{
 			UserID:       userID,
 			HotelOrderID: &createHotelOrder.ID,
 			Title:        travelerDetail.Title,
 			FullName:     travelerDetail.FullName,
-			IDCardNumber: &travelerDetail.IDCardNumber,
+			IDType:       idType,
+			IDCardNumber: &idNumber,
 		}
-		createTravelerDetail, err := u.travelerDetailRepo.CreateTravelerDetail(travelerDetailResponse)
+		createTravelerDetail, err := travelerDetailRepo.CreateTravelerDetail(travelerDetailResponse)
 		if err != nil {
 			return hotelOrderResponse, err
 		}
@@ -1078,7 +1141,7 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 		travelerDetailResponses = append(travelerDetailResponses, travelerDetailResponseses)
 	}
 
-	hotelOrder, err := u.hotelOrderRepo.GetHotelOrderByID(createHotelOrder.ID, userID)
+	hotelOrder, err := hotelOrderRepo.GetHotelOrderByID(createHotelOrder.ID, userID)
 	if err != nil {
 		return hotelOrderResponse, err
 	}
@@ -1120,16 +1183,20 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 		return hotelOrderResponse, err
 	}
 
-	if (hotelOrderInput.QuantityAdult + hotelOrderInput.QuantityInfant) > getHotelRoom.NumberOfGuest {
-		return hotelOrderResponse, errors.New("Quantity is out of range")
+	// The stay costs the sum of the nightly rates of every room line
+	for _, hotelOrderRoomLine := range hotelOrderRoomLines {
+		sumHotelPrice += hotelOrderRoomLine.Price
 	}
 
-	sumHotelPrice = getHotelRoom.DiscountPrice
-
 	createHotelOrder.Price = sumHotelPrice
-	createHotelOrder.TotalAmount = sumHotelPrice * createHotelOrder.NumberOfNight
+	createHotelOrder.TotalAmount = sumHotelPrice
+
+	hotelOrder, err = hotelOrderRepo.UpdateHotelOrder(createHotelOrder)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
 
-	hotelOrder, err = u.hotelOrderRepo.UpdateHotelOrder(createHotelOrder)
+	err = tx.Commit().Error
 	if err != nil {
 		return hotelOrderResponse, err
 	}
@@ -1161,6 +1228,11 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 		hotelRoomFacilitiesResponses = append(hotelRoomFacilitiesResponses, hotelRoomFacilitiesResponse)
 	}
 
+	hotelOrderRoomResponses, err := getHotelOrderRoomResponses(u.hotelOrderRepo, hotelOrder.ID)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
 	hotelOrderResponse = dtos.HotelOrderResponse{
 		HotelOrderID:     int(hotelOrder.ID),
 		QuantityAdult:    hotelOrder.QuantityAdult,
@@ -1170,6 +1242,7 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 		DateEnd:          helpers.FormatDateToYMD(&hotelOrder.DateEnd),
 		Price:            hotelOrder.Price,
 		TotalAmount:      hotelOrder.TotalAmount,
+		Rooms:            hotelOrderRoomResponses,
 		NameOrder:        hotelOrder.NameOrder,
 		EmailOrder:       hotelOrder.EmailOrder,
 		PhoneNumberOrder: hotelOrder.PhoneNumberOrder,
@@ -1253,17 +1326,15 @@ func (u *hotelOrderUsecase) CreateHotelOrder(userID uint, hotelOrderInput dtos.H
 func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInput dtos.HotelOrderInput) (dtos.HotelOrderResponse2, error) {
 	var hotelOrderResponse dtos.HotelOrderResponse2
 	sumHotelPrice := 0
-	if hotelOrderInput.HotelRoomID < 1 || hotelOrderInput.QuantityAdult < 1 || hotelOrderInput.DateStart == "" || hotelOrderInput.DateEnd == "" || hotelOrderInput.NameOrder == "" || hotelOrderInput.EmailOrder == "" || hotelOrderInput.PhoneNumberOrder == "" || hotelOrderInput.TravelerDetail == nil {
+	if (hotelOrderInput.HotelRoomID < 1 && len(hotelOrderInput.Rooms) == 0) || hotelOrderInput.DateStart == "" || hotelOrderInput.DateEnd == "" || hotelOrderInput.NameOrder == "" || hotelOrderInput.EmailOrder == "" || hotelOrderInput.PhoneNumberOrder == "" || hotelOrderInput.TravelerDetail == nil {
 		return hotelOrderResponse, errors.New("Failed to create hotel order")
 	}
-	getHotelRooms, err := u.hotelRoomRepo.GetHotelRoomByID(uint(hotelOrderInput.HotelRoomID))
-	if err != nil {
-		return hotelOrderResponse, err
-	}
-	getHotels, err := u.hotelRepo.GetHotelByID(getHotelRooms.HotelID)
+
+	travelerDetails, err := resolveSavedPassengers(u.savedPassengerRepo, userID, hotelOrderInput.TravelerDetail)
 	if err != nil {
 		return hotelOrderResponse, err
 	}
+	hotelOrderInput.TravelerDetail = travelerDetails
 
 	dateNow := "2006-01-02"
 	dateStartParse, err := time.Parse(dateNow, *&hotelOrderInput.DateStart)
@@ -1293,12 +1364,28 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 		return hotelOrderResponse, errors.New("Failed to date start cannot be larger than date end")
 	}
 
+	hotelOrderRoomLines, err := u.prepareHotelOrderRoomLines(hotelOrderInput, dateStartParse, dateEndParse)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+	getHotels, err := u.hotelRepo.GetHotelByID(hotelOrderRoomLines[0].HotelRoom.HotelID)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
+	// The order keeps the first room and every guest of its lines
+	quantityAdult, quantityInfant := 0, 0
+	for _, hotelOrderRoomLine := range hotelOrderRoomLines {
+		quantityAdult += hotelOrderRoomLine.QuantityAdult
+		quantityInfant += hotelOrderRoomLine.QuantityInfant
+	}
+
 	createHotelOrder := models.HotelOrder{
 		UserID:           userID,
 		HotelID:          getHotels.ID,
-		HotelRoomID:      uint(hotelOrderInput.HotelRoomID),
-		QuantityAdult:    hotelOrderInput.QuantityAdult,
-		QuantityInfant:   hotelOrderInput.QuantityInfant,
+		HotelRoomID:      hotelOrderRoomLines[0].HotelRoom.ID,
+		QuantityAdult:    quantityAdult,
+		QuantityInfant:   quantityInfant,
 		NumberOfNight:    days,
 		DateStart:        dateStartParse,
 		DateEnd:          dateEndParse,
@@ -1313,7 +1400,25 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 		Status:           "unpaid",
 	}
 
-	createHotelOrder, err = u.hotelOrderRepo.CreateHotelOrder(createHotelOrder)
+	// Everything below is written in one transaction, so a failed order leaves no rows behind
+	tx := u.hotelOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	hotelOrderRepo := u.hotelOrderRepo.WithTx(tx)
+	hotelRoomRepo := u.hotelRoomRepo.WithTx(tx)
+	travelerDetailRepo := u.travelerDetailRepo.WithTx(tx)
+	notificationRepo := u.notificationRepo.WithTx(tx)
+
+	createHotelOrder, err = hotelOrderRepo.CreateHotelOrder(createHotelOrder)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
+	err = createHotelOrderRoomLines(hotelOrderRepo, hotelRoomRepo, createHotelOrder, hotelOrderRoomLines)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
+
+	err = u.orderStateMachine.WithTx(tx).RecordHotelOrder(createHotelOrder, userID)
 	if err != nil {
 		return hotelOrderResponse, err
 	}
@@ -1326,7 +1431,7 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 			TicketOrderID: 0,
 		}
 
-		_, err = u.notificationRepo.CreateNotification(createNotification)
+		_, err = notificationRepo.CreateNotification(createNotification)
 		if err != nil {
 			return hotelOrderResponse, errors.New("Create Notification")
 		}
@@ -1334,14 +1439,21 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 
 	var travelerDetailResponses []dtos.TravelerDetailResponse
 	for _, travelerDetail := range hotelOrderInput.TravelerDetail {
+		idType := normalizeTravelerIDType(travelerDetail.IDType, travelerDetail.IDCardNumber)
+		idNumber := strings.ToUpper(strings.TrimSpace(travelerDetail.IDCardNumber))
+		err = validateHotelGuestDocument(idType, idNumber)
+		if err != nil {
+			return hotelOrderResponse, errors.New(travelerDetail.FullName + ": " + err.Error())
+		}
 		travelerDetailResponse := models.TravelerDetail{
 			UserID:       userID,
 			HotelOrderID: &createHotelOrder.ID,
 			Title:        travelerDetail.Title,
 			FullName:     travelerDetail.FullName,
-			IDCardNumber: &travelerDetail.IDCardNumber,
+			IDType:       idType,
+			IDCardNumber: &idNumber,
 		}
-		createTravelerDetail, err := u.travelerDetailRepo.CreateTravelerDetail(travelerDetailResponse)
+		createTravelerDetail, err := travelerDetailRepo.CreateTravelerDetail(travelerDetailResponse)
 		if err != nil {
 			return hotelOrderResponse, err
 		}
@@ -1354,7 +1466,7 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 		travelerDetailResponses = append(travelerDetailResponses, travelerDetailResponseses)
 	}
 
-	hotelOrder, err := u.hotelOrderRepo.GetHotelOrderByID(createHotelOrder.ID, userID)
+	hotelOrder, err := hotelOrderRepo.GetHotelOrderByID(createHotelOrder.ID, userID)
 	if err != nil {
 		return hotelOrderResponse, err
 	}
@@ -1396,16 +1508,20 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 		return hotelOrderResponse, err
 	}
 
-	if (hotelOrderInput.QuantityAdult + hotelOrderInput.QuantityInfant) > getHotelRoom.NumberOfGuest {
-		return hotelOrderResponse, errors.New("Quantity is out of range")
+	// The stay costs the sum of the nightly rates of every room line
+	for _, hotelOrderRoomLine := range hotelOrderRoomLines {
+		sumHotelPrice += hotelOrderRoomLine.Price
 	}
 
-	sumHotelPrice = getHotelRoom.DiscountPrice
-
 	createHotelOrder.Price = sumHotelPrice
-	createHotelOrder.TotalAmount = sumHotelPrice * createHotelOrder.NumberOfNight
+	createHotelOrder.TotalAmount = sumHotelPrice
+
+	hotelOrder, err = hotelOrderRepo.UpdateHotelOrder(createHotelOrder)
+	if err != nil {
+		return hotelOrderResponse, err
+	}
 
-	hotelOrder, err = u.hotelOrderRepo.UpdateHotelOrder(createHotelOrder)
+	err = tx.Commit().Error
 	if err != nil {
 		return hotelOrderResponse, err
 	}
@@ -1478,12 +1594,21 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 
 	res, err := c.CheckTransaction(hotelOrder.HotelOrderCode)
 	if res.TransactionStatus == "settlement" {
-		hotelOrder.Status = "paid"
-		_, _ = u.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+		hotelOrder, err = u.orderStateMachine.TransitionHotelOrder(hotelOrder, "paid", orderActorSystem, 0, "Midtrans settlement")
+		if err != nil {
+			return hotelOrderResponse, err
+		}
 	}
 	if res.TransactionStatus == "expire" {
-		hotelOrder.Status = "canceled"
-		_, _ = u.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+		hotelOrder, err = u.orderStateMachine.TransitionHotelOrder(hotelOrder, "canceled", orderActorSystem, 0, "Midtrans transaction expired")
+		if err != nil {
+			return hotelOrderResponse, err
+		}
+	}
+
+	hotelOrderRoomResponses, err := getHotelOrderRoomResponses(u.hotelOrderRepo, hotelOrder.ID)
+	if err != nil {
+		return hotelOrderResponse, err
 	}
 
 	hotelOrderResponse = dtos.HotelOrderResponse2{
@@ -1496,6 +1621,7 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 		DateEnd:          helpers.FormatDateToYMD(&hotelOrder.DateEnd),
 		Price:            hotelOrder.Price,
 		TotalAmount:      hotelOrder.TotalAmount,
+		Rooms:            hotelOrderRoomResponses,
 		NameOrder:        hotelOrder.NameOrder,
 		EmailOrder:       hotelOrder.EmailOrder,
 		PhoneNumberOrder: hotelOrder.PhoneNumberOrder,
@@ -1560,7 +1686,7 @@ func (u *hotelOrderUsecase) CreateHotelOrderMidtrans(userID uint, hotelOrderInpu
 // @Accept       json
 // @Produce      json
 // @Param hotel_order_id query int true "Hotel Order ID"
-// @Param status query string true "Update Status Order ID" Enums(paid,done,canceled,refund)
+// @Param status query string true "Update Status Order ID, a paid order is refunded through a refund request" Enums(canceled)
 // @Success      200 {object} dtos.HotelOrderStatusOKResponse
 // @Failure      400 {object} dtos.BadRequestResponse
 // @Failure      401 {object} dtos.UnauthorizedResponse
@@ -1576,72 +1702,11 @@ func (u *hotelOrderUsecase) UpdateHotelOrder(userID, hotelOrderID uint, status s
 	if err != nil {
 		return hotelOrderResponses, err
 	}
-	if hotelOrder.Status == status || status == "unpaid" {
-		return hotelOrderResponses, errors.New("Failed to update hotel order status")
-	}
-	hotelOrder.Status = status
-	hotelOrder, err = u.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+	hotelOrder, err = u.orderStateMachine.TransitionHotelOrder(hotelOrder, status, orderActorUser, userID, "")
 	if err != nil {
 		return hotelOrderResponses, err
 	}
 
-	if hotelOrder.ID > 0 && hotelOrder.Status == "paid" {
-		createNotification := models.Notification{
-			UserID:       userID,
-			TemplateID:   3,
-			HotelOrderID: hotelOrder.ID,
-		}
-
-		_, err = u.notificationRepo.CreateNotification(createNotification)
-		if err != nil {
-			return hotelOrderResponses, err
-		}
-	}
-
-	if hotelOrder.ID > 0 && hotelOrder.Status == "canceled" {
-		createNotification := models.Notification{
-			UserID:       userID,
-			TemplateID:   8,
-			HotelOrderID: hotelOrder.ID,
-		}
-
-		_, err = u.notificationRepo.CreateNotification(createNotification)
-		if err != nil {
-			return hotelOrderResponses, err
-		}
-	}
-
-	if hotelOrder.ID > 0 && hotelOrder.Status == "refund" {
-		createNotification := models.Notification{
-			UserID:       userID,
-			TemplateID:   9,
-			HotelOrderID: hotelOrder.ID,
-		}
-
-		_, err = u.notificationRepo.CreateNotification(createNotification)
-		if err != nil {
-			return hotelOrderResponses, err
-		}
-	}
-
-	checkExistHotelRating, err := u.hotelRatingRepo.CheckExistHotelRating(hotelOrder.ID, hotelOrder.UserID)
-	if err == nil {
-		return hotelOrderResponses, err
-	}
-
-	if hotelOrder.ID > 0 && hotelOrder.Status == "done" && checkExistHotelRating == false {
-		createNotification := models.Notification{
-			UserID:       userID,
-			TemplateID:   6,
-			HotelOrderID: hotelOrder.ID,
-		}
-
-		_, err = u.notificationRepo.CreateNotification(createNotification)
-		if err != nil {
-			return hotelOrderResponses, err
-		}
-	}
-
 	getHotel, err := u.hotelRepo.GetHotelByID2(hotelOrder.HotelID)
 	if err != nil {
 		return hotelOrderResponses, err
@@ -1725,6 +1790,11 @@ func (u *hotelOrderUsecase) UpdateHotelOrder(userID, hotelOrderID uint, status s
 		travelerDetailResponses = append(travelerDetailResponses, travelerDetailResponse)
 	}
 
+	hotelOrderRoomResponses, err := getHotelOrderRoomResponses(u.hotelOrderRepo, hotelOrder.ID)
+	if err != nil {
+		return hotelOrderResponses, err
+	}
+
 	hotelOrderResponses = dtos.HotelOrderResponse{
 		HotelOrderID:     int(hotelOrder.ID),
 		QuantityAdult:    hotelOrder.QuantityAdult,
@@ -1734,6 +1804,7 @@ func (u *hotelOrderUsecase) UpdateHotelOrder(userID, hotelOrderID uint, status s
 		DateEnd:          helpers.FormatDateToYMD(&hotelOrder.DateEnd),
 		Price:            hotelOrder.Price,
 		TotalAmount:      hotelOrder.TotalAmount,
+		Rooms:            hotelOrderRoomResponses,
 		NameOrder:        hotelOrder.NameOrder,
 		EmailOrder:       hotelOrder.EmailOrder,
 		PhoneNumberOrder: hotelOrder.PhoneNumberOrder,
@@ -1799,6 +1870,52 @@ func (u *hotelOrderUsecase) UpdateHotelOrder(userID, hotelOrderID uint, status s
 	return hotelOrderResponses, nil
 }
 
+// CancelHotelOrderRoom godoc
+// @Summary      Cancel Room of Order Hotel
+// @Description  Cancel one room line of an unpaid hotel order and take its price off the order. Canceling the last room cancels the order
+// @Tags         User - Hotel
+// @Accept       json
+// @Produce      json
+// @Param hotel_order_id query int true "Hotel Order ID"
+// @Param hotel_order_room_id query int true "Hotel Order Room ID"
+// @Success      200 {object} dtos.HotelOrderStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/hotel/order/room [patch]
+// @Security BearerAuth
+func (u *hotelOrderUsecase) CancelHotelOrderRoom(userID, hotelOrderID, hotelOrderRoomID uint) (dtos.HotelOrderResponse, error) {
+	var hotelOrderResponses dtos.HotelOrderResponse
+
+	tx := u.hotelOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	hotelOrderRepo := u.hotelOrderRepo.WithTx(tx)
+
+	// Lock the order so two lines canceled at once both come off its total
+	hotelOrder, err := hotelOrderRepo.GetHotelOrderByIDForUpdate(hotelOrderID)
+	if err != nil || hotelOrder.UserID != userID {
+		return hotelOrderResponses, errors.New("Failed to get hotel order")
+	}
+	hotelOrderRoom, err := hotelOrderRepo.GetHotelOrderRoomByID(hotelOrderRoomID)
+	if err != nil {
+		return hotelOrderResponses, errors.New("Failed to get hotel order room")
+	}
+
+	_, err = u.orderStateMachine.WithTx(tx).CancelHotelOrderRoom(hotelOrder, hotelOrderRoom, orderActorUser, userID)
+	if err != nil {
+		return hotelOrderResponses, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return hotelOrderResponses, err
+	}
+
+	return u.GetHotelOrderByID(userID, hotelOrderID, false, false)
+}
+
 // CsvHotelOrder godoc
 // @Summary      CSV Hotel Order
 // @Description  CSV
@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// hotelOrderRoomLine is a room line of a hotel order being placed, priced night by night.
+type hotelOrderRoomLine struct {
+	HotelRoom       models.HotelRoom
+	Quantity        int
+	QuantityAdult   int
+	QuantityInfant  int
+	HotelRoomNights []hotelRoomNight
+	Price           int
+}
+
+// getHotelOrderRoomInputs returns the room lines of an order. An order without lines books one room of
+// HotelRoomID for all of its guests, the way orders were placed before lines existed.
+func getHotelOrderRoomInputs(hotelOrderInput dtos.HotelOrderInput) []dtos.HotelOrderRoomInput {
+	if len(hotelOrderInput.Rooms) > 0 {
+		return hotelOrderInput.Rooms
+	}
+	return []dtos.HotelOrderRoomInput{{
+		HotelRoomID:    hotelOrderInput.HotelRoomID,
+		Quantity:       1,
+		QuantityAdult:  hotelOrderInput.QuantityAdult,
+		QuantityInfant: hotelOrderInput.QuantityInfant,
+	}}
+}
+
+// prepareHotelOrderRoomLines checks the room lines of an order, which must all be rooms of one hotel with
+// at least one adult per room and no more guests than the rooms sleep, and prices every line for the stay.
+func (u *hotelOrderUsecase) prepareHotelOrderRoomLines(hotelOrderInput dtos.HotelOrderInput, dateStart, dateEnd time.Time) ([]hotelOrderRoomLine, error) {
+	var hotelOrderRoomLines []hotelOrderRoomLine
+	for _, hotelOrderRoomInput := range getHotelOrderRoomInputs(hotelOrderInput) {
+		if hotelOrderRoomInput.HotelRoomID < 1 || hotelOrderRoomInput.Quantity < 1 || hotelOrderRoomInput.QuantityInfant < 0 {
+			return nil, errors.New("Failed to create hotel order")
+		}
+
+		hotelRoom, err := u.hotelRoomRepo.GetHotelRoomByID(uint(hotelOrderRoomInput.HotelRoomID))
+		if err != nil {
+			return nil, err
+		}
+		if len(hotelOrderRoomLines) > 0 && hotelRoom.HotelID != hotelOrderRoomLines[0].HotelRoom.HotelID {
+			return nil, errors.New("Rooms of a hotel order must belong to one hotel")
+		}
+
+		if hotelOrderRoomInput.QuantityAdult < hotelOrderRoomInput.Quantity {
+			return nil, fmt.Errorf("%s needs at least one adult per room", hotelRoom.Name)
+		}
+		if hotelOrderRoomInput.QuantityAdult+hotelOrderRoomInput.QuantityInfant > hotelRoom.NumberOfGuest*hotelOrderRoomInput.Quantity {
+			return nil, errors.New("Quantity is out of range")
+		}
+
+		hotelRoomNights, err := quoteHotelRoomNights(u.hotelRoomRateRepo, hotelRoom, dateStart, dateEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		hotelOrderRoomLine := hotelOrderRoomLine{
+			HotelRoom:       hotelRoom,
+			Quantity:        hotelOrderRoomInput.Quantity,
+			QuantityAdult:   hotelOrderRoomInput.QuantityAdult,
+			QuantityInfant:  hotelOrderRoomInput.QuantityInfant,
+			HotelRoomNights: hotelRoomNights,
+		}
+		for _, hotelRoomNight := range hotelRoomNights {
+			hotelOrderRoomLine.Price += hotelRoomNight.Price * hotelOrderRoomLine.Quantity
+		}
+		hotelOrderRoomLines = append(hotelOrderRoomLines, hotelOrderRoomLine)
+	}
+	return hotelOrderRoomLines, nil
+}
+
+// createHotelOrderRoomLines saves the room lines of a new order and takes their rooms for every night of
+// the stay. Rooms are locked in ID order, so concurrent orders for the same rooms never wait on each other
+// in a cycle, and each must have enough rooms free for all lines booking it.
+func createHotelOrderRoomLines(hotelOrderRepo repositories.HotelOrderRepository, hotelRoomRepo repositories.HotelRoomRepository, hotelOrder models.HotelOrder, hotelOrderRoomLines []hotelOrderRoomLine) error {
+	quantityByHotelRoom := make(map[uint]int)
+	var hotelRoomIDs []uint
+	for _, hotelOrderRoomLine := range hotelOrderRoomLines {
+		if _, ok := quantityByHotelRoom[hotelOrderRoomLine.HotelRoom.ID]; !ok {
+			hotelRoomIDs = append(hotelRoomIDs, hotelOrderRoomLine.HotelRoom.ID)
+		}
+		quantityByHotelRoom[hotelOrderRoomLine.HotelRoom.ID] += hotelOrderRoomLine.Quantity
+	}
+	sort.Slice(hotelRoomIDs, func(i, j int) bool { return hotelRoomIDs[i] < hotelRoomIDs[j] })
+
+	dateStart := hotelOrder.DateStart.Format("2006-01-02")
+	dateEnd := hotelOrder.DateEnd.Format("2006-01-02")
+	for _, hotelRoomID := range hotelRoomIDs {
+		lockHotelRoom, err := hotelRoomRepo.GetHotelRoomByIDForUpdate(hotelRoomID)
+		if err != nil {
+			return err
+		}
+		availableHotelRoom, err := getHotelRoomAvailability(hotelOrderRepo, lockHotelRoom, dateStart, dateEnd)
+		if err != nil {
+			return err
+		}
+		if availableHotelRoom < quantityByHotelRoom[hotelRoomID] {
+			return fmt.Errorf("%s has only %d room(s) available", lockHotelRoom.Name, availableHotelRoom)
+		}
+	}
+
+	for _, hotelOrderRoomLine := range hotelOrderRoomLines {
+		hotelOrderRoom, err := hotelOrderRepo.CreateHotelOrderRoom(models.HotelOrderRoom{
+			HotelOrderID:   hotelOrder.ID,
+			HotelRoomID:    hotelOrderRoomLine.HotelRoom.ID,
+			Quantity:       hotelOrderRoomLine.Quantity,
+			QuantityAdult:  hotelOrderRoomLine.QuantityAdult,
+			QuantityInfant: hotelOrderRoomLine.QuantityInfant,
+			Price:          hotelOrderRoomLine.Price,
+			Status:         "active",
+		})
+		if err != nil {
+			return err
+		}
+
+		err = hotelOrderRepo.CreateHotelRoomBookings(newHotelRoomBookings(hotelOrderRoom, hotelOrderRoomLine.HotelRoomNights))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getHotelOrderRoomResponses returns the room lines of an order, with the nightly prices of lines still
+// holding their rooms.
+func getHotelOrderRoomResponses(hotelOrderRepo repositories.HotelOrderRepository, hotelOrderID uint) ([]dtos.HotelOrderRoomResponse, error) {
+	hotelOrderRooms, err := hotelOrderRepo.GetHotelOrderRoomsByHotelOrderID(hotelOrderID)
+	if err != nil {
+		return nil, err
+	}
+	hotelRoomBookings, err := hotelOrderRepo.GetHotelRoomBookingsByHotelOrderID(hotelOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hotelOrderRoomResponses []dtos.HotelOrderRoomResponse
+	for _, hotelOrderRoom := range hotelOrderRooms {
+		var hotelOrderRoomBookings []models.HotelRoomBooking
+		for _, hotelRoomBooking := range hotelRoomBookings {
+			if hotelRoomBooking.HotelOrderRoomID == hotelOrderRoom.ID {
+				hotelOrderRoomBookings = append(hotelOrderRoomBookings, hotelRoomBooking)
+			}
+		}
+		hotelOrderRoomResponses = append(hotelOrderRoomResponses, dtos.HotelOrderRoomResponse{
+			HotelOrderRoomID: hotelOrderRoom.ID,
+			HotelRoomID:      hotelOrderRoom.HotelRoomID,
+			Name:             hotelOrderRoom.HotelRoom.Name,
+			Quantity:         hotelOrderRoom.Quantity,
+			QuantityAdult:    hotelOrderRoom.QuantityAdult,
+			QuantityInfant:   hotelOrderRoom.QuantityInfant,
+			Price:            hotelOrderRoom.Price,
+			Status:           hotelOrderRoom.Status,
+			Nights:           newHotelOrderNightResponses(hotelOrderRoomBookings),
+		})
+	}
+	return hotelOrderRoomResponses, nil
+}
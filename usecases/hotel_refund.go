@@ -0,0 +1,313 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+)
+
+type HotelRefundUsecase interface {
+	// admin
+	GetHotelRefundsByAdmin(page, limit int, status string) ([]dtos.HotelRefundResponse, int, error)
+	ApproveHotelRefund(adminID, id uint, hotelRefundProcessInput dtos.HotelRefundProcessInput) (dtos.HotelRefundResponse, error)
+	RejectHotelRefund(adminID, id uint, hotelRefundProcessInput dtos.HotelRefundProcessInput) (dtos.HotelRefundResponse, error)
+
+	// user
+	RequestHotelRefund(userID uint, hotelRefundInput dtos.HotelRefundInput) (dtos.HotelRefundResponse, error)
+	GetHotelRefunds(page, limit int, userID uint, status string) ([]dtos.HotelRefundResponse, int, error)
+}
+
+type hotelRefundUsecase struct {
+	hotelRefundRepo   repositories.HotelRefundRepository
+	hotelOrderRepo    repositories.HotelOrderRepository
+	hotelPoliciesRepo repositories.HotelPoliciesRepository
+	orderStateMachine OrderStateMachine
+}
+
+func NewHotelRefundUsecase(hotelRefundRepo repositories.HotelRefundRepository, hotelOrderRepo repositories.HotelOrderRepository, hotelPoliciesRepo repositories.HotelPoliciesRepository, orderStateMachine OrderStateMachine) HotelRefundUsecase {
+	return &hotelRefundUsecase{hotelRefundRepo, hotelOrderRepo, hotelPoliciesRepo, orderStateMachine}
+}
+
+// =============================== ADMIN ================================== \\
+
+// GetHotelRefundsByAdmin godoc
+// @Summary      Get hotel refunds
+// @Description  Get hotel refund requests
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected)
+// @Success      200 {object} dtos.GetAllHotelRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/hotel/refund [get]
+// @Security BearerAuth
+func (u *hotelRefundUsecase) GetHotelRefundsByAdmin(page, limit int, status string) ([]dtos.HotelRefundResponse, int, error) {
+	return u.GetHotelRefunds(page, limit, 0, status)
+}
+
+// ApproveHotelRefund godoc
+// @Summary      Approve hotel refund
+// @Description  Approve a pending refund request, the order becomes refund and its rooms are released
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID hotel refund"
+// @Param        request body dtos.HotelRefundProcessInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.HotelRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/hotel/refund/{id}/approve [put]
+// @Security BearerAuth
+func (u *hotelRefundUsecase) ApproveHotelRefund(adminID, id uint, hotelRefundProcessInput dtos.HotelRefundProcessInput) (dtos.HotelRefundResponse, error) {
+	var hotelRefundResponse dtos.HotelRefundResponse
+
+	tx := u.hotelOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+
+	hotelRefundRepo := u.hotelRefundRepo.WithTx(tx)
+	hotelOrderRepo := u.hotelOrderRepo.WithTx(tx)
+	orderStateMachine := u.orderStateMachine.WithTx(tx)
+
+	hotelRefund, err := hotelRefundRepo.GetHotelRefundByIDForUpdate(id)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+	if hotelRefund.Status != "pending" {
+		return hotelRefundResponse, errors.New("Hotel refund has already been processed")
+	}
+
+	// The order is locked so a check in or another transition can not slip in before the refund
+	hotelOrder, err := hotelOrderRepo.GetHotelOrderByIDForUpdate(hotelRefund.HotelOrderID)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+	if hotelOrder.Status != "paid" {
+		return hotelRefundResponse, errors.New("Only paid hotel orders can be refunded")
+	}
+	if hotelOrder.IsCheckIn {
+		return hotelRefundResponse, errors.New("Hotel order cannot be refunded after check in")
+	}
+
+	// The state machine releases the rooms and notifies the user
+	_, err = orderStateMachine.TransitionHotelOrder(hotelOrder, "refund", orderActorAdmin, adminID, "Refund approved")
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	processedAt := time.Now()
+	hotelRefund.Status = "approved"
+	hotelRefund.ProcessedBy = adminID
+	hotelRefund.ProcessedAt = &processedAt
+	hotelRefund.Note = hotelRefundProcessInput.Note
+	hotelRefund, err = hotelRefundRepo.UpdateHotelRefund(hotelRefund)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	return newHotelRefundResponse(hotelRefund), nil
+}
+
+// RejectHotelRefund godoc
+// @Summary      Reject hotel refund
+// @Description  Reject a pending refund request, the order stays paid
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID hotel refund"
+// @Param        request body dtos.HotelRefundProcessInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.HotelRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/hotel/refund/{id}/reject [put]
+// @Security BearerAuth
+func (u *hotelRefundUsecase) RejectHotelRefund(adminID, id uint, hotelRefundProcessInput dtos.HotelRefundProcessInput) (dtos.HotelRefundResponse, error) {
+	var hotelRefundResponse dtos.HotelRefundResponse
+
+	tx := u.hotelOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	hotelRefundRepo := u.hotelRefundRepo.WithTx(tx)
+
+	// Locked like an approval, so the two can not both process the same request
+	hotelRefund, err := hotelRefundRepo.GetHotelRefundByIDForUpdate(id)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+	if hotelRefund.Status != "pending" {
+		return hotelRefundResponse, errors.New("Hotel refund has already been processed")
+	}
+
+	processedAt := time.Now()
+	hotelRefund.Status = "rejected"
+	hotelRefund.ProcessedBy = adminID
+	hotelRefund.ProcessedAt = &processedAt
+	hotelRefund.Note = hotelRefundProcessInput.Note
+	hotelRefund, err = hotelRefundRepo.UpdateHotelRefund(hotelRefund)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	return newHotelRefundResponse(hotelRefund), nil
+}
+
+// =============================== ADMIN END ================================== \\
+
+// =============================== USER ================================== \\
+
+// RequestHotelRefund godoc
+// @Summary      Request hotel refund
+// @Description  Request a refund of a paid hotel order. Only hotels whose policy allows cancellation refund, in full, until the check in time of the first night. The request waits for admin approval
+// @Tags         User - Hotel
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.HotelRefundInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.HotelRefundCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/hotel/order/refund [post]
+// @Security BearerAuth
+func (u *hotelRefundUsecase) RequestHotelRefund(userID uint, hotelRefundInput dtos.HotelRefundInput) (dtos.HotelRefundResponse, error) {
+	var hotelRefundResponse dtos.HotelRefundResponse
+
+	hotelOrder, err := u.hotelOrderRepo.GetHotelOrderByID(hotelRefundInput.HotelOrderID, userID)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	tx := u.hotelOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	hotelRefundRepo := u.hotelRefundRepo.WithTx(tx)
+
+	// The order is locked so two requests sent at once can not both pass the pending check
+	hotelOrder, err = u.hotelOrderRepo.WithTx(tx).GetHotelOrderByIDForUpdate(hotelOrder.ID)
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+	if hotelOrder.Status != "paid" {
+		return hotelRefundResponse, errors.New("Only paid hotel orders can be refunded")
+	}
+
+	pendingHotelRefund, _ := hotelRefundRepo.GetPendingHotelRefundByHotelOrderID(hotelOrder.ID)
+	if pendingHotelRefund.ID > 0 {
+		return hotelRefundResponse, errors.New("Refund has already been requested")
+	}
+
+	hotelPolicies, err := u.hotelPoliciesRepo.GetHotelPoliciesByIDHotel(hotelOrder.HotelID)
+	if err != nil {
+		return hotelRefundResponse, errors.New("Hotel order is not refundable")
+	}
+
+	refundAmount := quoteHotelRefund(hotelOrder, hotelPolicies, time.Now())
+	if refundAmount <= 0 {
+		return hotelRefundResponse, errors.New("Hotel order is not refundable")
+	}
+
+	hotelRefund, err := hotelRefundRepo.CreateHotelRefund(models.HotelRefund{
+		HotelOrderID: hotelOrder.ID,
+		UserID:       hotelOrder.UserID,
+		Amount:       refundAmount,
+		Reason:       hotelRefundInput.Reason,
+		Status:       "pending",
+	})
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return hotelRefundResponse, err
+	}
+
+	return newHotelRefundResponse(hotelRefund), nil
+}
+
+// GetHotelRefunds godoc
+// @Summary      Get my hotel refunds
+// @Description  Get hotel refund requests of the user
+// @Tags         User - Hotel
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected)
+// @Success      200 {object} dtos.GetAllHotelRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/order/hotel/refund [get]
+// @Security BearerAuth
+func (u *hotelRefundUsecase) GetHotelRefunds(page, limit int, userID uint, status string) ([]dtos.HotelRefundResponse, int, error) {
+	hotelRefunds, count, err := u.hotelRefundRepo.GetHotelRefunds(page, limit, userID, strings.ToLower(status))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var hotelRefundResponses []dtos.HotelRefundResponse
+	for _, hotelRefund := range hotelRefunds {
+		hotelRefundResponses = append(hotelRefundResponses, newHotelRefundResponse(hotelRefund))
+	}
+	return hotelRefundResponses, count, nil
+}
+
+// quoteHotelRefund returns how much of a hotel order is refunded at the given time. A hotel whose policy
+// allows cancellation refunds the whole order until the check in time of the first night, otherwise nothing.
+func quoteHotelRefund(hotelOrder models.HotelOrder, hotelPolicies models.HotelPolicies, at time.Time) int {
+	if !hotelPolicies.IsPolicyCanceled || hotelOrder.IsCheckIn {
+		return 0
+	}
+
+	// A hotel without a check in time is refundable until the first night starts
+	minute, _ := helpers.FormatTimeToMinutes(hotelPolicies.TimeCheckIn)
+	dateStart := hotelOrder.DateStart
+	checkInAt := time.Date(dateStart.Year(), dateStart.Month(), dateStart.Day(), 0, minute, 0, 0, time.Local)
+	if !checkInAt.After(at) {
+		return 0
+	}
+	return hotelOrder.TotalAmount
+}
+
+func newHotelRefundResponse(hotelRefund models.HotelRefund) dtos.HotelRefundResponse {
+	return dtos.HotelRefundResponse{
+		HotelRefundID: hotelRefund.ID,
+		HotelOrderID:  hotelRefund.HotelOrderID,
+		UserID:        hotelRefund.UserID,
+		Amount:        hotelRefund.Amount,
+		Reason:        hotelRefund.Reason,
+		Status:        hotelRefund.Status,
+		ProcessedBy:   hotelRefund.ProcessedBy,
+		ProcessedAt:   hotelRefund.ProcessedAt,
+		Note:          hotelRefund.Note,
+		CreatedAt:     hotelRefund.CreatedAt,
+		UpdatedAt:     hotelRefund.UpdatedAt,
+	}
+}
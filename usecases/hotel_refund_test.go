@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"back-end-golang/models"
+	"testing"
+	"time"
+)
+
+func TestQuoteHotelRefund(t *testing.T) {
+	dateStart := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	checkInAt := dateStart.Add(14 * time.Hour)
+	hotelOrder := models.HotelOrder{DateStart: dateStart, TotalAmount: 900000}
+	cancelable := models.HotelPolicies{IsPolicyCanceled: true, TimeCheckIn: "14:00"}
+
+	tests := []struct {
+		name          string
+		hotelOrder    models.HotelOrder
+		hotelPolicies models.HotelPolicies
+		at            time.Time
+		want          int
+	}{
+		{
+			name:          "before check in",
+			hotelOrder:    hotelOrder,
+			hotelPolicies: cancelable,
+			at:            checkInAt.Add(-time.Minute),
+			want:          900000,
+		},
+		{
+			name:          "at check in time",
+			hotelOrder:    hotelOrder,
+			hotelPolicies: cancelable,
+			at:            checkInAt,
+			want:          0,
+		},
+		{
+			name:          "hotel does not allow cancellation",
+			hotelOrder:    hotelOrder,
+			hotelPolicies: models.HotelPolicies{TimeCheckIn: "14:00"},
+			at:            dateStart.AddDate(0, 0, -7),
+			want:          0,
+		},
+		{
+			name:          "guest already checked in",
+			hotelOrder:    models.HotelOrder{DateStart: dateStart, TotalAmount: 900000, IsCheckIn: true},
+			hotelPolicies: cancelable,
+			at:            dateStart.AddDate(0, 0, -1),
+			want:          0,
+		},
+		{
+			name:          "no check in time refunds until the first night starts",
+			hotelOrder:    hotelOrder,
+			hotelPolicies: models.HotelPolicies{IsPolicyCanceled: true},
+			at:            dateStart.Add(time.Minute),
+			want:          0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteHotelRefund(tt.hotelOrder, tt.hotelPolicies, tt.at); got != tt.want {
+				t.Errorf("quoteHotelRefund() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"time"
+)
+
+// parseHotelStayDates reads the check-in and check-out dates of a stay, which must both be given with
+// check-out at least one night after check-in.
+func parseHotelStayDates(checkIn, checkOut string) (time.Time, time.Time, error) {
+	checkInParse, err := helpers.FormatStringToDate(checkIn)
+	if err != nil {
+		return checkInParse, time.Time{}, errors.New("Failed to parse check in date")
+	}
+	checkOutParse, err := helpers.FormatStringToDate(checkOut)
+	if err != nil {
+		return checkInParse, checkOutParse, errors.New("Failed to parse check out date")
+	}
+	if !checkOutParse.After(checkInParse) {
+		return checkInParse, checkOutParse, errors.New("Check out must be at least one night after check in")
+	}
+	return checkInParse, checkOutParse, nil
+}
+
+// getHotelRoomAvailability returns how many rooms of the hotel room are free on every night from
+// dateStart up to the night before dateEnd.
+func getHotelRoomAvailability(hotelOrderRepo repositories.HotelOrderRepository, hotelRoom models.HotelRoom, dateStart, dateEnd string) (int, error) {
+	peak, err := hotelOrderRepo.GetHotelRoomPeakBooking(hotelRoom.ID, dateStart, dateEnd)
+	if err != nil {
+		return 0, err
+	}
+	if peak >= hotelRoom.QuantityOfRoom {
+		return 0, nil
+	}
+	return hotelRoom.QuantityOfRoom - peak, nil
+}
+
+// newHotelRoomBookings returns the ledger rows of an order room line, its rooms for each priced night of the stay.
+func newHotelRoomBookings(hotelOrderRoom models.HotelOrderRoom, hotelRoomNights []hotelRoomNight) []models.HotelRoomBooking {
+	var hotelRoomBookings []models.HotelRoomBooking
+	for _, hotelRoomNight := range hotelRoomNights {
+		hotelRoomBookings = append(hotelRoomBookings, models.HotelRoomBooking{
+			HotelOrderID:     hotelOrderRoom.HotelOrderID,
+			HotelOrderRoomID: hotelOrderRoom.ID,
+			HotelRoomID:      hotelOrderRoom.HotelRoomID,
+			Date:             hotelRoomNight.Date,
+			Quantity:         hotelOrderRoom.Quantity,
+			Price:            hotelRoomNight.Price,
+		})
+	}
+	return hotelRoomBookings
+}
@@ -0,0 +1,284 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hotelRoomNight is the price of one room of a hotel room for one night, as the rate calendar set it.
+type hotelRoomNight struct {
+	Date  time.Time
+	Price int
+}
+
+type HotelRoomRateUsecase interface {
+	GetHotelRoomRates(hotelRoomID uint) ([]dtos.HotelRoomRateResponse, error)
+	CreateHotelRoomRate(hotelRoomID uint, hotelRoomRateInput dtos.HotelRoomRateInput) (dtos.HotelRoomRateResponse, error)
+	DeleteHotelRoomRate(hotelRoomID, id uint) error
+}
+
+type hotelRoomRateUsecase struct {
+	hotelRoomRateRepo repositories.HotelRoomRateRepository
+	hotelRoomRepo     repositories.HotelRoomRepository
+}
+
+func NewHotelRoomRateUsecase(hotelRoomRateRepo repositories.HotelRoomRateRepository, hotelRoomRepo repositories.HotelRoomRepository) HotelRoomRateUsecase {
+	return &hotelRoomRateUsecase{hotelRoomRateRepo, hotelRoomRepo}
+}
+
+// GetHotelRoomRates godoc
+// @Summary      Get hotel room rates
+// @Description  Get the rate calendar of a hotel room
+// @Tags         Admin - Hotel Room
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID hotel room"
+// @Success      200 {object} dtos.GetAllHotelRoomRateStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/hotel-room/{id}/rate [get]
+// @Security BearerAuth
+func (u *hotelRoomRateUsecase) GetHotelRoomRates(hotelRoomID uint) ([]dtos.HotelRoomRateResponse, error) {
+	hotelRoomRateResponses := make([]dtos.HotelRoomRateResponse, 0)
+
+	hotelRoom, err := u.hotelRoomRepo.GetHotelRoomByID(hotelRoomID)
+	if err != nil {
+		return hotelRoomRateResponses, errors.New("Failed to get hotel room")
+	}
+
+	hotelRoomRates, err := u.hotelRoomRateRepo.GetHotelRoomRatesByHotelRoomID(hotelRoom.ID)
+	if err != nil {
+		return hotelRoomRateResponses, err
+	}
+	for _, hotelRoomRate := range hotelRoomRates {
+		hotelRoomRateResponses = append(hotelRoomRateResponses, newHotelRoomRateResponse(hotelRoomRate))
+	}
+
+	return hotelRoomRateResponses, nil
+}
+
+// CreateHotelRoomRate godoc
+// @Summary      Create hotel room rate
+// @Description  Price the nights of a hotel room between two dates and/or on some days of week (0 Sunday to 6 Saturday), and require a minimum stay. Either date may be left empty for an open range, and a price of 0 keeps the room price
+// @Tags         Admin - Hotel Room
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID hotel room"
+// @Param        request body dtos.HotelRoomRateInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.HotelRoomRateCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/hotel-room/{id}/rate [post]
+// @Security BearerAuth
+func (u *hotelRoomRateUsecase) CreateHotelRoomRate(hotelRoomID uint, hotelRoomRateInput dtos.HotelRoomRateInput) (dtos.HotelRoomRateResponse, error) {
+	var hotelRoomRateResponse dtos.HotelRoomRateResponse
+
+	hotelRoom, err := u.hotelRoomRepo.GetHotelRoomByID(hotelRoomID)
+	if err != nil {
+		return hotelRoomRateResponse, errors.New("Failed to get hotel room")
+	}
+
+	createHotelRoomRate := models.HotelRoomRate{
+		HotelRoomID: hotelRoom.ID,
+		Price:       hotelRoomRateInput.Price,
+		MinimumStay: hotelRoomRateInput.MinimumStay,
+		Note:        hotelRoomRateInput.Note,
+	}
+
+	if hotelRoomRateInput.DateStart != "" {
+		dateStart, err := helpers.FormatStringToDate(hotelRoomRateInput.DateStart)
+		if err != nil {
+			return hotelRoomRateResponse, errors.New("Failed to parse date start")
+		}
+		createHotelRoomRate.DateStart = &dateStart
+	}
+	if hotelRoomRateInput.DateEnd != "" {
+		dateEnd, err := helpers.FormatStringToDate(hotelRoomRateInput.DateEnd)
+		if err != nil {
+			return hotelRoomRateResponse, errors.New("Failed to parse date end")
+		}
+		createHotelRoomRate.DateEnd = &dateEnd
+	}
+	if createHotelRoomRate.DateStart != nil && createHotelRoomRate.DateEnd != nil && createHotelRoomRate.DateEnd.Before(*createHotelRoomRate.DateStart) {
+		return hotelRoomRateResponse, errors.New("Date end must be after date start")
+	}
+
+	createHotelRoomRate.Weekdays, err = formatHotelRoomRateWeekdays(hotelRoomRateInput.Weekdays)
+	if err != nil {
+		return hotelRoomRateResponse, err
+	}
+
+	if hotelRoomRateInput.Price < 0 || hotelRoomRateInput.MinimumStay < 0 {
+		return hotelRoomRateResponse, errors.New("Price and minimum stay cannot be negative")
+	}
+	if hotelRoomRateInput.Price == 0 && hotelRoomRateInput.MinimumStay == 0 {
+		return hotelRoomRateResponse, errors.New("Rate must set a price or a minimum stay")
+	}
+
+	createHotelRoomRate, err = u.hotelRoomRateRepo.CreateHotelRoomRate(createHotelRoomRate)
+	if err != nil {
+		return hotelRoomRateResponse, err
+	}
+
+	return newHotelRoomRateResponse(createHotelRoomRate), nil
+}
+
+// DeleteHotelRoomRate godoc
+// @Summary      Delete hotel room rate
+// @Description  Delete hotel room rate
+// @Tags         Admin - Hotel Room
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID hotel room"
+// @Param rate_id path integer true "ID hotel room rate"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/hotel-room/{id}/rate/{rate_id} [delete]
+// @Security BearerAuth
+func (u *hotelRoomRateUsecase) DeleteHotelRoomRate(hotelRoomID, id uint) error {
+	hotelRoomRate, err := u.hotelRoomRateRepo.GetHotelRoomRateByID(id)
+	if err != nil || hotelRoomRate.HotelRoomID != hotelRoomID {
+		return errors.New("Failed to get hotel room rate")
+	}
+	return u.hotelRoomRateRepo.DeleteHotelRoomRate(hotelRoomRate)
+}
+
+func newHotelRoomRateResponse(hotelRoomRate models.HotelRoomRate) dtos.HotelRoomRateResponse {
+	return dtos.HotelRoomRateResponse{
+		HotelRoomRateID: hotelRoomRate.ID,
+		HotelRoomID:     hotelRoomRate.HotelRoomID,
+		DateStart:       helpers.FormatDateToYMD(hotelRoomRate.DateStart),
+		DateEnd:         helpers.FormatDateToYMD(hotelRoomRate.DateEnd),
+		Weekdays:        parseHotelRoomRateWeekdays(hotelRoomRate.Weekdays),
+		Price:           hotelRoomRate.Price,
+		MinimumStay:     hotelRoomRate.MinimumStay,
+		Note:            hotelRoomRate.Note,
+	}
+}
+
+func newHotelOrderNightResponses(hotelRoomBookings []models.HotelRoomBooking) []dtos.HotelOrderNightResponse {
+	var hotelOrderNightResponses []dtos.HotelOrderNightResponse
+	for _, hotelRoomBooking := range hotelRoomBookings {
+		hotelOrderNightResponses = append(hotelOrderNightResponses, dtos.HotelOrderNightResponse{
+			Date:  helpers.FormatDateToYMD(&hotelRoomBooking.Date),
+			Price: hotelRoomBooking.Price,
+		})
+	}
+	return hotelOrderNightResponses
+}
+
+// formatHotelRoomRateWeekdays stores the days of week of a rate sorted and without duplicates.
+func formatHotelRoomRateWeekdays(weekdays []int) (string, error) {
+	isWeekday := make(map[int]bool)
+	for _, weekday := range weekdays {
+		if weekday < 0 || weekday > 6 {
+			return "", errors.New("Weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		isWeekday[weekday] = true
+	}
+
+	var formatted []string
+	for weekday := 0; weekday <= 6; weekday++ {
+		if isWeekday[weekday] {
+			formatted = append(formatted, strconv.Itoa(weekday))
+		}
+	}
+	return strings.Join(formatted, ","), nil
+}
+
+func parseHotelRoomRateWeekdays(weekdays string) []int {
+	parsed := make([]int, 0)
+	for _, weekday := range strings.Split(weekdays, ",") {
+		day, err := strconv.Atoi(weekday)
+		if err == nil {
+			parsed = append(parsed, day)
+		}
+	}
+	sort.Ints(parsed)
+	return parsed
+}
+
+// isHotelRoomRateNight tells whether the rate covers night, by date range and day of week.
+func isHotelRoomRateNight(hotelRoomRate models.HotelRoomRate, night time.Time) bool {
+	if hotelRoomRate.DateStart != nil && night.Before(*hotelRoomRate.DateStart) {
+		return false
+	}
+	if hotelRoomRate.DateEnd != nil && night.After(*hotelRoomRate.DateEnd) {
+		return false
+	}
+	if hotelRoomRate.Weekdays == "" {
+		return true
+	}
+	for _, weekday := range parseHotelRoomRateWeekdays(hotelRoomRate.Weekdays) {
+		if time.Weekday(weekday) == night.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteHotelRoomNights prices every night of a stay in the hotel room from dateStart up to the night
+// before dateEnd. A night takes the price of the most specific rate covering it, a dated rate before an
+// open one and a weekday rate before an every-day one, the newest on a tie; nights without a priced rate
+// keep the room discount price. The stay must be as long as the longest minimum stay of its nights.
+func quoteHotelRoomNights(hotelRoomRateRepo repositories.HotelRoomRateRepository, hotelRoom models.HotelRoom, dateStart, dateEnd time.Time) ([]hotelRoomNight, error) {
+	hotelRoomRates, err := hotelRoomRateRepo.GetHotelRoomRatesByHotelRoomIDAndDates(hotelRoom.ID, helpers.FormatDateToYMD(&dateStart), helpers.FormatDateToYMD(&dateEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	var hotelRoomNights []hotelRoomNight
+	minimumStay := 0
+	for night := dateStart; night.Before(dateEnd); night = night.AddDate(0, 0, 1) {
+		hotelRoomNight := hotelRoomNight{Date: night, Price: hotelRoom.DiscountPrice}
+		specificity := -1
+		for _, hotelRoomRate := range hotelRoomRates {
+			if !isHotelRoomRateNight(hotelRoomRate, night) {
+				continue
+			}
+			if hotelRoomRate.MinimumStay > minimumStay {
+				minimumStay = hotelRoomRate.MinimumStay
+			}
+			if hotelRoomRate.Price < 1 {
+				continue
+			}
+
+			rateSpecificity := 0
+			if hotelRoomRate.DateStart != nil || hotelRoomRate.DateEnd != nil {
+				rateSpecificity += 2
+			}
+			if hotelRoomRate.Weekdays != "" {
+				rateSpecificity++
+			}
+			// Rates come newest first, so only a more specific rate takes over
+			if rateSpecificity > specificity {
+				specificity = rateSpecificity
+				hotelRoomNight.Price = hotelRoomRate.Price
+			}
+		}
+		hotelRoomNights = append(hotelRoomNights, hotelRoomNight)
+	}
+
+	if len(hotelRoomNights) < minimumStay {
+		return hotelRoomNights, fmt.Errorf("Hotel room requires a minimum stay of %d nights", minimumStay)
+	}
+	return hotelRoomNights, nil
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeHotelRoomRateRepo serves the rates of a room newest first, the other methods are not used.
+type fakeHotelRoomRateRepo struct {
+	repositories.HotelRoomRateRepository
+	hotelRoomRates []models.HotelRoomRate
+}
+
+func (r fakeHotelRoomRateRepo) GetHotelRoomRatesByHotelRoomIDAndDates(hotelRoomID uint, dateStart, dateEnd string) ([]models.HotelRoomRate, error) {
+	return r.hotelRoomRates, nil
+}
+
+func TestQuoteHotelRoomNights(t *testing.T) {
+	date := func(day int) *time.Time {
+		d := time.Date(2026, 10, day, 0, 0, 0, 0, time.Local)
+		return &d
+	}
+	// A stay of three nights, Monday to Wednesday
+	dateStart, dateEnd := *date(19), *date(22)
+	hotelRoom := models.HotelRoom{DiscountPrice: 500000}
+
+	tests := []struct {
+		name           string
+		hotelRoomRates []models.HotelRoomRate
+		want           []int
+		wantErr        bool
+	}{
+		{
+			name: "room price without rates",
+			want: []int{500000, 500000, 500000},
+		},
+		{
+			name:           "weekday rate",
+			hotelRoomRates: []models.HotelRoomRate{{Weekdays: "1", Price: 600000}},
+			want:           []int{600000, 500000, 500000},
+		},
+		{
+			name: "dated rate before an every-day rate",
+			hotelRoomRates: []models.HotelRoomRate{
+				{Price: 550000},
+				{DateStart: date(20), DateEnd: date(20), Price: 800000},
+			},
+			want: []int{550000, 800000, 550000},
+		},
+		{
+			name: "dated rate before a weekday rate",
+			hotelRoomRates: []models.HotelRoomRate{
+				{Weekdays: "1", Price: 900000},
+				{DateStart: date(19), DateEnd: date(21), Price: 700000},
+			},
+			want: []int{700000, 700000, 700000},
+		},
+		{
+			name: "newest rate on a tie",
+			hotelRoomRates: []models.HotelRoomRate{
+				{Price: 610000},
+				{Price: 620000},
+			},
+			want: []int{610000, 610000, 610000},
+		},
+		{
+			name:           "rate without a price only sets the minimum stay",
+			hotelRoomRates: []models.HotelRoomRate{{DateStart: date(21), MinimumStay: 3}},
+			want:           []int{500000, 500000, 500000},
+		},
+		{
+			name:           "stay shorter than the minimum stay",
+			hotelRoomRates: []models.HotelRoomRate{{DateStart: date(21), MinimumStay: 4}},
+			wantErr:        true,
+		},
+		{
+			name:           "minimum stay of a night outside the stay",
+			hotelRoomRates: []models.HotelRoomRate{{DateStart: date(22), MinimumStay: 7}},
+			want:           []int{500000, 500000, 500000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelRoomNights, err := quoteHotelRoomNights(fakeHotelRoomRateRepo{hotelRoomRates: tt.hotelRoomRates}, hotelRoom, dateStart, dateEnd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("quoteHotelRoomNights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []int
+			for i, hotelRoomNight := range hotelRoomNights {
+				if night := dateStart.AddDate(0, 0, i); !hotelRoomNight.Date.Equal(night) {
+					t.Errorf("night %d date = %v, want %v", i, hotelRoomNight.Date, night)
+				}
+				got = append(got, hotelRoomNight.Price)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("quoteHotelRoomNights() prices = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
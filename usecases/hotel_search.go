@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+)
+
+// newHotelSearchFilter checks the filters of a hotel search. A station, when given, is the point searched
+// around in place of latitude and longitude.
+func (u *hotelUsecase) newHotelSearchFilter(stationId, regionId int, latitude, longitude *float64, radius float64, address, name string, sortByDistance bool) (repositories.HotelSearchFilter, error) {
+	var hotelSearchFilter repositories.HotelSearchFilter
+	if stationId > 0 {
+		station, err := u.stationRepo.GetStationByID(uint(stationId))
+		if err != nil {
+			return hotelSearchFilter, err
+		}
+		if station.Latitude == nil || station.Longitude == nil {
+			return hotelSearchFilter, errors.New("Station has no location")
+		}
+		latitude, longitude = station.Latitude, station.Longitude
+	}
+	hasLocation, err := validateLocation(latitude, longitude)
+	if err != nil {
+		return hotelSearchFilter, err
+	}
+	if !hasLocation && (radius != 0 || sortByDistance) {
+		return hotelSearchFilter, errors.New("Radius and sort by distance need a location to search around")
+	}
+	if radius < 0 {
+		return hotelSearchFilter, errors.New("Radius must not be negative")
+	}
+	if regionId < 0 {
+		return hotelSearchFilter, errors.New("Region not found")
+	}
+
+	return repositories.HotelSearchFilter{
+		Address:        address,
+		Name:           name,
+		RegionID:       uint(regionId),
+		Latitude:       latitude,
+		Longitude:      longitude,
+		RadiusKm:       radius,
+		SortByDistance: sortByDistance,
+	}, nil
+}
+
+// GetHotelSearchFacets godoc
+// @Summary      Get hotel search facets
+// @Description  Count the hotels a search finds in each province, regency and district
+// @Tags         User - Hotel
+// @Accept       json
+// @Produce      json
+// @Param address query string false "Search address hotel, or the name of its province, regency or district"
+// @Param name query string false "Search name hotel"
+// @Param region_id query int false "Filter hotels in this province, regency or district"
+// @Param latitude query number false "Search around this latitude, required with longitude"
+// @Param longitude query number false "Search around this longitude, required with latitude"
+// @Param station_id query int false "Search around this station instead of latitude and longitude"
+// @Param radius query number false "Only hotels within this many kilometres of the searched point"
+// @Success      200 {object} dtos.HotelSearchFacetStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/hotel/search/facet [get]
+// @Security BearerAuth
+func (u *hotelUsecase) GetHotelSearchFacets(stationId, regionId int, latitude, longitude *float64, radius float64, address, name string) (dtos.HotelSearchFacetResponse, error) {
+	var hotelSearchFacetResponse dtos.HotelSearchFacetResponse
+	hotelSearchFilter, err := u.newHotelSearchFilter(stationId, regionId, latitude, longitude, radius, address, name, false)
+	if err != nil {
+		return hotelSearchFacetResponse, err
+	}
+
+	facets := make(map[string][]dtos.HotelRegionFacetResponse)
+	for _, level := range regionLevels {
+		hotelRegionCounts, err := u.hotelRepo.CountSearchHotelsByRegion(hotelSearchFilter, level)
+		if err != nil {
+			return hotelSearchFacetResponse, err
+		}
+
+		var regionIDs []uint
+		for _, hotelRegionCount := range hotelRegionCounts {
+			regionIDs = append(regionIDs, hotelRegionCount.RegionID)
+		}
+		regions, err := u.regionRepo.GetRegionsByIDs(regionIDs)
+		if err != nil {
+			return hotelSearchFacetResponse, err
+		}
+		regionByID := make(map[uint]models.Region)
+		for _, region := range regions {
+			regionByID[region.ID] = region
+		}
+
+		facets[level] = make([]dtos.HotelRegionFacetResponse, 0)
+		for _, hotelRegionCount := range hotelRegionCounts {
+			region, ok := regionByID[hotelRegionCount.RegionID]
+			if !ok {
+				continue // Skip hotels placed in a region since deleted
+			}
+			facets[level] = append(facets[level], dtos.HotelRegionFacetResponse{
+				RegionID: region.ID,
+				ParentID: region.ParentID,
+				Name:     region.Name,
+				Count:    hotelRegionCount.Count,
+			})
+		}
+	}
+
+	hotelSearchFacetResponse.Province = facets["province"]
+	hotelSearchFacetResponse.Regency = facets["regency"]
+	hotelSearchFacetResponse.District = facets["district"]
+	return hotelSearchFacetResponse, nil
+}
@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"back-end-golang/helpers"
+	"errors"
+)
+
+// validateLocation checks an optional coordinate pair, which must be given whole and lie on the globe.
+// It reports whether a coordinate was given at all.
+func validateLocation(latitude, longitude *float64) (bool, error) {
+	if latitude == nil && longitude == nil {
+		return false, nil
+	}
+	if latitude == nil || longitude == nil {
+		return false, errors.New("Latitude and longitude must be given together")
+	}
+	if !helpers.ValidateCoordinate(*latitude, *longitude) {
+		return false, errors.New("Latitude or longitude is out of range")
+	}
+	return true, nil
+}
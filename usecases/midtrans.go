@@ -55,7 +55,7 @@ func GenerateSnapReq(input dtos.MidtransInput) *snap.Request {
 		},
 		Expiry: &snap.ExpiryDetails{
 			Unit:     "minutes",
-			Duration: 60,
+			Duration: int64(configs.EnvPaymentWindowMinutes()),
 		},
 		CreditCard: &snap.CreditCardDetails{
 			Secure: true,
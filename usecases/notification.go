@@ -2,7 +2,10 @@ package usecases
 
 import (
 	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
 	"back-end-golang/repositories"
+	"fmt"
 	"strings"
 )
 
@@ -16,10 +19,14 @@ type notificationUsecase struct {
 	userRepo            repositories.UserRepository
 	hotelOrderRepo      repositories.HotelOrderRepository
 	ticketOrderRepo     repositories.TicketOrderRepository
+	trainWaitlistRepo   repositories.TrainWaitlistRepository
+	trainRepo           repositories.TrainRepository
+	trainCarriageRepo   repositories.TrainCarriageRepository
+	trainSeatRepo       repositories.TrainSeatRepository
 }
 
-func NewNotificationUsecase(notificationRepo repositories.NotificationRepository, templateMessageRepo repositories.TemplateMessageRepository, userRepo repositories.UserRepository, hotelOrderRepo repositories.HotelOrderRepository, ticketOrderRepo repositories.TicketOrderRepository) NotificationUsecase {
-	return &notificationUsecase{notificationRepo, templateMessageRepo, userRepo, hotelOrderRepo, ticketOrderRepo}
+func NewNotificationUsecase(notificationRepo repositories.NotificationRepository, templateMessageRepo repositories.TemplateMessageRepository, userRepo repositories.UserRepository, hotelOrderRepo repositories.HotelOrderRepository, ticketOrderRepo repositories.TicketOrderRepository, trainWaitlistRepo repositories.TrainWaitlistRepository, trainRepo repositories.TrainRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainSeatRepo repositories.TrainSeatRepository) NotificationUsecase {
+	return &notificationUsecase{notificationRepo, templateMessageRepo, userRepo, hotelOrderRepo, ticketOrderRepo, trainWaitlistRepo, trainRepo, trainCarriageRepo, trainSeatRepo}
 }
 
 // GetNotificationByUserID godoc
@@ -76,6 +83,14 @@ func (u *notificationUsecase) GetNotificationByUserID(id uint) (dtos.Notificatio
 		newContent := strings.Replace(getTemplate.Content, "[Nama Pengguna]", getUser.FullName, -1)
 		newContent = strings.Replace(newContent, "[Order Code]", orderCode, -1)
 
+		if notification.TrainWaitlistID > 0 {
+			getTrainWaitlist, err := u.trainWaitlistRepo.GetTrainWaitlistByID(notification.TrainWaitlistID)
+			if err != nil {
+				continue
+			}
+			newTitle, newContent = u.replaceTrainWaitlistOffer(newTitle, newContent, getTrainWaitlist)
+		}
+
 		templateContentResponse := dtos.TemplateMessageByUserIDResponse{
 			Title:     newTitle,
 			Content:   newContent,
@@ -93,3 +108,22 @@ func (u *notificationUsecase) GetNotificationByUserID(id uint) (dtos.Notificatio
 
 	return notificationResponse, nil
 }
+
+// replaceTrainWaitlistOffer fills the train, date and seat of a waitlist offer into its notification. A
+// template without the [Train], [Date] and [Seat] placeholders gets them as a closing sentence instead.
+func (u *notificationUsecase) replaceTrainWaitlistOffer(title, content string, trainWaitlist models.TrainWaitlist) (string, string) {
+	getTrain, _ := u.trainRepo.GetTrainByID(trainWaitlist.TrainID)
+	getTrainCarriage, _ := u.trainCarriageRepo.GetTrainCarriageByID(trainWaitlist.TrainCarriageID)
+	getTrainSeat, _ := u.trainSeatRepo.GetTrainSeatByID(trainWaitlist.TrainSeatID)
+
+	train := getTrain.Name + " (" + getTrain.CodeTrain + ")"
+	date := helpers.FormatDateToYMD(&trainWaitlist.DateOfDeparture)
+	seat := getTrainCarriage.Name + " " + getTrainSeat.Name
+
+	if !strings.Contains(content, "[Train]") && !strings.Contains(content, "[Date]") && !strings.Contains(content, "[Seat]") {
+		content += fmt.Sprintf(" Train %s on %s, seat %s.", train, date, seat)
+	}
+
+	replacer := strings.NewReplacer("[Train]", train, "[Date]", date, "[Seat]", seat)
+	return replacer.Replace(title), replacer.Replace(content)
+}
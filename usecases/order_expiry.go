@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"back-end-golang/configs"
+	"back-end-golang/repositories"
+	"log"
+	"net/http"
+	"time"
+)
+
+// OrderExpiryUsecase cancels orders that were not paid within the payment window, which puts their seats
+// and rooms back on sale and sends the cancellation notification through the order state machine. Each
+// run also hands seats freed by lapsed holds to the train waitlist.
+type OrderExpiryUsecase interface {
+	ExpireUnpaidOrders() (int, error)
+	Start(interval time.Duration)
+}
+
+type orderExpiryUsecase struct {
+	ticketOrderRepo   repositories.TicketOrderRepository
+	hotelOrderRepo    repositories.HotelOrderRepository
+	trainSeatHoldRepo repositories.TrainSeatHoldRepository
+	orderStateMachine OrderStateMachine
+	trainWaitlist     TrainWaitlistUsecase
+}
+
+func NewOrderExpiryUsecase(ticketOrderRepo repositories.TicketOrderRepository, hotelOrderRepo repositories.HotelOrderRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, orderStateMachine OrderStateMachine, trainWaitlist TrainWaitlistUsecase) OrderExpiryUsecase {
+	return &orderExpiryUsecase{ticketOrderRepo, hotelOrderRepo, trainSeatHoldRepo, orderStateMachine, trainWaitlist}
+}
+
+// Start runs ExpireUnpaidOrders in the background every interval for as long as the server runs.
+func (u *orderExpiryUsecase) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			expired, err := u.ExpireUnpaidOrders()
+			if err != nil {
+				log.Println("Order expiry:", err)
+			}
+			if expired > 0 {
+				log.Printf("Order expiry: canceled %d unpaid orders", expired)
+			}
+
+			offered, err := u.trainWaitlist.ProcessTrainWaitlists()
+			if err != nil {
+				log.Println("Train waitlist:", err)
+			}
+			if offered > 0 {
+				log.Printf("Train waitlist: offered %d seats", offered)
+			}
+		}
+	}()
+}
+
+// ExpireUnpaidOrders cancels every ticket and hotel order still unpaid after the payment window and
+// returns how many were canceled. An order paid through Midtrans is settled instead when the payment
+// went through, and left for the next run when Midtrans cannot be reached.
+func (u *orderExpiryUsecase) ExpireUnpaidOrders() (int, error) {
+	expired := 0
+	createdBefore := time.Now().Add(-time.Duration(configs.EnvPaymentWindowMinutes()) * time.Minute)
+
+	_ = u.trainSeatHoldRepo.DeleteExpiredTrainSeatHolds()
+
+	InitiateCoreApiClient()
+
+	ticketOrders, err := u.ticketOrderRepo.GetUnpaidTicketOrdersCreatedBefore(createdBefore)
+	if err != nil {
+		return expired, err
+	}
+	for _, ticketOrder := range ticketOrders {
+		status, note, ok := expiredOrderStatus(ticketOrder.PaymentID, ticketOrder.TicketOrderCode)
+		if !ok {
+			continue
+		}
+
+		tx := u.ticketOrderRepo.BeginTransaction()
+		// The order is read again under a lock, so a payment made meanwhile is not overwritten
+		lockedTicketOrder, err := u.ticketOrderRepo.WithTx(tx).GetTicketOrderByIDForUpdate(ticketOrder.ID)
+		if err != nil || lockedTicketOrder.Status != "unpaid" {
+			tx.Rollback()
+			continue
+		}
+		_, err = u.orderStateMachine.WithTx(tx).TransitionTicketOrder(lockedTicketOrder, status, orderActorSystem, 0, note)
+		if err != nil {
+			tx.Rollback()
+			log.Println("Order expiry: ticket order", ticketOrder.ID, err)
+			continue
+		}
+		err = tx.Commit().Error
+		if err != nil {
+			log.Println("Order expiry: ticket order", ticketOrder.ID, err)
+			continue
+		}
+		if status == "canceled" {
+			expired++
+		}
+	}
+
+	hotelOrders, err := u.hotelOrderRepo.GetUnpaidHotelOrdersCreatedBefore(createdBefore)
+	if err != nil {
+		return expired, err
+	}
+	for _, hotelOrder := range hotelOrders {
+		status, note, ok := expiredOrderStatus(hotelOrder.PaymentID, hotelOrder.HotelOrderCode)
+		if !ok {
+			continue
+		}
+
+		tx := u.hotelOrderRepo.BeginTransaction()
+		lockedHotelOrder, err := u.hotelOrderRepo.WithTx(tx).GetHotelOrderByIDForUpdate(hotelOrder.ID)
+		if err != nil || lockedHotelOrder.Status != "unpaid" {
+			tx.Rollback()
+			continue
+		}
+		_, err = u.orderStateMachine.WithTx(tx).TransitionHotelOrder(lockedHotelOrder, status, orderActorSystem, 0, note)
+		if err != nil {
+			tx.Rollback()
+			log.Println("Order expiry: hotel order", hotelOrder.ID, err)
+			continue
+		}
+		err = tx.Commit().Error
+		if err != nil {
+			log.Println("Order expiry: hotel order", hotelOrder.ID, err)
+			continue
+		}
+		if status == "canceled" {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// expiredOrderStatus decides what an order past its payment window becomes. Orders without a payment
+// method were paid through Midtrans, they are asked for first and skipped when the answer is unknown.
+func expiredOrderStatus(paymentID int, orderCode string) (string, string, bool) {
+	if paymentID != 0 {
+		return "canceled", "Payment window expired", true
+	}
+
+	res, err := c.CheckTransaction(orderCode)
+	if err != nil && err.StatusCode != http.StatusNotFound {
+		return "", "", false
+	}
+	if err == nil && res.TransactionStatus == "settlement" {
+		return "paid", "Midtrans settlement", true
+	}
+	return "canceled", "Payment window expired", true
+}
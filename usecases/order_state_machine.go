@@ -0,0 +1,364 @@
+package usecases
+
+import (
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+const (
+	orderTypeTicket = "ticket"
+	orderTypeHotel  = "hotel"
+
+	orderActorUser   = "user"
+	orderActorAdmin  = "admin"
+	orderActorSystem = "system"
+)
+
+// orderTransitions lists, per order type, the statuses an order may move to and who may move it there.
+// The system actor covers the payment gateway and background jobs. Only they and an admin confirm a
+// payment or finish an order, and a paid order is only refunded by an admin approving a refund request.
+var orderTransitions = map[string]map[string]map[string][]string{
+	orderTypeTicket: {
+		"unpaid": {
+			"paid":     {orderActorAdmin, orderActorSystem},
+			"canceled": {orderActorUser, orderActorAdmin, orderActorSystem},
+		},
+		"paid": {
+			"done":   {orderActorAdmin, orderActorSystem},
+			"refund": {orderActorAdmin},
+		},
+	},
+	orderTypeHotel: {
+		"unpaid": {
+			"paid":     {orderActorAdmin, orderActorSystem},
+			"canceled": {orderActorUser, orderActorAdmin, orderActorSystem},
+		},
+		"paid": {
+			"done":   {orderActorAdmin, orderActorSystem},
+			"refund": {orderActorAdmin},
+		},
+	},
+}
+
+// orderNotificationTemplates maps the status an order reaches to the notification template sent to its owner.
+var orderNotificationTemplates = map[string]map[string]uint{
+	orderTypeTicket: {"paid": 4, "canceled": 8, "refund": 9},
+	orderTypeHotel:  {"paid": 3, "canceled": 8, "refund": 9, "done": 6},
+}
+
+// OrderStateMachine is the only place ticket and hotel order statuses change. Every transition is checked
+// against orderTransitions, saved, appended to the status history and followed by its side effects, all in
+// one transaction: the caller's when given through WithTx, its own otherwise.
+type OrderStateMachine interface {
+	WithTx(tx *gorm.DB) OrderStateMachine
+	RecordTicketOrder(ticketOrder models.TicketOrder, changedBy uint) error
+	TransitionTicketOrder(ticketOrder models.TicketOrder, status, actor string, changedBy uint, note string) (models.TicketOrder, error)
+	RecordHotelOrder(hotelOrder models.HotelOrder, changedBy uint) error
+	TransitionHotelOrder(hotelOrder models.HotelOrder, status, actor string, changedBy uint, note string) (models.HotelOrder, error)
+	CancelHotelOrderRoom(hotelOrder models.HotelOrder, hotelOrderRoom models.HotelOrderRoom, actor string, changedBy uint) (models.HotelOrder, error)
+}
+
+type orderStateMachine struct {
+	ticketOrderRepo          repositories.TicketOrderRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	trainSeatHoldRepo        repositories.TrainSeatHoldRepository
+	hotelOrderRepo           repositories.HotelOrderRepository
+	hotelRatingRepo          repositories.HotelRatingsRepository
+	notificationRepo         repositories.NotificationRepository
+	orderStatusHistoryRepo   repositories.OrderStatusHistoryRepository
+	trainWaitlist            TrainWaitlistUsecase
+	tx                       *gorm.DB
+}
+
+func NewOrderStateMachine(ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, hotelOrderRepo repositories.HotelOrderRepository, hotelRatingRepo repositories.HotelRatingsRepository, notificationRepo repositories.NotificationRepository, orderStatusHistoryRepo repositories.OrderStatusHistoryRepository, trainWaitlist TrainWaitlistUsecase) OrderStateMachine {
+	return &orderStateMachine{ticketOrderRepo, ticketTravelerDetailRepo, trainSeatHoldRepo, hotelOrderRepo, hotelRatingRepo, notificationRepo, orderStatusHistoryRepo, trainWaitlist, nil}
+}
+
+func (m *orderStateMachine) WithTx(tx *gorm.DB) OrderStateMachine {
+	return &orderStateMachine{
+		ticketOrderRepo:          m.ticketOrderRepo.WithTx(tx),
+		ticketTravelerDetailRepo: m.ticketTravelerDetailRepo.WithTx(tx),
+		trainSeatHoldRepo:        m.trainSeatHoldRepo.WithTx(tx),
+		hotelOrderRepo:           m.hotelOrderRepo.WithTx(tx),
+		hotelRatingRepo:          m.hotelRatingRepo,
+		notificationRepo:         m.notificationRepo.WithTx(tx),
+		orderStatusHistoryRepo:   m.orderStatusHistoryRepo.WithTx(tx),
+		trainWaitlist:            m.trainWaitlist.WithTx(tx),
+		tx:                       tx,
+	}
+}
+
+// inTransaction runs fn on a state machine bound to a transaction, opening and committing one when the
+// caller did not give its own.
+func (m *orderStateMachine) inTransaction(fn func(machine *orderStateMachine) error) error {
+	if m.tx != nil {
+		return fn(m)
+	}
+
+	tx := m.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+
+	err := fn(m.WithTx(tx).(*orderStateMachine))
+	if err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// RecordTicketOrder starts the history of a newly created ticket order.
+func (m *orderStateMachine) RecordTicketOrder(ticketOrder models.TicketOrder, changedBy uint) error {
+	return m.createOrderStatusHistory(orderTypeTicket, ticketOrder.ID, "", ticketOrder.Status, changedBy, "Order created")
+}
+
+func (m *orderStateMachine) TransitionTicketOrder(ticketOrder models.TicketOrder, status, actor string, changedBy uint, note string) (models.TicketOrder, error) {
+	err := m.inTransaction(func(machine *orderStateMachine) error {
+		var err error
+		ticketOrder, err = machine.transitionTicketOrder(ticketOrder, status, actor, changedBy, note)
+		return err
+	})
+	return ticketOrder, err
+}
+
+func (m *orderStateMachine) transitionTicketOrder(ticketOrder models.TicketOrder, status, actor string, changedBy uint, note string) (models.TicketOrder, error) {
+	fromStatus := ticketOrder.Status
+	err := checkOrderTransition(orderTypeTicket, fromStatus, status, actor)
+	if err != nil {
+		return ticketOrder, err
+	}
+
+	// The order only moves from the status it was read with, so two transitions racing on it can not both win
+	isUpdated, err := m.ticketOrderRepo.UpdateTicketOrderStatus(ticketOrder.ID, fromStatus, status)
+	if err != nil {
+		return ticketOrder, err
+	}
+	if !isUpdated {
+		return ticketOrder, errors.New("Ticket order status has already changed")
+	}
+	ticketOrder.Status = status
+
+	err = m.createOrderStatusHistory(orderTypeTicket, ticketOrder.ID, fromStatus, status, changedBy, note)
+	if err != nil {
+		return ticketOrder, err
+	}
+
+	// Seats of an order that will not travel go back on sale
+	if status == "canceled" || status == "refund" {
+		ticketTravelerDetails, err := m.ticketTravelerDetailRepo.GetTicketTravelerDetailByTicketOrderID(ticketOrder.ID)
+		if err != nil {
+			return ticketOrder, err
+		}
+		releaseTicketOrderSeats(m.ticketTravelerDetailRepo, m.trainSeatHoldRepo, ticketOrder, ticketTravelerDetails)
+
+		// Users waiting for a sold-out class get the released seats first, a failed offer is retried
+		// by the next waitlist run instead of blocking the cancellation
+		_, _ = m.trainWaitlist.OfferReleasedTrainSeats(ticketTravelerDetails)
+	}
+
+	if templateID, ok := orderNotificationTemplates[orderTypeTicket][status]; ok {
+		_, err = m.notificationRepo.CreateNotification(models.Notification{
+			UserID:        ticketOrder.UserID,
+			TemplateID:    templateID,
+			TicketOrderID: ticketOrder.ID,
+		})
+		if err != nil {
+			return ticketOrder, err
+		}
+	}
+
+	return ticketOrder, nil
+}
+
+// RecordHotelOrder starts the history of a newly created hotel order.
+func (m *orderStateMachine) RecordHotelOrder(hotelOrder models.HotelOrder, changedBy uint) error {
+	return m.createOrderStatusHistory(orderTypeHotel, hotelOrder.ID, "", hotelOrder.Status, changedBy, "Order created")
+}
+
+func (m *orderStateMachine) TransitionHotelOrder(hotelOrder models.HotelOrder, status, actor string, changedBy uint, note string) (models.HotelOrder, error) {
+	err := m.inTransaction(func(machine *orderStateMachine) error {
+		var err error
+		hotelOrder, err = machine.transitionHotelOrder(hotelOrder, status, actor, changedBy, note)
+		return err
+	})
+	return hotelOrder, err
+}
+
+func (m *orderStateMachine) transitionHotelOrder(hotelOrder models.HotelOrder, status, actor string, changedBy uint, note string) (models.HotelOrder, error) {
+	fromStatus := hotelOrder.Status
+	err := checkOrderTransition(orderTypeHotel, fromStatus, status, actor)
+	if err != nil {
+		return hotelOrder, err
+	}
+
+	// The order only moves from the status it was read with, so two transitions racing on it can not both win
+	isUpdated, err := m.hotelOrderRepo.UpdateHotelOrderStatus(hotelOrder.ID, fromStatus, status)
+	if err != nil {
+		return hotelOrder, err
+	}
+	if !isUpdated {
+		return hotelOrder, errors.New("Hotel order status has already changed")
+	}
+	hotelOrder.Status = status
+
+	err = m.createOrderStatusHistory(orderTypeHotel, hotelOrder.ID, fromStatus, status, changedBy, note)
+	if err != nil {
+		return hotelOrder, err
+	}
+
+	// Rooms of an order that will not stay go back on sale
+	if status == "canceled" || status == "refund" {
+		err = m.hotelOrderRepo.UpdateActiveHotelOrderRoomsStatus(hotelOrder.ID, status)
+		if err != nil {
+			return hotelOrder, err
+		}
+		err = m.hotelOrderRepo.DeleteHotelRoomBookingsByHotelOrderID(hotelOrder.ID)
+		if err != nil {
+			return hotelOrder, err
+		}
+	}
+
+	if templateID, ok := orderNotificationTemplates[orderTypeHotel][status]; ok {
+		// The rating reminder is only sent while the stay has not been rated yet
+		if status == "done" {
+			isRated, _ := m.hotelRatingRepo.CheckExistHotelRating(hotelOrder.ID, hotelOrder.UserID)
+			if isRated {
+				return hotelOrder, nil
+			}
+		}
+		_, err = m.notificationRepo.CreateNotification(models.Notification{
+			UserID:       hotelOrder.UserID,
+			TemplateID:   templateID,
+			HotelOrderID: hotelOrder.ID,
+		})
+		if err != nil {
+			return hotelOrder, err
+		}
+	}
+
+	return hotelOrder, nil
+}
+
+// CancelHotelOrderRoom cancels one room line of an unpaid order, or refunds it from a paid one when an admin
+// does it, and takes its price off the order. Canceling the last active line moves the whole order the same way.
+func (m *orderStateMachine) CancelHotelOrderRoom(hotelOrder models.HotelOrder, hotelOrderRoom models.HotelOrderRoom, actor string, changedBy uint) (models.HotelOrder, error) {
+	err := m.inTransaction(func(machine *orderStateMachine) error {
+		var err error
+		hotelOrder, err = machine.cancelHotelOrderRoom(hotelOrder, hotelOrderRoom, actor, changedBy)
+		return err
+	})
+	return hotelOrder, err
+}
+
+func (m *orderStateMachine) cancelHotelOrderRoom(hotelOrder models.HotelOrder, hotelOrderRoom models.HotelOrderRoom, actor string, changedBy uint) (models.HotelOrder, error) {
+	// The totals below are saved whole, so they are taken from the order as locked now
+	lockedHotelOrder, err := m.hotelOrderRepo.GetHotelOrderByIDForUpdate(hotelOrder.ID)
+	if err != nil {
+		return hotelOrder, err
+	}
+	if lockedHotelOrder.Status != hotelOrder.Status {
+		return hotelOrder, errors.New("Hotel order status has already changed")
+	}
+	hotelOrder = lockedHotelOrder
+
+	if hotelOrderRoom.HotelOrderID != hotelOrder.ID || hotelOrderRoom.Status != "active" {
+		return hotelOrder, errors.New("Hotel order room is not active")
+	}
+
+	if hotelOrder.IsCheckIn {
+		return hotelOrder, errors.New("Hotel order room cannot be canceled after check in")
+	}
+	status := "canceled"
+	if hotelOrder.Status == "paid" {
+		status = "refund"
+	}
+	err = checkOrderTransition(orderTypeHotel, hotelOrder.Status, status, actor)
+	if err != nil {
+		return hotelOrder, err
+	}
+
+	hotelOrderRooms, err := m.hotelOrderRepo.GetHotelOrderRoomsByHotelOrderID(hotelOrder.ID)
+	if err != nil {
+		return hotelOrder, err
+	}
+	isActive := false
+	var keptHotelOrderRooms []models.HotelOrderRoom
+	for _, keptHotelOrderRoom := range hotelOrderRooms {
+		if keptHotelOrderRoom.Status != "active" {
+			continue
+		}
+		if keptHotelOrderRoom.ID == hotelOrderRoom.ID {
+			isActive = true
+			continue
+		}
+		keptHotelOrderRooms = append(keptHotelOrderRooms, keptHotelOrderRoom)
+	}
+	if !isActive {
+		return hotelOrder, errors.New("Hotel order room is not active")
+	}
+	if len(keptHotelOrderRooms) == 0 {
+		return m.transitionHotelOrder(hotelOrder, status, actor, changedBy, "Last room canceled")
+	}
+
+	hotelOrderRoom.Status = status
+	_, err = m.hotelOrderRepo.UpdateHotelOrderRoom(hotelOrderRoom)
+	if err != nil {
+		return hotelOrder, err
+	}
+	err = m.hotelOrderRepo.DeleteHotelRoomBookingsByHotelOrderRoomID(hotelOrderRoom.ID)
+	if err != nil {
+		return hotelOrder, err
+	}
+
+	// The order keeps pointing at a room it still books
+	hotelOrder.HotelRoomID = keptHotelOrderRooms[0].HotelRoomID
+	hotelOrder.QuantityAdult -= hotelOrderRoom.QuantityAdult
+	hotelOrder.QuantityInfant -= hotelOrderRoom.QuantityInfant
+	hotelOrder.Price -= hotelOrderRoom.Price
+	hotelOrder.TotalAmount -= hotelOrderRoom.Price
+	hotelOrder, err = m.hotelOrderRepo.UpdateHotelOrder(hotelOrder)
+	if err != nil {
+		return hotelOrder, err
+	}
+
+	note := fmt.Sprintf("Room line %d %s, %d taken off the total", hotelOrderRoom.ID, status, hotelOrderRoom.Price)
+	err = m.createOrderStatusHistory(orderTypeHotel, hotelOrder.ID, hotelOrder.Status, hotelOrder.Status, changedBy, note)
+	if err != nil {
+		return hotelOrder, err
+	}
+
+	return hotelOrder, nil
+}
+
+func (m *orderStateMachine) createOrderStatusHistory(orderType string, orderID uint, fromStatus, toStatus string, changedBy uint, note string) error {
+	_, err := m.orderStatusHistoryRepo.CreateOrderStatusHistory(models.OrderStatusHistory{
+		OrderType:  orderType,
+		OrderID:    orderID,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		ChangedBy:  changedBy,
+		Note:       note,
+	})
+	return err
+}
+
+// checkOrderTransition reports why an actor may not move an order from one status to another.
+func checkOrderTransition(orderType, fromStatus, toStatus, actor string) error {
+	// A paid order only leaves through the refund workflow, which applies the refund policy
+	if fromStatus == "paid" && (toStatus == "canceled" || toStatus == "refund") && actor != orderActorAdmin {
+		return errors.New("Paid orders must be canceled through a refund request")
+	}
+
+	actors, ok := orderTransitions[orderType][fromStatus][toStatus]
+	if !ok {
+		return errors.New("Order status cannot change from " + fromStatus + " to " + toStatus)
+	}
+	for _, allowedActor := range actors {
+		if allowedActor == actor {
+			return nil
+		}
+	}
+	return errors.New("Order status cannot be changed to " + toStatus + " by " + actor)
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/repositories"
+	"errors"
+)
+
+type OrderStatusHistoryUsecase interface {
+	GetOrderStatusHistories(userID uint, orderType string, orderID uint) ([]dtos.OrderStatusHistoryResponse, error)
+	UpdateOrderStatusByAdmin(adminID uint, orderType string, orderID uint, orderStatusInput dtos.OrderStatusInput) ([]dtos.OrderStatusHistoryResponse, error)
+}
+
+type orderStatusHistoryUsecase struct {
+	orderStatusHistoryRepo repositories.OrderStatusHistoryRepository
+	ticketOrderRepo        repositories.TicketOrderRepository
+	hotelOrderRepo         repositories.HotelOrderRepository
+	orderStateMachine      OrderStateMachine
+}
+
+func NewOrderStatusHistoryUsecase(orderStatusHistoryRepo repositories.OrderStatusHistoryRepository, ticketOrderRepo repositories.TicketOrderRepository, hotelOrderRepo repositories.HotelOrderRepository, orderStateMachine OrderStateMachine) OrderStatusHistoryUsecase {
+	return &orderStatusHistoryUsecase{orderStatusHistoryRepo, ticketOrderRepo, hotelOrderRepo, orderStateMachine}
+}
+
+// GetOrderStatusHistories godoc
+// @Summary      Get order status history
+// @Description  Get every status change of a ticket or hotel order, oldest first
+// @Tags         User - Order
+// @Accept       json
+// @Produce      json
+// @Param order_type query string true "Order type" Enums(ticket, hotel)
+// @Param order_id query int true "Order ID"
+// @Success      200 {object} dtos.GetAllOrderStatusHistoryStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/order/status-history [get]
+// @Security BearerAuth
+func (u *orderStatusHistoryUsecase) GetOrderStatusHistories(userID uint, orderType string, orderID uint) ([]dtos.OrderStatusHistoryResponse, error) {
+	// Make sure the order belongs to the user, an admin passes user id 1 and may read any order
+	switch orderType {
+	case orderTypeTicket:
+		ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByID(orderID, userID)
+		if err != nil {
+			return nil, err
+		}
+		orderID = ticketOrder.ID
+	case orderTypeHotel:
+		hotelOrder, err := u.hotelOrderRepo.GetHotelOrderByID(orderID, userID)
+		if err != nil {
+			return nil, err
+		}
+		orderID = hotelOrder.ID
+	default:
+		return nil, errors.New("Order type must be ticket or hotel")
+	}
+
+	return u.getOrderStatusHistoryResponses(orderType, orderID)
+}
+
+// UpdateOrderStatusByAdmin godoc
+// @Summary      Update order status
+// @Description  Confirm the payment of an order paid outside Midtrans, finish it or cancel it while unpaid. A paid order is refunded through a refund request
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Order ID"
+// @Param order body dtos.OrderStatusInput true "Order status"
+// @Success      200 {object} dtos.GetAllOrderStatusHistoryStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/ticket/{id}/status [patch]
+// @Router       /admin/order/hotel/{id}/status [patch]
+// @Security BearerAuth
+func (u *orderStatusHistoryUsecase) UpdateOrderStatusByAdmin(adminID uint, orderType string, orderID uint, orderStatusInput dtos.OrderStatusInput) ([]dtos.OrderStatusHistoryResponse, error) {
+	if orderStatusInput.Status == "refund" {
+		return nil, errors.New("Paid orders must be refunded through a refund request")
+	}
+
+	note := orderStatusInput.Note
+	if note == "" {
+		note = "Changed by admin"
+	}
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	orderStateMachine := u.orderStateMachine.WithTx(tx)
+
+	// The order is locked so the expiry job or Midtrans can not move it while the admin does
+	switch orderType {
+	case orderTypeTicket:
+		ticketOrder, err := u.ticketOrderRepo.WithTx(tx).GetTicketOrderByIDForUpdate(orderID)
+		if err != nil {
+			return nil, err
+		}
+		_, err = orderStateMachine.TransitionTicketOrder(ticketOrder, orderStatusInput.Status, orderActorAdmin, adminID, note)
+		if err != nil {
+			return nil, err
+		}
+	case orderTypeHotel:
+		hotelOrder, err := u.hotelOrderRepo.WithTx(tx).GetHotelOrderByIDForUpdate(orderID)
+		if err != nil {
+			return nil, err
+		}
+		_, err = orderStateMachine.TransitionHotelOrder(hotelOrder, orderStatusInput.Status, orderActorAdmin, adminID, note)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("Order type must be ticket or hotel")
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		return nil, err
+	}
+
+	return u.getOrderStatusHistoryResponses(orderType, orderID)
+}
+
+func (u *orderStatusHistoryUsecase) getOrderStatusHistoryResponses(orderType string, orderID uint) ([]dtos.OrderStatusHistoryResponse, error) {
+	orderStatusHistories, err := u.orderStatusHistoryRepo.GetOrderStatusHistories(orderType, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderStatusHistoryResponses := []dtos.OrderStatusHistoryResponse{}
+	for _, orderStatusHistory := range orderStatusHistories {
+		orderStatusHistoryResponses = append(orderStatusHistoryResponses, dtos.OrderStatusHistoryResponse{
+			OrderStatusHistoryID: orderStatusHistory.ID,
+			OrderType:            orderStatusHistory.OrderType,
+			OrderID:              orderStatusHistory.OrderID,
+			FromStatus:           orderStatusHistory.FromStatus,
+			ToStatus:             orderStatusHistory.ToStatus,
+			ChangedBy:            orderStatusHistory.ChangedBy,
+			Note:                 orderStatusHistory.Note,
+			CreatedAt:            orderStatusHistory.CreatedAt,
+		})
+	}
+	return orderStatusHistoryResponses, nil
+}
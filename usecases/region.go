@@ -0,0 +1,296 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+)
+
+// regionLevels are the levels of the region master from the top down.
+var regionLevels = []string{"province", "regency", "district"}
+
+// regionPath places a hotel or a station in the region master. A place known only down to its regency
+// has no district.
+type regionPath struct {
+	ProvinceID uint
+	RegencyID  uint
+	DistrictID uint
+}
+
+type RegionUsecase interface {
+	GetRegions(parentID uint, name string) ([]dtos.RegionResponse, error)
+	GetRegionByID(id uint) (dtos.RegionResponse, error)
+	CreateRegion(regionInput dtos.RegionInput) (dtos.RegionResponse, error)
+	UpdateRegion(id uint, regionInput dtos.RegionInput) (dtos.RegionResponse, error)
+	DeleteRegion(id uint) error
+}
+
+type regionUsecase struct {
+	regionRepo repositories.RegionRepository
+}
+
+func NewRegionUsecase(regionRepo repositories.RegionRepository) RegionUsecase {
+	return &regionUsecase{regionRepo}
+}
+
+// GetRegions godoc
+// @Summary      Get regions
+// @Description  Get the provinces, or the regions right below a parent region
+// @Tags         Admin - Region
+// @Accept       json
+// @Produce      json
+// @Param parent_id query int false "ID parent region, provinces when empty"
+// @Param name query string false "Search region name"
+// @Success      200 {object} dtos.GetAllRegionStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /public/region [get]
+func (u *regionUsecase) GetRegions(parentID uint, name string) ([]dtos.RegionResponse, error) {
+	regionResponses := make([]dtos.RegionResponse, 0)
+
+	regions, err := u.regionRepo.GetRegionsByParentID(parentID, name)
+	if err != nil {
+		return regionResponses, err
+	}
+	for _, region := range regions {
+		regionResponses = append(regionResponses, newRegionResponse(region))
+	}
+
+	return regionResponses, nil
+}
+
+// GetRegionByID godoc
+// @Summary      Get region by ID
+// @Description  Get region by ID
+// @Tags         Admin - Region
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID region"
+// @Success      200 {object} dtos.RegionStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /public/region/{id} [get]
+func (u *regionUsecase) GetRegionByID(id uint) (dtos.RegionResponse, error) {
+	region, err := u.regionRepo.GetRegionByID(id)
+	if err != nil {
+		return dtos.RegionResponse{}, err
+	}
+	return newRegionResponse(region), nil
+}
+
+// CreateRegion godoc
+// @Summary      Create region
+// @Description  Create a province, or a regency or district below its parent region
+// @Tags         Admin - Region
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.RegionInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.RegionCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/region [post]
+// @Security BearerAuth
+func (u *regionUsecase) CreateRegion(regionInput dtos.RegionInput) (dtos.RegionResponse, error) {
+	var regionResponse dtos.RegionResponse
+	if strings.TrimSpace(regionInput.Name) == "" {
+		return regionResponse, errors.New("Failed to create region")
+	}
+
+	level, err := u.getRegionLevel(regionInput.ParentID)
+	if err != nil {
+		return regionResponse, err
+	}
+
+	createdRegion, err := u.regionRepo.CreateRegion(models.Region{
+		ParentID: regionInput.ParentID,
+		Level:    level,
+		Name:     strings.TrimSpace(regionInput.Name),
+	})
+	if err != nil {
+		return regionResponse, err
+	}
+
+	return newRegionResponse(createdRegion), nil
+}
+
+// UpdateRegion godoc
+// @Summary      Update region
+// @Description  Rename a region. Its parent may only change to another region of the same level
+// @Tags         Admin - Region
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID region"
+// @Param        request body dtos.RegionInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.RegionStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/region/{id} [put]
+// @Security BearerAuth
+func (u *regionUsecase) UpdateRegion(id uint, regionInput dtos.RegionInput) (dtos.RegionResponse, error) {
+	var regionResponse dtos.RegionResponse
+	if strings.TrimSpace(regionInput.Name) == "" {
+		return regionResponse, errors.New("Failed to update region")
+	}
+
+	region, err := u.regionRepo.GetRegionByID(id)
+	if err != nil {
+		return regionResponse, err
+	}
+
+	if regionInput.ParentID != region.ParentID {
+		// Hotels and stations keep the regions above theirs, so a region in use stays where it is
+		inUse, err := u.regionRepo.IsRegionInUse(region.ID)
+		if err != nil {
+			return regionResponse, err
+		}
+		if inUse {
+			return regionResponse, errors.New("Region in use can not move to another parent")
+		}
+
+		level, err := u.getRegionLevel(regionInput.ParentID)
+		if err != nil {
+			return regionResponse, err
+		}
+		if level != region.Level {
+			return regionResponse, errors.New("Region can only move below a region of the same level as its parent")
+		}
+		region.ParentID = regionInput.ParentID
+	}
+	region.Name = strings.TrimSpace(regionInput.Name)
+
+	region, err = u.regionRepo.UpdateRegion(region)
+	if err != nil {
+		return regionResponse, err
+	}
+
+	return newRegionResponse(region), nil
+}
+
+// DeleteRegion godoc
+// @Summary      Delete region
+// @Description  Delete a region no other region, hotel or station is placed in
+// @Tags         Admin - Region
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID region"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/region/{id} [delete]
+// @Security BearerAuth
+func (u *regionUsecase) DeleteRegion(id uint) error {
+	region, err := u.regionRepo.GetRegionByID(id)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := u.regionRepo.IsRegionInUse(region.ID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("Region is still in use")
+	}
+
+	return u.regionRepo.DeleteRegion(region)
+}
+
+// getRegionLevel returns the level of a region created below parentID, a province when it has no parent.
+func (u *regionUsecase) getRegionLevel(parentID uint) (string, error) {
+	if parentID == 0 {
+		return regionLevels[0], nil
+	}
+
+	parent, err := u.regionRepo.GetRegionByID(parentID)
+	if err != nil {
+		return "", errors.New("Parent region not found")
+	}
+	for i, level := range regionLevels[:len(regionLevels)-1] {
+		if parent.Level == level {
+			return regionLevels[i+1], nil
+		}
+	}
+	return "", errors.New("District can not have regions below it")
+}
+
+func newRegionResponse(region models.Region) dtos.RegionResponse {
+	return dtos.RegionResponse{
+		RegionID: region.ID,
+		ParentID: region.ParentID,
+		Level:    region.Level,
+		Name:     region.Name,
+	}
+}
+
+// resolveRegionPath places something in regionID and every region above it.
+func resolveRegionPath(regionRepo repositories.RegionRepository, regionID uint) (regionPath, error) {
+	var path regionPath
+	for regionID != 0 {
+		region, err := regionRepo.GetRegionByID(regionID)
+		if err != nil {
+			return path, errors.New("Region not found")
+		}
+		switch region.Level {
+		case "province":
+			path.ProvinceID = region.ID
+		case "regency":
+			path.RegencyID = region.ID
+		case "district":
+			path.DistrictID = region.ID
+		}
+		regionID = region.ParentID
+	}
+	return path, nil
+}
+
+// getRegionResponses returns the regions of a place from its province down.
+func getRegionResponses(regionRepo repositories.RegionRepository, path regionPath) []dtos.RegionResponse {
+	var ids []uint
+	for _, id := range []uint{path.ProvinceID, path.RegencyID, path.DistrictID} {
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	regions, err := regionRepo.GetRegionsByIDs(ids)
+	if err != nil {
+		return nil
+	}
+	regionByID := make(map[uint]models.Region)
+	for _, region := range regions {
+		regionByID[region.ID] = region
+	}
+
+	var regionResponses []dtos.RegionResponse
+	for _, id := range ids {
+		if region, ok := regionByID[id]; ok {
+			regionResponses = append(regionResponses, newRegionResponse(region))
+		}
+	}
+	return regionResponses
+}
+
+func hotelRegionPath(hotel models.Hotel) regionPath {
+	return regionPath{ProvinceID: hotel.ProvinceID, RegencyID: hotel.RegencyID, DistrictID: hotel.DistrictID}
+}
+
+func stationRegionPath(station models.Station) regionPath {
+	return regionPath{ProvinceID: station.ProvinceID, RegencyID: station.RegencyID, DistrictID: station.DistrictID}
+}
@@ -0,0 +1,244 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+)
+
+type SavedPassengerUsecase interface {
+	GetSavedPassengers(page, limit int, userID uint) ([]dtos.SavedPassengerResponse, int, error)
+	GetSavedPassengerByID(userID, id uint) (dtos.SavedPassengerResponse, error)
+	CreateSavedPassenger(userID uint, savedPassengerInput dtos.SavedPassengerInput) (dtos.SavedPassengerResponse, error)
+	UpdateSavedPassenger(userID, id uint, savedPassengerInput dtos.SavedPassengerInput) (dtos.SavedPassengerResponse, error)
+	DeleteSavedPassenger(userID, id uint) error
+}
+
+type savedPassengerUsecase struct {
+	savedPassengerRepo repositories.SavedPassengerRepository
+}
+
+func NewSavedPassengerUsecase(savedPassengerRepo repositories.SavedPassengerRepository) SavedPassengerUsecase {
+	return &savedPassengerUsecase{savedPassengerRepo}
+}
+
+// GetSavedPassengers godoc
+// @Summary      Get saved passengers
+// @Description  Get the passengers saved in the address book of the user
+// @Tags         User - Saved Passenger
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success      200 {object} dtos.GetAllSavedPassengerStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/saved-passenger [get]
+// @Security BearerAuth
+func (u *savedPassengerUsecase) GetSavedPassengers(page, limit int, userID uint) ([]dtos.SavedPassengerResponse, int, error) {
+	var savedPassengerResponses []dtos.SavedPassengerResponse
+
+	savedPassengers, count, err := u.savedPassengerRepo.GetSavedPassengers(page, limit, userID)
+	if err != nil {
+		return savedPassengerResponses, 0, err
+	}
+
+	for _, savedPassenger := range savedPassengers {
+		savedPassengerResponses = append(savedPassengerResponses, newSavedPassengerResponse(savedPassenger))
+	}
+
+	return savedPassengerResponses, count, nil
+}
+
+// GetSavedPassengerByID godoc
+// @Summary      Get saved passenger by ID
+// @Description  Get a passenger saved in the address book of the user
+// @Tags         User - Saved Passenger
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID saved passenger"
+// @Success      200 {object} dtos.SavedPassengerStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/saved-passenger/{id} [get]
+// @Security BearerAuth
+func (u *savedPassengerUsecase) GetSavedPassengerByID(userID, id uint) (dtos.SavedPassengerResponse, error) {
+	var savedPassengerResponse dtos.SavedPassengerResponse
+
+	savedPassenger, err := u.savedPassengerRepo.GetSavedPassengerByID(userID, id)
+	if err != nil {
+		return savedPassengerResponse, errors.New("Saved passenger not found")
+	}
+
+	return newSavedPassengerResponse(savedPassenger), nil
+}
+
+// CreateSavedPassenger godoc
+// @Summary      Create saved passenger
+// @Description  Save a passenger to the address book of the user, id_type is ktp, passport or none
+// @Tags         User - Saved Passenger
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.SavedPassengerInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.SavedPassengerCreeatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/saved-passenger [post]
+// @Security BearerAuth
+func (u *savedPassengerUsecase) CreateSavedPassenger(userID uint, savedPassengerInput dtos.SavedPassengerInput) (dtos.SavedPassengerResponse, error) {
+	var savedPassengerResponse dtos.SavedPassengerResponse
+
+	savedPassenger := models.SavedPassenger{UserID: userID}
+	savedPassenger, err := applySavedPassengerInput(savedPassenger, savedPassengerInput)
+	if err != nil {
+		return savedPassengerResponse, err
+	}
+
+	savedPassenger, err = u.savedPassengerRepo.CreateSavedPassenger(savedPassenger)
+	if err != nil {
+		return savedPassengerResponse, err
+	}
+
+	return newSavedPassengerResponse(savedPassenger), nil
+}
+
+// UpdateSavedPassenger godoc
+// @Summary      Update saved passenger
+// @Description  Update a passenger saved in the address book of the user
+// @Tags         User - Saved Passenger
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID saved passenger"
+// @Param        request body dtos.SavedPassengerInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.SavedPassengerStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/saved-passenger/{id} [put]
+// @Security BearerAuth
+func (u *savedPassengerUsecase) UpdateSavedPassenger(userID, id uint, savedPassengerInput dtos.SavedPassengerInput) (dtos.SavedPassengerResponse, error) {
+	var savedPassengerResponse dtos.SavedPassengerResponse
+
+	savedPassenger, err := u.savedPassengerRepo.GetSavedPassengerByID(userID, id)
+	if err != nil {
+		return savedPassengerResponse, errors.New("Saved passenger not found")
+	}
+
+	savedPassenger, err = applySavedPassengerInput(savedPassenger, savedPassengerInput)
+	if err != nil {
+		return savedPassengerResponse, err
+	}
+
+	savedPassenger, err = u.savedPassengerRepo.UpdateSavedPassenger(savedPassenger)
+	if err != nil {
+		return savedPassengerResponse, err
+	}
+
+	return newSavedPassengerResponse(savedPassenger), nil
+}
+
+// DeleteSavedPassenger godoc
+// @Summary      Delete saved passenger
+// @Description  Delete a passenger from the address book of the user, past orders keep their traveler data
+// @Tags         User - Saved Passenger
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID saved passenger"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/saved-passenger/{id} [delete]
+// @Security BearerAuth
+func (u *savedPassengerUsecase) DeleteSavedPassenger(userID, id uint) error {
+	savedPassenger, err := u.savedPassengerRepo.GetSavedPassengerByID(userID, id)
+	if err != nil {
+		return errors.New("Saved passenger not found")
+	}
+	return u.savedPassengerRepo.DeleteSavedPassenger(savedPassenger)
+}
+
+func applySavedPassengerInput(savedPassenger models.SavedPassenger, savedPassengerInput dtos.SavedPassengerInput) (models.SavedPassenger, error) {
+	if savedPassengerInput.Title == "" || savedPassengerInput.FullName == "" {
+		return savedPassenger, errors.New("Title and full name are required")
+	}
+
+	idType := normalizeTravelerIDType(savedPassengerInput.IDType, savedPassengerInput.IDNumber)
+	idNumber := strings.ToUpper(strings.TrimSpace(savedPassengerInput.IDNumber))
+	err := validateTravelerDocument(idType, idNumber)
+	if err != nil {
+		return savedPassenger, err
+	}
+
+	savedPassenger.BirthDate = nil
+	if savedPassengerInput.BirthDate != "" {
+		birthDate, err := helpers.FormatStringToDate(savedPassengerInput.BirthDate)
+		if err != nil || birthDate.After(time.Now()) {
+			return savedPassenger, errors.New("Birth date invalid")
+		}
+		savedPassenger.BirthDate = &birthDate
+	}
+
+	savedPassenger.Title = savedPassengerInput.Title
+	savedPassenger.FullName = savedPassengerInput.FullName
+	savedPassenger.IDType = idType
+	savedPassenger.IDNumber = idNumber
+	return savedPassenger, nil
+}
+
+func newSavedPassengerResponse(savedPassenger models.SavedPassenger) dtos.SavedPassengerResponse {
+	return dtos.SavedPassengerResponse{
+		SavedPassengerID: savedPassenger.ID,
+		Title:            savedPassenger.Title,
+		FullName:         savedPassenger.FullName,
+		IDType:           savedPassenger.IDType,
+		IDNumber:         savedPassenger.IDNumber,
+		BirthDate:        helpers.FormatDateToYMD(savedPassenger.BirthDate),
+		CreatedAt:        savedPassenger.CreatedAt,
+		UpdatedAt:        savedPassenger.UpdatedAt,
+	}
+}
+
+// resolveSavedPassengers replaces every traveler given by saved_passenger_id with the data stored in the
+// address book of the user, travelers typed inline are kept as they are.
+func resolveSavedPassengers(savedPassengerRepo repositories.SavedPassengerRepository, userID uint, travelerDetails []dtos.TravelerDetailInput) ([]dtos.TravelerDetailInput, error) {
+	resolved := make([]dtos.TravelerDetailInput, 0, len(travelerDetails))
+	for _, travelerDetail := range travelerDetails {
+		if travelerDetail.SavedPassengerID == 0 {
+			resolved = append(resolved, travelerDetail)
+			continue
+		}
+
+		savedPassenger, err := savedPassengerRepo.GetSavedPassengerByID(userID, travelerDetail.SavedPassengerID)
+		if err != nil {
+			return nil, errors.New("Saved passenger not found")
+		}
+		resolved = append(resolved, dtos.TravelerDetailInput{
+			SavedPassengerID: savedPassenger.ID,
+			Title:            savedPassenger.Title,
+			FullName:         savedPassenger.FullName,
+			IDType:           savedPassenger.IDType,
+			IDCardNumber:     savedPassenger.IDNumber,
+			BirthDate:        helpers.FormatDateToYMD(savedPassenger.BirthDate),
+			PassengerType:    travelerDetail.PassengerType,
+		})
+	}
+	return resolved, nil
+}
@@ -0,0 +1,321 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const maxSeatLayoutColumns = 26
+
+type SeatLayoutUsecase interface {
+	GetAllSeatLayouts(page, limit int) ([]dtos.SeatLayoutResponse, int, error)
+	GetSeatLayoutByID(id uint) (dtos.SeatLayoutResponse, error)
+	CreateSeatLayout(seatLayoutInput dtos.SeatLayoutInput) (dtos.SeatLayoutResponse, error)
+	UpdateSeatLayout(id uint, seatLayoutInput dtos.SeatLayoutInput) (dtos.SeatLayoutResponse, error)
+	DeleteSeatLayout(id uint) error
+}
+
+type seatLayoutUsecase struct {
+	seatLayoutRepo           repositories.SeatLayoutRepository
+	trainCarriageRepo        repositories.TrainCarriageRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+}
+
+func NewSeatLayoutUsecase(seatLayoutRepo repositories.SeatLayoutRepository, trainCarriageRepo repositories.TrainCarriageRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository) SeatLayoutUsecase {
+	return &seatLayoutUsecase{seatLayoutRepo, trainCarriageRepo, ticketTravelerDetailRepo}
+}
+
+// GetAllSeatLayouts godoc
+// @Summary      Get all seat layout
+// @Description  Get all seat layout
+// @Tags         Admin - Seat Layout
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success      200 {object} dtos.GetAllSeatLayoutStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/seat-layout [get]
+// @Security BearerAuth
+func (u *seatLayoutUsecase) GetAllSeatLayouts(page, limit int) ([]dtos.SeatLayoutResponse, int, error) {
+	seatLayouts, count, err := u.seatLayoutRepo.GetAllSeatLayouts(page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var seatLayoutResponses []dtos.SeatLayoutResponse
+	for _, seatLayout := range seatLayouts {
+		seatLayoutResponses = append(seatLayoutResponses, newSeatLayoutResponse(seatLayout))
+	}
+
+	return seatLayoutResponses, count, nil
+}
+
+// GetSeatLayoutByID godoc
+// @Summary      Get seat layout by ID
+// @Description  Get seat layout by ID
+// @Tags         Admin - Seat Layout
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID seat layout"
+// @Success      200 {object} dtos.SeatLayoutStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/seat-layout/{id} [get]
+// @Security BearerAuth
+func (u *seatLayoutUsecase) GetSeatLayoutByID(id uint) (dtos.SeatLayoutResponse, error) {
+	var seatLayoutResponse dtos.SeatLayoutResponse
+	seatLayout, err := u.seatLayoutRepo.GetSeatLayoutByID(id)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+	return newSeatLayoutResponse(seatLayout), nil
+}
+
+// CreateSeatLayout godoc
+// @Summary      Create a new seat layout
+// @Description  Create a seat layout template that can be assigned to train carriages
+// @Tags         Admin - Seat Layout
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.SeatLayoutInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.SeatLayoutCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/seat-layout [post]
+// @Security BearerAuth
+func (u *seatLayoutUsecase) CreateSeatLayout(seatLayoutInput dtos.SeatLayoutInput) (dtos.SeatLayoutResponse, error) {
+	var seatLayoutResponse dtos.SeatLayoutResponse
+
+	createSeatLayout, err := newSeatLayout(models.SeatLayout{}, seatLayoutInput)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	createSeatLayout, err = u.seatLayoutRepo.CreateSeatLayout(createSeatLayout)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	return newSeatLayoutResponse(createSeatLayout), nil
+}
+
+// UpdateSeatLayout godoc
+// @Summary      Update seat layout
+// @Description  Update a seat layout and regenerate the seats of the carriages using it
+// @Tags         Admin - Seat Layout
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID seat layout"
+// @Param        request body dtos.SeatLayoutInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.SeatLayoutStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/seat-layout/{id} [put]
+// @Security BearerAuth
+func (u *seatLayoutUsecase) UpdateSeatLayout(id uint, seatLayoutInput dtos.SeatLayoutInput) (dtos.SeatLayoutResponse, error) {
+	var seatLayoutResponse dtos.SeatLayoutResponse
+
+	seatLayout, err := u.seatLayoutRepo.GetSeatLayoutByID(id)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	seatLayout, err = newSeatLayout(seatLayout, seatLayoutInput)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	trainCarriages, err := u.trainCarriageRepo.GetTrainCarriagesBySeatLayoutID(seatLayout.ID)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	// Seats that were already sold must keep their position
+	for _, trainCarriage := range trainCarriages {
+		ticketTravelerDetail, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainCarriageID(trainCarriage.ID)
+		if ticketTravelerDetail.ID > 0 {
+			return seatLayoutResponse, errors.New("Seat layout is used by a train carriage with orders")
+		}
+	}
+
+	// The layout and every carriage seat map change together, a failure leaves the old ones in place
+	tx := u.trainCarriageRepo.BeginTransaction()
+	defer tx.Rollback()
+
+	seatLayout, err = u.seatLayoutRepo.WithTx(tx).UpdateSeatLayout(seatLayout)
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	for _, trainCarriage := range trainCarriages {
+		err = generateTrainSeats(u.trainCarriageRepo.WithTx(tx), seatLayout, trainCarriage)
+		if err != nil {
+			return seatLayoutResponse, err
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return seatLayoutResponse, err
+	}
+
+	return newSeatLayoutResponse(seatLayout), nil
+}
+
+// DeleteSeatLayout godoc
+// @Summary      Delete a seat layout
+// @Description  Delete a seat layout
+// @Tags         Admin - Seat Layout
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID seat layout"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/seat-layout/{id} [delete]
+// @Security BearerAuth
+func (u *seatLayoutUsecase) DeleteSeatLayout(id uint) error {
+	_, err := u.seatLayoutRepo.GetSeatLayoutByID(id)
+	if err != nil {
+		return err
+	}
+
+	trainCarriages, err := u.trainCarriageRepo.GetTrainCarriagesBySeatLayoutID(id)
+	if err != nil {
+		return err
+	}
+	if len(trainCarriages) > 0 {
+		return errors.New("Seat layout is still assigned to a train carriage")
+	}
+
+	return u.seatLayoutRepo.DeleteSeatLayout(id)
+}
+
+func newSeatLayout(seatLayout models.SeatLayout, seatLayoutInput dtos.SeatLayoutInput) (models.SeatLayout, error) {
+	if seatLayoutInput.Name == "" || seatLayoutInput.Class == "" || seatLayoutInput.Rows < 1 || seatLayoutInput.Columns < 1 {
+		return seatLayout, errors.New("Failed to create seat layout")
+	}
+	if seatLayoutInput.Columns > maxSeatLayoutColumns {
+		return seatLayout, errors.New("Columns must be at most 26")
+	}
+	if seatLayoutInput.AisleAfterColumn < 0 || seatLayoutInput.AisleAfterColumn >= seatLayoutInput.Columns {
+		return seatLayout, errors.New("Aisle must be between two columns")
+	}
+
+	var disabledSeats []string
+	for _, disabledSeat := range seatLayoutInput.DisabledSeats {
+		disabledSeat = strings.ToUpper(strings.TrimSpace(disabledSeat))
+		row, column := parseTrainSeatName(disabledSeat)
+		if row < 1 || row > seatLayoutInput.Rows || column > seatLayoutInput.Columns {
+			return seatLayout, errors.New("Disabled seat is outside of the seat layout")
+		}
+		disabledSeats = append(disabledSeats, disabledSeat)
+	}
+
+	seatLayout.Name = seatLayoutInput.Name
+	seatLayout.Class = seatLayoutInput.Class
+	seatLayout.Rows = seatLayoutInput.Rows
+	seatLayout.Columns = seatLayoutInput.Columns
+	seatLayout.AisleAfterColumn = seatLayoutInput.AisleAfterColumn
+	seatLayout.DisabledSeats = strings.Join(disabledSeats, ",")
+	return seatLayout, nil
+}
+
+func newSeatLayoutResponse(seatLayout models.SeatLayout) dtos.SeatLayoutResponse {
+	disabledSeats := make([]string, 0)
+	if seatLayout.DisabledSeats != "" {
+		disabledSeats = strings.Split(seatLayout.DisabledSeats, ",")
+	}
+
+	return dtos.SeatLayoutResponse{
+		SeatLayoutID:     seatLayout.ID,
+		Name:             seatLayout.Name,
+		Class:            seatLayout.Class,
+		Rows:             seatLayout.Rows,
+		Columns:          seatLayout.Columns,
+		AisleAfterColumn: seatLayout.AisleAfterColumn,
+		DisabledSeats:    disabledSeats,
+		CreatedAt:        seatLayout.CreatedAt,
+		UpdatedAt:        seatLayout.UpdatedAt,
+	}
+}
+
+// generateTrainSeats replaces the seats of a train carriage with the seats
+// described by the seat layout. Seats are named by column letter and row
+// number, e.g. "A1".
+func generateTrainSeats(trainCarriageRepo repositories.TrainCarriageRepository, seatLayout models.SeatLayout, trainCarriage models.TrainCarriage) error {
+	disabledSeats := make(map[string]bool)
+	for _, disabledSeat := range strings.Split(seatLayout.DisabledSeats, ",") {
+		disabledSeats[disabledSeat] = true
+	}
+
+	var trainSeats []models.TrainSeat
+	for row := 1; row <= seatLayout.Rows; row++ {
+		for column := 1; column <= seatLayout.Columns; column++ {
+			name := fmt.Sprintf("%c%d", 'A'+column-1, row)
+			trainSeats = append(trainSeats, models.TrainSeat{
+				TrainCarriageID: trainCarriage.ID,
+				Class:           trainCarriage.Class,
+				Name:            name,
+				Row:             row,
+				Column:          column,
+				IsWindow:        column == 1 || column == seatLayout.Columns,
+				IsAisle:         seatLayout.AisleAfterColumn > 0 && (column == seatLayout.AisleAfterColumn || column == seatLayout.AisleAfterColumn+1),
+				IsDisabled:      disabledSeats[name],
+			})
+		}
+	}
+
+	err := trainCarriageRepo.DeleteTrainSeatsByTrainCarriageID(trainCarriage.ID)
+	if err != nil {
+		return err
+	}
+	return trainCarriageRepo.CreateTrainSeats(trainSeats)
+}
+
+// isTrainSeatInCarriage reports whether the seat can be booked in the train
+// carriage.
+func isTrainSeatInCarriage(trainSeat models.TrainSeat, trainCarriage models.TrainCarriage) bool {
+	if trainSeat.IsDisabled {
+		return false
+	}
+	if trainCarriage.SeatLayoutID > 0 {
+		return trainSeat.TrainCarriageID == trainCarriage.ID
+	}
+	return trainSeat.TrainCarriageID == 0 && strings.EqualFold(trainSeat.Class, trainCarriage.Class)
+}
+
+// parseTrainSeatName splits a seat name such as "C12" into its row and
+// column. It is used for the shared class seats, which only store a name.
+func parseTrainSeatName(name string) (int, int) {
+	if len(name) < 2 || name[0] < 'A' || name[0] > 'Z' {
+		return 0, 0
+	}
+	row, err := strconv.Atoi(name[1:])
+	if err != nil {
+		return 0, 0
+	}
+	return row, int(name[0]-'A') + 1
+}
@@ -13,17 +13,20 @@ type StationUsecase interface {
 	GetAllStations(page, limit int) ([]dtos.StationResponse, int, error)
 	GetAllStationsByAdmin(page, limit int, search, sortBy, filter string) ([]dtos.StationResponse, int, error)
 	GetStationByID(id uint) (dtos.StationResponse, error)
+	AutocompleteStation(keyword string, limit int) ([]dtos.StationAutocompleteResponse, error)
 	CreateStation(station *dtos.StationInput) (dtos.StationResponse, error)
 	UpdateStation(id uint, station dtos.StationInput) (dtos.StationResponse, error)
+	UpdateStationLocation(id uint, locationInput dtos.LocationInput) (dtos.StationResponse, error)
 	DeleteStation(id uint) error
 }
 
 type stationUsecase struct {
 	stationRepo repositories.StationRepository
+	regionRepo  repositories.RegionRepository
 }
 
-func NewStationUsecase(StationRepo repositories.StationRepository) StationUsecase {
-	return &stationUsecase{StationRepo}
+func NewStationUsecase(StationRepo repositories.StationRepository, regionRepo repositories.RegionRepository) StationUsecase {
+	return &stationUsecase{StationRepo, regionRepo}
 }
 
 // GetAllStations godoc
@@ -54,6 +57,9 @@ func (u *stationUsecase) GetAllStations(page, limit int) ([]dtos.StationResponse
 			Origin:    station.Origin,
 			Name:      station.Name,
 			Initial:   station.Initial,
+			Latitude:  station.Latitude,
+			Longitude: station.Longitude,
+			Region:    getRegionResponses(u.regionRepo, stationRegionPath(station)),
 			CreatedAt: station.CreatedAt,
 			UpdatedAt: station.UpdatedAt,
 		}
@@ -107,6 +113,9 @@ func (u *stationUsecase) GetAllStationsByAdmin(page, limit int, search, sortBy,
 			Origin:    station.Origin,
 			Name:      station.Name,
 			Initial:   station.Initial,
+			Latitude:  station.Latitude,
+			Longitude: station.Longitude,
+			Region:    getRegionResponses(u.regionRepo, stationRegionPath(station)),
 			CreatedAt: station.CreatedAt,
 			UpdatedAt: station.UpdatedAt,
 			// DeletedAt: &deletedStation,
@@ -152,12 +161,196 @@ func (u *stationUsecase) GetStationByID(id uint) (dtos.StationResponse, error) {
 		Origin:    station.Origin,
 		Name:      station.Name,
 		Initial:   station.Initial,
+		Latitude:  station.Latitude,
+		Longitude: station.Longitude,
+		Region:    getRegionResponses(u.regionRepo, stationRegionPath(station)),
 		CreatedAt: station.CreatedAt,
 		UpdatedAt: station.UpdatedAt,
 	}
 	return stationResponse, nil
 }
 
+// AutocompleteStation godoc
+// @Summary      Autocomplete station and city
+// @Description  Suggest cities and stations by name, initial or city, tolerating typos after the first letter
+// @Tags         Public - Station
+// @Accept       json
+// @Produce      json
+// @Param keyword query string true "Keyword"
+// @Param limit query int false "Number of suggestions"
+// @Success      200 {object} dtos.StationAutocompleteStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /public/station/autocomplete [get]
+func (u *stationUsecase) AutocompleteStation(keyword string, limit int) ([]dtos.StationAutocompleteResponse, error) {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return nil, errors.New("Keyword is required")
+	}
+
+	// The database narrows the stations down, only that shortlist is ranked here
+	stations, err := u.stationRepo.GetStationsByKeyword(keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	type stationSuggestion struct {
+		score    int
+		response dtos.StationAutocompleteResponse
+	}
+
+	var suggestions []stationSuggestion
+	cities := make(map[string]int)
+	for _, station := range stations {
+		cityKey := strings.ToLower(station.Origin)
+		if index, ok := cities[cityKey]; ok {
+			suggestions[index].response.StationCount++
+		} else if score := stationMatchScore(keyword, station.Origin); score > 0 {
+			cities[cityKey] = len(suggestions)
+			suggestions = append(suggestions, stationSuggestion{
+				score: score,
+				response: dtos.StationAutocompleteResponse{
+					Type:         "city",
+					Origin:       station.Origin,
+					StationCount: 1,
+				},
+			})
+		}
+
+		score := stationMatchScore(keyword, station.Name)
+		if initialScore := stationMatchScore(keyword, station.Initial); initialScore > score {
+			score = initialScore
+		}
+		if originScore := stationMatchScore(keyword, station.Origin); originScore > score {
+			score = originScore
+		}
+		if score == 0 {
+			continue
+		}
+		suggestions = append(suggestions, stationSuggestion{
+			score: score,
+			response: dtos.StationAutocompleteResponse{
+				Type:      "station",
+				StationID: station.ID,
+				Origin:    station.Origin,
+				Name:      station.Name,
+				Initial:   station.Initial,
+			},
+		})
+	}
+
+	// Best match first, a city before its stations on a tie
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].score != suggestions[j].score {
+			return suggestions[i].score > suggestions[j].score
+		}
+		return suggestions[i].response.Type == "city" && suggestions[j].response.Type != "city"
+	})
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	var stationAutocompleteResponses []dtos.StationAutocompleteResponse
+	for _, suggestion := range suggestions {
+		stationAutocompleteResponses = append(stationAutocompleteResponses, suggestion.response)
+	}
+
+	return stationAutocompleteResponses, nil
+}
+
+// stationMatchScore rates how well a lowercase keyword matches a station
+// field, from an exact match down to a near miss within a small edit distance.
+// A zero score means no match.
+func stationMatchScore(keyword, text string) int {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return 0
+	}
+	switch {
+	case text == keyword:
+		return 100
+	case strings.HasPrefix(text, keyword):
+		return 80
+	}
+	for _, word := range strings.Fields(text) {
+		if strings.HasPrefix(word, keyword) {
+			return 70
+		}
+	}
+	if strings.Contains(text, keyword) {
+		return 60
+	}
+	if isSubsequence(keyword, text) && len(keyword) >= 3 {
+		return 40
+	}
+
+	// Allow roughly one typo for every four characters typed
+	maxDistance := len(keyword) / 4
+	if maxDistance == 0 {
+		return 0
+	}
+	bestDistance := maxDistance + 1
+	for _, word := range append(strings.Fields(text), text) {
+		if len(word) > len(keyword) {
+			word = word[:len(keyword)]
+		}
+		if distance := levenshteinDistance(keyword, word); distance < bestDistance {
+			bestDistance = distance
+		}
+	}
+	if bestDistance > maxDistance {
+		return 0
+	}
+	return 30 - bestDistance
+}
+
+// isSubsequence reports whether every character of keyword appears in text in
+// order, so "psn" matches "pasar senen".
+func isSubsequence(keyword, text string) bool {
+	index := 0
+	for i := 0; i < len(text) && index < len(keyword); i++ {
+		if text[i] == keyword[index] {
+			index++
+		}
+	}
+	return index == len(keyword)
+}
+
+// levenshteinDistance counts the single character edits needed to turn a into b.
+func levenshteinDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // CreateStation godoc
 // @Summary      Create a new station
 // @Description  Create a new station
@@ -178,13 +371,25 @@ func (u *stationUsecase) CreateStation(station *dtos.StationInput) (dtos.Station
 	if station.Initial == "" || station.Name == "" || station.Origin == "" {
 		return stationResponses, errors.New("Failed to create station")
 	}
+	if _, err := validateLocation(station.Latitude, station.Longitude); err != nil {
+		return stationResponses, err
+	}
+	stationRegion, err := resolveRegionPath(u.regionRepo, station.RegionID)
+	if err != nil {
+		return stationResponses, err
+	}
 	station.Name = strings.ToUpper(station.Name)
 	station.Origin = strings.ToUpper(station.Origin)
 	station.Initial = strings.ToUpper(station.Initial)
 	createStation := models.Station{
-		Origin:  station.Origin,
-		Name:    station.Name,
-		Initial: station.Initial,
+		Origin:     station.Origin,
+		Name:       station.Name,
+		Initial:    station.Initial,
+		Latitude:   station.Latitude,
+		Longitude:  station.Longitude,
+		ProvinceID: stationRegion.ProvinceID,
+		RegencyID:  stationRegion.RegencyID,
+		DistrictID: stationRegion.DistrictID,
 	}
 
 	createdStation, err := u.stationRepo.CreateStation(createStation)
@@ -197,6 +402,9 @@ func (u *stationUsecase) CreateStation(station *dtos.StationInput) (dtos.Station
 		Origin:    createdStation.Origin,
 		Name:      createdStation.Name,
 		Initial:   createdStation.Initial,
+		Latitude:  createdStation.Latitude,
+		Longitude: createdStation.Longitude,
+		Region:    getRegionResponses(u.regionRepo, stationRegionPath(createdStation)),
 		CreatedAt: createdStation.CreatedAt,
 		UpdatedAt: createdStation.UpdatedAt,
 	}
@@ -225,8 +433,16 @@ func (u *stationUsecase) UpdateStation(id uint, stationInput dtos.StationInput)
 	if stationInput.Initial == "" || stationInput.Name == "" || stationInput.Origin == "" {
 		return stationResponse, errors.New("Failed to update station")
 	}
+	hasLocation, err := validateLocation(stationInput.Latitude, stationInput.Longitude)
+	if err != nil {
+		return stationResponse, err
+	}
+	stationRegion, err := resolveRegionPath(u.regionRepo, stationInput.RegionID)
+	if err != nil {
+		return stationResponse, err
+	}
 
-	station, err := u.stationRepo.GetStationByID(id)
+	station, err = u.stationRepo.GetStationByID(id)
 	if err != nil {
 		return stationResponse, err
 	}
@@ -234,6 +450,15 @@ func (u *stationUsecase) UpdateStation(id uint, stationInput dtos.StationInput)
 	station.Origin = stationInput.Origin
 	station.Name = stationInput.Name
 	station.Initial = stationInput.Initial
+	if hasLocation {
+		station.Latitude = stationInput.Latitude
+		station.Longitude = stationInput.Longitude
+	}
+	if stationInput.RegionID != 0 {
+		station.ProvinceID = stationRegion.ProvinceID
+		station.RegencyID = stationRegion.RegencyID
+		station.DistrictID = stationRegion.DistrictID
+	}
 
 	station.Name = strings.ToUpper(station.Name)
 	station.Origin = strings.ToUpper(station.Origin)
@@ -249,6 +474,9 @@ func (u *stationUsecase) UpdateStation(id uint, stationInput dtos.StationInput)
 	stationResponse.Origin = station.Origin
 	stationResponse.Name = station.Name
 	stationResponse.Initial = station.Initial
+	stationResponse.Latitude = station.Latitude
+	stationResponse.Longitude = station.Longitude
+	stationResponse.Region = getRegionResponses(u.regionRepo, stationRegionPath(station))
 	stationResponse.CreatedAt = station.CreatedAt
 	stationResponse.UpdatedAt = station.UpdatedAt
 
@@ -256,6 +484,43 @@ func (u *stationUsecase) UpdateStation(id uint, stationInput dtos.StationInput)
 
 }
 
+// UpdateStationLocation godoc
+// @Summary      Update station location
+// @Description  Set the coordinates of a station, or clear them when neither latitude nor longitude is given
+// @Tags         Admin - Station
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID station"
+// @Param        request body dtos.LocationInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.StationStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/station/{id}/location [put]
+// @Security BearerAuth
+func (u *stationUsecase) UpdateStationLocation(id uint, locationInput dtos.LocationInput) (dtos.StationResponse, error) {
+	var stationResponse dtos.StationResponse
+	if _, err := validateLocation(locationInput.Latitude, locationInput.Longitude); err != nil {
+		return stationResponse, err
+	}
+
+	station, err := u.stationRepo.GetStationByID(id)
+	if err != nil {
+		return stationResponse, err
+	}
+
+	station.Latitude = locationInput.Latitude
+	station.Longitude = locationInput.Longitude
+	station, err = u.stationRepo.UpdateStation(station)
+	if err != nil {
+		return stationResponse, err
+	}
+
+	return u.GetStationByID(station.ID)
+}
+
 // DeleteStation godoc
 // @Summary      Delete a station
 // @Description  Delete a station
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
 )
@@ -35,10 +34,15 @@ type ticketOrderUsecase struct {
 	paymentRepo              repositories.PaymentRepository
 	userRepo                 repositories.UserRepository
 	notificationRepo         repositories.NotificationRepository
+	trainScheduleRepo        repositories.TrainScheduleRepository
+	trainSeatHoldRepo        repositories.TrainSeatHoldRepository
+	trainFareRepo            repositories.TrainFareRepository
+	orderStateMachine        OrderStateMachine
+	savedPassengerRepo       repositories.SavedPassengerRepository
 }
 
-func NewTicketOrderUsecase(ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, travelerDetailRepo repositories.TravelerDetailRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainRepo repositories.TrainRepository, trainSeatRepo repositories.TrainSeatRepository, stationRepo repositories.StationRepository, trainStationRepo repositories.TrainStationRepository, paymentRepo repositories.PaymentRepository, userRepo repositories.UserRepository, notificationRepo repositories.NotificationRepository) TicketOrderUsecase {
-	return &ticketOrderUsecase{ticketOrderRepo, ticketTravelerDetailRepo, travelerDetailRepo, trainCarriageRepo, trainRepo, trainSeatRepo, stationRepo, trainStationRepo, paymentRepo, userRepo, notificationRepo}
+func NewTicketOrderUsecase(ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, travelerDetailRepo repositories.TravelerDetailRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainRepo repositories.TrainRepository, trainSeatRepo repositories.TrainSeatRepository, stationRepo repositories.StationRepository, trainStationRepo repositories.TrainStationRepository, paymentRepo repositories.PaymentRepository, userRepo repositories.UserRepository, notificationRepo repositories.NotificationRepository, trainScheduleRepo repositories.TrainScheduleRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, trainFareRepo repositories.TrainFareRepository, orderStateMachine OrderStateMachine, savedPassengerRepo repositories.SavedPassengerRepository) TicketOrderUsecase {
+	return &ticketOrderUsecase{ticketOrderRepo, ticketTravelerDetailRepo, travelerDetailRepo, trainCarriageRepo, trainRepo, trainSeatRepo, stationRepo, trainStationRepo, paymentRepo, userRepo, notificationRepo, trainScheduleRepo, trainSeatHoldRepo, trainFareRepo, orderStateMachine, savedPassengerRepo}
 }
 
 // GetTicketOrders godoc
@@ -749,14 +753,20 @@ func (u *ticketOrderUsecase) GetTicketOrderByID(userID, ticketOrderId, trainId u
 
 	InitiateCoreApiClient()
 
-	if getTicketOrder.PaymentID == 0 {
-		res, _ := c.CheckTransaction(getTicketOrder.TicketOrderCode)
-		if res.TransactionStatus == "settlement" {
-			getTicketOrder.Status = "paid"
-			_, _ = u.ticketOrderRepo.UpdateTicketOrder(getTicketOrder)
-		} else {
-			getTicketOrder.Status = "canceled"
-			_, _ = u.ticketOrderRepo.UpdateTicketOrder(getTicketOrder)
+	// Only an unpaid order follows Midtrans, a refunded or finished order must not be reopened. A pending
+	// payment or a failed lookup leaves the order unpaid, overdue orders are left to the expiry job
+	if getTicketOrder.PaymentID == 0 && getTicketOrder.Status == "unpaid" {
+		res, midtransErr := c.CheckTransaction(getTicketOrder.TicketOrderCode)
+		if midtransErr == nil && res != nil {
+			switch res.TransactionStatus {
+			case "settlement":
+				getTicketOrder, err = u.orderStateMachine.TransitionTicketOrder(getTicketOrder, "paid", orderActorSystem, 0, "Midtrans settlement")
+			case "expire", "cancel", "deny":
+				getTicketOrder, err = u.orderStateMachine.TransitionTicketOrder(getTicketOrder, "canceled", orderActorSystem, 0, "Midtrans transaction "+res.TransactionStatus)
+			}
+			if err != nil {
+				return ticketTravelerDetailResponses, err
+			}
 		}
 	}
 
@@ -893,13 +903,19 @@ func (u *ticketOrderUsecase) GetTicketOrderByID(userID, ticketOrderId, trainId u
 // @Security BearerAuth
 func (u *ticketOrderUsecase) CreateTicketOrder(userID uint, ticketOrderInput dtos.TicketOrderInput) (dtos.TicketOrderResponse, error) {
 	var ticketOrderResponse dtos.TicketOrderResponse
-	sumTrainPrice := 0
-	trainPrice := 0
 	if ticketOrderInput.QuantityAdult < 1 || ticketOrderInput.PaymentID < 1 || ticketOrderInput.NameOrder == "" || ticketOrderInput.EmailOrder == "" || ticketOrderInput.PhoneNumberOrder == "" || ticketOrderInput.TravelerDetail == nil || ticketOrderInput.TicketTravelerDetailDeparture == nil {
 		return ticketOrderResponse, errors.New("Failed to create ticket order")
 	}
-	if ticketOrderInput.QuantityInfant > 1 {
-		return ticketOrderResponse, errors.New("Quantity infant not existing")
+
+	travelerDetails, err := resolveSavedPassengers(u.savedPassengerRepo, userID, ticketOrderInput.TravelerDetail)
+	if err != nil {
+		return ticketOrderResponse, err
+	}
+	ticketOrderInput.TravelerDetail = travelerDetails
+
+	travelerIdentities, err := checkTicketOrderTravelers(ticketOrderInput)
+	if err != nil {
+		return ticketOrderResponse, err
 	}
 	createTicketOrder := models.TicketOrder{
 		UserID:           userID,
@@ -921,7 +937,18 @@ func (u *ticketOrderUsecase) CreateTicketOrder(userID uint, ticketOrderInput dto
 		return ticketOrderResponse, errors.New("failed to get payment id")
 	}
 
-	createTicketOrder, err = u.ticketOrderRepo.CreateTicketOrder(createTicketOrder)
+	// Everything below is written in one transaction, so a failed order leaves no rows behind
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketOrderRepo := u.ticketOrderRepo.WithTx(tx)
+	notificationRepo := u.notificationRepo.WithTx(tx)
+
+	createTicketOrder, err = ticketOrderRepo.CreateTicketOrder(createTicketOrder)
+	if err != nil {
+		return ticketOrderResponse, err
+	}
+
+	err = u.orderStateMachine.WithTx(tx).RecordTicketOrder(createTicketOrder, userID)
 	if err != nil {
 		return ticketOrderResponse, err
 	}
@@ -932,330 +959,31 @@ func (u *ticketOrderUsecase) CreateTicketOrder(userID uint, ticketOrderInput dto
 			TemplateID: 7,
 		}
 
-		_, err = u.notificationRepo.CreateNotification(createNotification)
+		_, err = notificationRepo.CreateNotification(createNotification)
 		if err != nil {
 			return ticketOrderResponse, err
 		}
 	}
 
-	var ticketTravelerDetailDepartureResponses []dtos.TicketTravelerDetailResponse
-
-	for _, travelerDetail := range ticketOrderInput.TravelerDetail {
-		if travelerDetail.Title == "" || travelerDetail.FullName == "" {
-			return ticketOrderResponse, errors.New("Failed to create ticket order")
-		}
-		createTravelerDetail := models.TravelerDetail{
-			UserID:        userID,
-			TicketOrderID: &createTicketOrder.ID,
-			Title:         travelerDetail.Title,
-			FullName:      travelerDetail.FullName,
-			IDCardNumber:  &travelerDetail.IDCardNumber,
-		}
-		createTravelerDetail, err := u.travelerDetailRepo.CreateTravelerDetail(createTravelerDetail)
-		if err != nil {
-			return ticketOrderResponse, err
-		}
-
-		if ticketOrderInput.QuantityAdult != len(ticketOrderInput.TicketTravelerDetailDeparture) {
-			return ticketOrderResponse, errors.New("Quantity adult and ticket traveler detail departure does not match")
-		}
-
-		for _, ticketTravelerDetailDeparture := range ticketOrderInput.TicketTravelerDetailDeparture {
-			if ticketTravelerDetailDeparture.TrainCarriageID < 1 || ticketTravelerDetailDeparture.TrainSeatID < 1 || ticketTravelerDetailDeparture.StationOriginID < 1 || ticketTravelerDetailDeparture.StationDestinationID < 1 || ticketTravelerDetailDeparture.Date == "" {
-				return ticketOrderResponse, errors.New("Failed to create ticket order")
-			}
-
-			now := time.Now()
-			today := now.Format("2006-01-02")
-
-			if today > ticketTravelerDetailDeparture.Date {
-				return ticketOrderResponse, errors.New("Departure date must be earlier than")
-			}
-
-			dateDepartureParse, err := helpers.FormatStringToDate(ticketTravelerDetailDeparture.Date)
-			if err != nil {
-				return ticketOrderResponse, errors.New("Failed to parsing date")
-			}
-
-			getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(uint(ticketTravelerDetailDeparture.TrainCarriageID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train carriage id")
-			}
-
-			getTrain, err := u.trainRepo.GetTrainByID2(uint(getTrainCarriage.TrainID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train id")
-			}
-
-			getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, uint(ticketTravelerDetailDeparture.StationOriginID), uint(ticketTravelerDetailDeparture.StationDestinationID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train station")
-			}
-
-			if getTrain.Status != "available" {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train")
-			}
-
-			// Check if route[0] matches stationOriginId and route[1] matches stationDestinationId
-			if len(getTrainStation) < 2 || getTrainStation[0].StationID != uint(ticketTravelerDetailDeparture.StationOriginID) || getTrainStation[1].StationID != uint(ticketTravelerDetailDeparture.StationDestinationID) {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train")
-			}
-
-			if travelerDetail.IDCardNumber != "" {
-				trainPrice = getTrainCarriage.Price
-				sumTrainPrice += trainPrice
-			} else {
-				trainPrice = 0
-				sumTrainPrice += trainPrice
-			}
-
-			getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(uint(ticketTravelerDetailDeparture.TrainSeatID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, err
-			}
-			getStationOrigin, err := u.stationRepo.GetStationByID2(uint(ticketTravelerDetailDeparture.StationOriginID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get station origin id")
-			}
-			getStationDestination, err := u.stationRepo.GetStationByID2(uint(ticketTravelerDetailDeparture.StationDestinationID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get station destination id")
-			}
-
-			trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationOrigin.ID)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, err
-			}
-			trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationDestination.ID)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, err
-			}
-
-			createTicketTravelerDetail := models.TicketTravelerDetail{
-				TicketOrderID:        createTicketOrder.ID,
-				TravelerDetailID:     createTravelerDetail.ID,
-				TrainID:              uint(getTrain.ID),
-				TrainPrice:           trainPrice,
-				TrainCarriageID:      uint(getTrainCarriage.ID),
-				TrainSeatID:          uint(getTrainSeat.ID),
-				StationOriginID:      uint(getStationOrigin.ID),
-				DepartureTime:        trainStationOrigin.ArriveTime,
-				StationDestinationID: uint(getStationDestination.ID),
-				ArrivalTime:          trainStationDestination.ArriveTime,
-				DateOfDeparture:      dateDepartureParse,
-				BoardingTicketCode:   "boarding-ticket-" + uuid.New().String(),
-			}
-			createTicketTravelerDetail, err = u.ticketTravelerDetailRepo.CreateTicketTravelerDetail(createTicketTravelerDetail)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-				return ticketOrderResponse, err
-			}
-
-			getTravelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(createTicketTravelerDetail.TravelerDetailID)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-				return ticketOrderResponse, err
-			}
-
-			ticketTravelerDetailResponse := dtos.TicketTravelerDetailResponse{
-				TicketTravelerDetailID: int(createTicketTravelerDetail.ID),
-				TravelerDetail: dtos.TravelerDetailResponse{
-					ID:           int(getTravelerDetail.ID),
-					Title:        getTravelerDetail.Title,
-					FullName:     getTravelerDetail.FullName,
-					IDCardNumber: *getTravelerDetail.IDCardNumber,
-				},
-				Train: dtos.TrainResponsesSimply{
-					TrainID:         getTrain.ID,
-					CodeTrain:       getTrain.CodeTrain,
-					Name:            getTrain.Name,
-					Class:           getTrainCarriage.Class,
-					TrainPrice:      trainPrice,
-					TrainCarriageID: getTrainCarriage.ID,
-					TrainCarriage:   getTrainCarriage.Name,
-					TrainSeatID:     getTrainSeat.ID,
-					TrainSeat:       getTrainSeat.Name,
-				},
-				StationOrigin: dtos.StationResponseSimply{
-					StationID:  getStationOrigin.ID,
-					Origin:     getStationOrigin.Origin,
-					Name:       getStationOrigin.Name,
-					Initial:    getStationOrigin.Initial,
-					ArriveTime: createTicketTravelerDetail.DepartureTime,
-				},
-				StationDestination: dtos.StationResponseSimply{
-					StationID:  getStationDestination.ID,
-					Origin:     getStationDestination.Origin,
-					Name:       getStationDestination.Name,
-					Initial:    getStationDestination.Initial,
-					ArriveTime: createTicketTravelerDetail.ArrivalTime,
-				},
-				Date:               createTicketTravelerDetail.DateOfDeparture,
-				BoardingTicketCode: createTicketTravelerDetail.BoardingTicketCode,
-			}
-			ticketTravelerDetailDepartureResponses = append(ticketTravelerDetailDepartureResponses, ticketTravelerDetailResponse)
-		}
-
-		if createTicketOrder.WithReturn {
-
-			if ticketOrderInput.QuantityAdult != len(ticketOrderInput.TicketTravelerDetailReturn) {
-				return ticketOrderResponse, errors.New("Quantity adult and ticket traveler detail return does not match")
-			}
-			for _, ticketTravelerDetailReturn := range ticketOrderInput.TicketTravelerDetailReturn {
-				if ticketTravelerDetailReturn.TrainCarriageID < 1 || ticketTravelerDetailReturn.TrainSeatID < 1 || ticketTravelerDetailReturn.StationOriginID < 1 || ticketTravelerDetailReturn.StationDestinationID < 1 || ticketTravelerDetailReturn.Date == "" {
-					return ticketOrderResponse, errors.New("Failed to create ticket order")
-				}
-
-				now := time.Now()
-				today := now.Format("2006-01-02")
-
-				if today > ticketTravelerDetailReturn.Date {
-					return ticketOrderResponse, errors.New("Return date must be earlier than")
-				}
-
-				dateReturn, err := helpers.FormatStringToDate(ticketTravelerDetailReturn.Date)
-				if err != nil {
-					return ticketOrderResponse, errors.New("Failed to parsing date")
-				}
-
-				getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(uint(ticketTravelerDetailReturn.TrainCarriageID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get train carriage id")
-				}
-
-				getTrain, err := u.trainRepo.GetTrainByID2(getTrainCarriage.TrainID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get train id")
-				}
-
-				if travelerDetail.IDCardNumber != "" {
-					trainPrice = getTrainCarriage.Price
-					sumTrainPrice += trainPrice
-				} else {
-					trainPrice = 0
-					sumTrainPrice += trainPrice
-				}
-
-				getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(uint(ticketTravelerDetailReturn.TrainSeatID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get train seat id")
-				}
-				getStationOrigin, err := u.stationRepo.GetStationByID(uint(ticketTravelerDetailReturn.StationOriginID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get station origin id")
-				}
-				getStationDestination, err := u.stationRepo.GetStationByID(uint(ticketTravelerDetailReturn.StationDestinationID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get station destination id")
-				}
-
-				trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationOrigin.ID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, err
-				}
-				trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationDestination.ID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, err
-				}
-
-				createTicketTravelerDetail := models.TicketTravelerDetail{
-					TicketOrderID:        createTicketOrder.ID,
-					TravelerDetailID:     createTravelerDetail.ID,
-					TrainID:              uint(getTrain.ID),
-					TrainPrice:           trainPrice,
-					TrainCarriageID:      uint(getTrainCarriage.ID),
-					TrainSeatID:          uint(getTrainSeat.ID),
-					StationOriginID:      uint(getStationOrigin.ID),
-					DepartureTime:        trainStationOrigin.ArriveTime,
-					StationDestinationID: uint(getStationDestination.ID),
-					ArrivalTime:          trainStationDestination.ArriveTime,
-					DateOfDeparture:      dateReturn,
-					BoardingTicketCode:   "boarding-ticket-" + uuid.New().String(),
-				}
-				createTicketTravelerDetail, err = u.ticketTravelerDetailRepo.CreateTicketTravelerDetail(createTicketTravelerDetail)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-					return ticketOrderResponse, err
-				}
-
-				getTravelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(createTicketTravelerDetail.TravelerDetailID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-					return ticketOrderResponse, err
-				}
-
-				ticketTravelerDetailResponse := dtos.TicketTravelerDetailResponse{
-					TicketTravelerDetailID: int(createTicketTravelerDetail.ID),
-					TravelerDetail: dtos.TravelerDetailResponse{
-						ID:           int(getTravelerDetail.ID),
-						Title:        getTravelerDetail.Title,
-						FullName:     getTravelerDetail.FullName,
-						IDCardNumber: *getTravelerDetail.IDCardNumber,
-					},
-					Train: dtos.TrainResponsesSimply{
-						TrainID:         getTrain.ID,
-						CodeTrain:       getTrain.CodeTrain,
-						Name:            getTrain.Name,
-						Class:           getTrainCarriage.Class,
-						TrainPrice:      trainPrice,
-						TrainCarriageID: getTrainCarriage.ID,
-						TrainCarriage:   getTrainCarriage.Name,
-						TrainSeatID:     getTrainSeat.ID,
-						TrainSeat:       getTrainSeat.Name,
-					},
-					StationOrigin: dtos.StationResponseSimply{
-						StationID:  getStationOrigin.ID,
-						Origin:     getStationOrigin.Origin,
-						Name:       getStationOrigin.Name,
-						Initial:    getStationOrigin.Initial,
-						ArriveTime: createTicketTravelerDetail.DepartureTime,
-					},
-					StationDestination: dtos.StationResponseSimply{
-						StationID:  getStationDestination.ID,
-						Origin:     getStationDestination.Origin,
-						Name:       getStationDestination.Name,
-						Initial:    getStationDestination.Initial,
-						ArriveTime: createTicketTravelerDetail.ArrivalTime,
-					},
-					Date:               createTicketTravelerDetail.DateOfDeparture,
-					BoardingTicketCode: createTicketTravelerDetail.BoardingTicketCode,
-				}
-				ticketTravelerDetailDepartureResponses = append(ticketTravelerDetailDepartureResponses, ticketTravelerDetailResponse)
-			}
-		}
-
+	ticketTravelerDetailDepartureResponses, sumTrainPrice, err := u.createTicketOrderTickets(tx, userID, createTicketOrder, ticketOrderInput, travelerIdentities)
+	if err != nil {
+		return ticketOrderResponse, err
 	}
 
 	createTicketOrder.Price = sumTrainPrice
-	createTicketOrder.TotalAmount = sumTrainPrice * ticketOrderInput.QuantityAdult
+	createTicketOrder.TotalAmount = sumTrainPrice
+
+	updateTicketOrder, err := ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+	if err != nil {
+		return ticketOrderResponse, err
+	}
 
-	updateTicketOrder, err := u.ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+	getOrderTicket, err := ticketOrderRepo.GetTicketOrderByID(updateTicketOrder.ID, userID)
 	if err != nil {
 		return ticketOrderResponse, err
 	}
 
-	getOrderTicket, err := u.ticketOrderRepo.GetTicketOrderByID(updateTicketOrder.ID, userID)
+	err = tx.Commit().Error
 	if err != nil {
 		return ticketOrderResponse, err
 	}
@@ -1304,15 +1032,21 @@ func (u *ticketOrderUsecase) CreateTicketOrder(userID uint, ticketOrderInput dto
 // @Security BearerAuth
 func (u *ticketOrderUsecase) CreateTicketOrderMidtrans(userID uint, ticketOrderInput dtos.TicketOrderInput) (dtos.TicketOrderResponseMidtrans, error) {
 	var ticketOrderResponse dtos.TicketOrderResponseMidtrans
-	sumTrainPrice := 0
-	trainPrice := 0
 	if ticketOrderInput.QuantityAdult < 1 || ticketOrderInput.NameOrder == "" || ticketOrderInput.EmailOrder == "" || ticketOrderInput.PhoneNumberOrder == "" || ticketOrderInput.TravelerDetail == nil || ticketOrderInput.TicketTravelerDetailDeparture == nil {
 		return ticketOrderResponse, errors.New("Failed to create ticket order")
 	}
 
-	if ticketOrderInput.QuantityInfant > 1 {
-		return ticketOrderResponse, errors.New("Quantity infant not existing")
+	travelerDetails, err := resolveSavedPassengers(u.savedPassengerRepo, userID, ticketOrderInput.TravelerDetail)
+	if err != nil {
+		return ticketOrderResponse, err
+	}
+	ticketOrderInput.TravelerDetail = travelerDetails
+
+	travelerIdentities, err := checkTicketOrderTravelers(ticketOrderInput)
+	if err != nil {
+		return ticketOrderResponse, err
 	}
+
 	createTicketOrder := models.TicketOrder{
 		UserID:           userID,
 		QuantityAdult:    ticketOrderInput.QuantityAdult,
@@ -1328,7 +1062,18 @@ func (u *ticketOrderUsecase) CreateTicketOrderMidtrans(userID uint, ticketOrderI
 		Status:           "unpaid",
 	}
 
-	createTicketOrder, err := u.ticketOrderRepo.CreateTicketOrder(createTicketOrder)
+	// Everything below is written in one transaction, so a failed order leaves no rows behind
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketOrderRepo := u.ticketOrderRepo.WithTx(tx)
+	notificationRepo := u.notificationRepo.WithTx(tx)
+
+	createTicketOrder, err = ticketOrderRepo.CreateTicketOrder(createTicketOrder)
+	if err != nil {
+		return ticketOrderResponse, err
+	}
+
+	err = u.orderStateMachine.WithTx(tx).RecordTicketOrder(createTicketOrder, userID)
 	if err != nil {
 		return ticketOrderResponse, err
 	}
@@ -1339,331 +1084,31 @@ func (u *ticketOrderUsecase) CreateTicketOrderMidtrans(userID uint, ticketOrderI
 			TemplateID: 7,
 		}
 
-		_, err = u.notificationRepo.CreateNotification(createNotification)
+		_, err = notificationRepo.CreateNotification(createNotification)
 		if err != nil {
 			return ticketOrderResponse, err
 		}
 	}
 
-	var ticketTravelerDetailDepartureResponses []dtos.TicketTravelerDetailResponse
-
-	for _, travelerDetail := range ticketOrderInput.TravelerDetail {
-		if travelerDetail.Title == "" || travelerDetail.FullName == "" {
-			return ticketOrderResponse, errors.New("Failed to create ticket order")
-		}
-		createTravelerDetail := models.TravelerDetail{
-			UserID:        userID,
-			TicketOrderID: &createTicketOrder.ID,
-			Title:         travelerDetail.Title,
-			FullName:      travelerDetail.FullName,
-			IDCardNumber:  &travelerDetail.IDCardNumber,
-		}
-		createTravelerDetail, err := u.travelerDetailRepo.CreateTravelerDetail(createTravelerDetail)
-		if err != nil {
-			return ticketOrderResponse, err
-		}
-
-		if ticketOrderInput.QuantityAdult != len(ticketOrderInput.TicketTravelerDetailDeparture) {
-			return ticketOrderResponse, errors.New("Quantity adult and ticket traveler detail departure does not match")
-		}
-
-		for _, ticketTravelerDetailDeparture := range ticketOrderInput.TicketTravelerDetailDeparture {
-			if ticketTravelerDetailDeparture.TrainCarriageID < 1 || ticketTravelerDetailDeparture.TrainSeatID < 1 || ticketTravelerDetailDeparture.StationOriginID < 1 || ticketTravelerDetailDeparture.StationDestinationID < 1 || ticketTravelerDetailDeparture.Date == "" {
-				return ticketOrderResponse, errors.New("Failed to create ticket order")
-			}
-
-			now := time.Now()
-			today := now.Format("2006-01-02")
-
-			if today > ticketTravelerDetailDeparture.Date {
-				return ticketOrderResponse, errors.New("Departure date must be earlier than")
-			}
-
-			dateDepartureParse, err := helpers.FormatStringToDate(ticketTravelerDetailDeparture.Date)
-			if err != nil {
-				return ticketOrderResponse, errors.New("Failed to parsing date")
-			}
-
-			getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(uint(ticketTravelerDetailDeparture.TrainCarriageID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train carriage id")
-			}
-
-			getTrain, err := u.trainRepo.GetTrainByID2(uint(getTrainCarriage.TrainID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train id")
-			}
-
-			getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, uint(ticketTravelerDetailDeparture.StationOriginID), uint(ticketTravelerDetailDeparture.StationDestinationID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train station")
-			}
-
-			if getTrain.Status != "available" {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train")
-			}
-
-			// Check if route[0] matches stationOriginId and route[1] matches stationDestinationId
-			if len(getTrainStation) < 2 || getTrainStation[0].StationID != uint(ticketTravelerDetailDeparture.StationOriginID) || getTrainStation[1].StationID != uint(ticketTravelerDetailDeparture.StationDestinationID) {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get train")
-			}
-
-			if travelerDetail.IDCardNumber != "" {
-				trainPrice = getTrainCarriage.Price
-				sumTrainPrice += trainPrice
-			} else {
-				trainPrice = 0
-				sumTrainPrice += trainPrice
-			}
-
-			getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(uint(ticketTravelerDetailDeparture.TrainSeatID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, err
-			}
-			getStationOrigin, err := u.stationRepo.GetStationByID2(uint(ticketTravelerDetailDeparture.StationOriginID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get station origin id")
-			}
-			getStationDestination, err := u.stationRepo.GetStationByID2(uint(ticketTravelerDetailDeparture.StationDestinationID))
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, errors.New("Failed to get station destination id")
-			}
-
-			trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationOrigin.ID)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, err
-			}
-			trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationDestination.ID)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				return ticketOrderResponse, err
-			}
-
-			createTicketTravelerDetail := models.TicketTravelerDetail{
-				TicketOrderID:        createTicketOrder.ID,
-				TravelerDetailID:     createTravelerDetail.ID,
-				TrainID:              uint(getTrain.ID),
-				TrainPrice:           trainPrice,
-				TrainCarriageID:      uint(getTrainCarriage.ID),
-				TrainSeatID:          uint(getTrainSeat.ID),
-				StationOriginID:      uint(getStationOrigin.ID),
-				DepartureTime:        trainStationOrigin.ArriveTime,
-				StationDestinationID: uint(getStationDestination.ID),
-				ArrivalTime:          trainStationDestination.ArriveTime,
-				DateOfDeparture:      dateDepartureParse,
-				BoardingTicketCode:   "boarding-ticket-" + uuid.New().String(),
-			}
-			createTicketTravelerDetail, err = u.ticketTravelerDetailRepo.CreateTicketTravelerDetail(createTicketTravelerDetail)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-				return ticketOrderResponse, err
-			}
-
-			getTravelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(createTicketTravelerDetail.TravelerDetailID)
-			if err != nil {
-				_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-				_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-				return ticketOrderResponse, err
-			}
-
-			ticketTravelerDetailResponse := dtos.TicketTravelerDetailResponse{
-				TicketTravelerDetailID: int(createTicketTravelerDetail.ID),
-				TravelerDetail: dtos.TravelerDetailResponse{
-					ID:           int(getTravelerDetail.ID),
-					Title:        getTravelerDetail.Title,
-					FullName:     getTravelerDetail.FullName,
-					IDCardNumber: *getTravelerDetail.IDCardNumber,
-				},
-				Train: dtos.TrainResponsesSimply{
-					TrainID:         getTrain.ID,
-					CodeTrain:       getTrain.CodeTrain,
-					Name:            getTrain.Name,
-					Class:           getTrainCarriage.Class,
-					TrainPrice:      trainPrice,
-					TrainCarriageID: getTrainCarriage.ID,
-					TrainCarriage:   getTrainCarriage.Name,
-					TrainSeatID:     getTrainSeat.ID,
-					TrainSeat:       getTrainSeat.Name,
-				},
-				StationOrigin: dtos.StationResponseSimply{
-					StationID:  getStationOrigin.ID,
-					Origin:     getStationOrigin.Origin,
-					Name:       getStationOrigin.Name,
-					Initial:    getStationOrigin.Initial,
-					ArriveTime: createTicketTravelerDetail.DepartureTime,
-				},
-				StationDestination: dtos.StationResponseSimply{
-					StationID:  getStationDestination.ID,
-					Origin:     getStationDestination.Origin,
-					Name:       getStationDestination.Name,
-					Initial:    getStationDestination.Initial,
-					ArriveTime: createTicketTravelerDetail.ArrivalTime,
-				},
-				Date:               createTicketTravelerDetail.DateOfDeparture,
-				BoardingTicketCode: createTicketTravelerDetail.BoardingTicketCode,
-			}
-			ticketTravelerDetailDepartureResponses = append(ticketTravelerDetailDepartureResponses, ticketTravelerDetailResponse)
-		}
-
-		if createTicketOrder.WithReturn {
-
-			if ticketOrderInput.QuantityAdult != len(ticketOrderInput.TicketTravelerDetailReturn) {
-				return ticketOrderResponse, errors.New("Quantity adult and ticket traveler detail return does not match")
-			}
-
-			for _, ticketTravelerDetailReturn := range ticketOrderInput.TicketTravelerDetailReturn {
-				if ticketTravelerDetailReturn.TrainCarriageID < 1 || ticketTravelerDetailReturn.TrainSeatID < 1 || ticketTravelerDetailReturn.StationOriginID < 1 || ticketTravelerDetailReturn.StationDestinationID < 1 || ticketTravelerDetailReturn.Date == "" {
-					return ticketOrderResponse, errors.New("Failed to create ticket order")
-				}
-
-				now := time.Now()
-				today := now.Format("2006-01-02")
-
-				if today > ticketTravelerDetailReturn.Date {
-					return ticketOrderResponse, errors.New("Return date must be earlier than")
-				}
-
-				dateReturn, err := helpers.FormatStringToDate(ticketTravelerDetailReturn.Date)
-				if err != nil {
-					return ticketOrderResponse, errors.New("Failed to parsing date")
-				}
-
-				getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(uint(ticketTravelerDetailReturn.TrainCarriageID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get train carriage id")
-				}
-
-				getTrain, err := u.trainRepo.GetTrainByID2(getTrainCarriage.TrainID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get train id")
-				}
-
-				if travelerDetail.IDCardNumber != "" {
-					trainPrice = getTrainCarriage.Price
-					sumTrainPrice += trainPrice
-				} else {
-					trainPrice = 0
-					sumTrainPrice += trainPrice
-				}
-
-				getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(uint(ticketTravelerDetailReturn.TrainSeatID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get train seat id")
-				}
-				getStationOrigin, err := u.stationRepo.GetStationByID(uint(ticketTravelerDetailReturn.StationOriginID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get station origin id")
-				}
-				getStationDestination, err := u.stationRepo.GetStationByID(uint(ticketTravelerDetailReturn.StationDestinationID))
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, errors.New("Failed to get station destination id")
-				}
-
-				trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationOrigin.ID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, err
-				}
-				trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(getTrain.ID, getStationDestination.ID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					return ticketOrderResponse, err
-				}
-
-				createTicketTravelerDetail := models.TicketTravelerDetail{
-					TicketOrderID:        createTicketOrder.ID,
-					TravelerDetailID:     createTravelerDetail.ID,
-					TrainID:              uint(getTrain.ID),
-					TrainPrice:           trainPrice,
-					TrainCarriageID:      uint(getTrainCarriage.ID),
-					TrainSeatID:          uint(getTrainSeat.ID),
-					StationOriginID:      uint(getStationOrigin.ID),
-					DepartureTime:        trainStationOrigin.ArriveTime,
-					StationDestinationID: uint(getStationDestination.ID),
-					ArrivalTime:          trainStationDestination.ArriveTime,
-					DateOfDeparture:      dateReturn,
-					BoardingTicketCode:   "boarding-ticket-" + uuid.New().String(),
-				}
-				createTicketTravelerDetail, err = u.ticketTravelerDetailRepo.CreateTicketTravelerDetail(createTicketTravelerDetail)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-					return ticketOrderResponse, err
-				}
-
-				getTravelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(createTicketTravelerDetail.TravelerDetailID)
-				if err != nil {
-					_, _ = u.ticketOrderRepo.DeleteTicketOrder(createTicketOrder)
-					_, _ = u.ticketTravelerDetailRepo.DeleteTicketTravelerDetail(createTicketTravelerDetail)
-					return ticketOrderResponse, err
-				}
-
-				ticketTravelerDetailResponse := dtos.TicketTravelerDetailResponse{
-					TicketTravelerDetailID: int(createTicketTravelerDetail.ID),
-					TravelerDetail: dtos.TravelerDetailResponse{
-						ID:           int(getTravelerDetail.ID),
-						Title:        getTravelerDetail.Title,
-						FullName:     getTravelerDetail.FullName,
-						IDCardNumber: *getTravelerDetail.IDCardNumber,
-					},
-					Train: dtos.TrainResponsesSimply{
-						TrainID:         getTrain.ID,
-						CodeTrain:       getTrain.CodeTrain,
-						Name:            getTrain.Name,
-						Class:           getTrainCarriage.Class,
-						TrainPrice:      trainPrice,
-						TrainCarriageID: getTrainCarriage.ID,
-						TrainCarriage:   getTrainCarriage.Name,
-						TrainSeatID:     getTrainSeat.ID,
-						TrainSeat:       getTrainSeat.Name,
-					},
-					StationOrigin: dtos.StationResponseSimply{
-						StationID:  getStationOrigin.ID,
-						Origin:     getStationOrigin.Origin,
-						Name:       getStationOrigin.Name,
-						Initial:    getStationOrigin.Initial,
-						ArriveTime: createTicketTravelerDetail.DepartureTime,
-					},
-					StationDestination: dtos.StationResponseSimply{
-						StationID:  getStationDestination.ID,
-						Origin:     getStationDestination.Origin,
-						Name:       getStationDestination.Name,
-						Initial:    getStationDestination.Initial,
-						ArriveTime: createTicketTravelerDetail.ArrivalTime,
-					},
-					Date:               createTicketTravelerDetail.DateOfDeparture,
-					BoardingTicketCode: createTicketTravelerDetail.BoardingTicketCode,
-				}
-				ticketTravelerDetailDepartureResponses = append(ticketTravelerDetailDepartureResponses, ticketTravelerDetailResponse)
-			}
-		}
-
+	ticketTravelerDetailDepartureResponses, sumTrainPrice, err := u.createTicketOrderTickets(tx, userID, createTicketOrder, ticketOrderInput, travelerIdentities)
+	if err != nil {
+		return ticketOrderResponse, err
 	}
 
 	createTicketOrder.Price = sumTrainPrice
-	createTicketOrder.TotalAmount = sumTrainPrice * ticketOrderInput.QuantityAdult
+	createTicketOrder.TotalAmount = sumTrainPrice
+
+	updateTicketOrder, err := ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+	if err != nil {
+		return ticketOrderResponse, err
+	}
 
-	updateTicketOrder, err := u.ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+	getOrderTicket, err := ticketOrderRepo.GetTicketOrderByID(updateTicketOrder.ID, userID)
 	if err != nil {
 		return ticketOrderResponse, err
 	}
 
-	getOrderTicket, err := u.ticketOrderRepo.GetTicketOrderByID(updateTicketOrder.ID, userID)
+	err = tx.Commit().Error
 	if err != nil {
 		return ticketOrderResponse, err
 	}
@@ -1711,12 +1156,16 @@ func (u *ticketOrderUsecase) CreateTicketOrderMidtrans(userID uint, ticketOrderI
 
 	res, err := c.CheckTransaction(createTicketOrder.TicketOrderCode)
 	if res.TransactionStatus == "settlement" {
-		createTicketOrder.Status = "paid"
-		_, _ = u.ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+		_, err = u.orderStateMachine.TransitionTicketOrder(createTicketOrder, "paid", orderActorSystem, 0, "Midtrans settlement")
+		if err != nil {
+			return ticketOrderResponse, err
+		}
 	}
 	if res.TransactionStatus == "expire" {
-		createTicketOrder.Status = "canceled"
-		_, _ = u.ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+		_, err = u.orderStateMachine.TransitionTicketOrder(createTicketOrder, "canceled", orderActorSystem, 0, "Midtrans transaction expired")
+		if err != nil {
+			return ticketOrderResponse, err
+		}
 	}
 
 	ticketOrderResponse = dtos.TicketOrderResponseMidtrans{
@@ -1746,7 +1195,7 @@ func (u *ticketOrderUsecase) CreateTicketOrderMidtrans(userID uint, ticketOrderI
 // @Accept       json
 // @Produce      json
 // @Param ticket_order_id query int true "Ticket Order ID"
-// @Param status query string true "Update Status Order ID" Enums(paid, done, canceled, refund)
+// @Param status query string true "Update Status Order ID, a paid order is refunded through a refund request" Enums(canceled)
 // @Success      200 {object} dtos.TicketOrderStatusOKResponse
 // @Failure      400 {object} dtos.BadRequestResponse
 // @Failure      401 {object} dtos.UnauthorizedResponse
@@ -1763,41 +1212,11 @@ func (u *ticketOrderUsecase) UpdateTicketOrder(userID, ticketOrderID uint, statu
 		return ticketOrderResponse, err
 	}
 
-	if createTicketOrder.Status == status || status == "unpaid" {
-		return ticketOrderResponse, errors.New("Failed to update hotel order status")
-	}
-
-	createTicketOrder.Status = status
-
-	createTicketOrder, err = u.ticketOrderRepo.UpdateTicketOrder(createTicketOrder)
+	createTicketOrder, err = u.orderStateMachine.TransitionTicketOrder(createTicketOrder, status, orderActorUser, userID, "")
 	if err != nil {
 		return ticketOrderResponse, err
 	}
 
-	if createTicketOrder.ID > 0 && createTicketOrder.Status == "paid" {
-		createNotification := models.Notification{
-			UserID:     userID,
-			TemplateID: 4,
-		}
-
-		_, err = u.notificationRepo.CreateNotification(createNotification)
-		if err != nil {
-			return ticketOrderResponse, err
-		}
-	}
-
-	if createTicketOrder.ID > 0 && createTicketOrder.Status == "canceled" {
-		createNotification := models.Notification{
-			UserID:     userID,
-			TemplateID: 8,
-		}
-
-		_, err = u.notificationRepo.CreateNotification(createNotification)
-		if err != nil {
-			return ticketOrderResponse, err
-		}
-	}
-
 	getTicketTravelerDetail, err := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTicketOrderID(createTicketOrder.ID)
 	if err != nil {
 		return ticketOrderResponse, err
@@ -1835,10 +1254,11 @@ func (u *ticketOrderUsecase) UpdateTicketOrder(userID, ticketOrderID uint, statu
 		ticketTravelerDetailResponse := dtos.TicketTravelerDetailResponse{
 			TicketTravelerDetailID: int(getTravelerDetail.ID),
 			TravelerDetail: dtos.TravelerDetailResponse{
-				ID:           int(getTravelerDetail.ID),
-				Title:        getTravelerDetail.Title,
-				FullName:     getTravelerDetail.FullName,
-				IDCardNumber: *getTravelerDetail.IDCardNumber,
+				ID:            int(getTravelerDetail.ID),
+				Title:         getTravelerDetail.Title,
+				FullName:      getTravelerDetail.FullName,
+				IDCardNumber:  *getTravelerDetail.IDCardNumber,
+				PassengerType: getTravelerDetail.PassengerType,
 			},
 			Train: dtos.TrainResponsesSimply{
 				TrainID:         getTrain.ID,
@@ -1905,3 +1325,35 @@ func (u *ticketOrderUsecase) UpdateTicketOrder(userID, ticketOrderID uint, statu
 
 	return ticketOrderResponse, nil
 }
+
+// releaseTicketOrderSeats frees the seats of a canceled or refunded order so they can be sold again.
+func releaseTicketOrderSeats(ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, ticketOrder models.TicketOrder, ticketTravelerDetails []models.TicketTravelerDetail) {
+	_ = ticketTravelerDetailRepo.DeleteTrainSeatBookingsByTicketOrderID(ticketOrder.ID)
+	for _, ticketTravelerDetail := range ticketTravelerDetails {
+		serviceDate := ticketTravelerDetail.DateOfDeparture
+		if ticketTravelerDetail.ServiceDate != nil {
+			serviceDate = *ticketTravelerDetail.ServiceDate
+		}
+		_ = trainSeatHoldRepo.DeleteTrainSeatHoldsByUserIDAndTrainSeatID(ticketOrder.UserID, ticketTravelerDetail.TrainID, ticketTravelerDetail.TrainSeatID, helpers.FormatDateToYMD(&serviceDate))
+	}
+}
+
+// newTrainSeatBookings returns one booking for every hop the ticket rides between its origin and
+// destination stop. Tickets without a stored sequence claim nothing, the overlap check covers them.
+func newTrainSeatBookings(ticketTravelerDetail models.TicketTravelerDetail) []models.TrainSeatBooking {
+	var trainSeatBookings []models.TrainSeatBooking
+	if ticketTravelerDetail.ServiceDate == nil {
+		return trainSeatBookings
+	}
+	for sequence := ticketTravelerDetail.OriginSequence; sequence < ticketTravelerDetail.DestinationSequence; sequence++ {
+		trainSeatBookings = append(trainSeatBookings, models.TrainSeatBooking{
+			TicketOrderID:          ticketTravelerDetail.TicketOrderID,
+			TicketTravelerDetailID: ticketTravelerDetail.ID,
+			TrainID:                ticketTravelerDetail.TrainID,
+			TrainSeatID:            ticketTravelerDetail.TrainSeatID,
+			ServiceDate:            *ticketTravelerDetail.ServiceDate,
+			Sequence:               sequence,
+		})
+	}
+	return trainSeatBookings
+}
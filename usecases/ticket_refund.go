@@ -0,0 +1,527 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+)
+
+type TicketRefundUsecase interface {
+	// admin
+	GetRefundPolicies() ([]dtos.RefundPolicyResponse, error)
+	UpdateRefundPolicy(refundPolicyInput dtos.RefundPolicyInput) (dtos.RefundPolicyResponse, error)
+	DeleteRefundPolicy(id uint) error
+	GetTicketRefundsByAdmin(page, limit int, status string) ([]dtos.TicketRefundResponse, int, error)
+	ApproveTicketRefund(adminID, id uint, ticketRefundProcessInput dtos.TicketRefundProcessInput) (dtos.TicketRefundResponse, error)
+	RejectTicketRefund(adminID, id uint, ticketRefundProcessInput dtos.TicketRefundProcessInput) (dtos.TicketRefundResponse, error)
+
+	// user
+	QuoteTicketRefund(userID, ticketOrderID uint) (dtos.TicketRefundQuoteResponse, error)
+	RequestTicketRefund(userID uint, ticketRefundInput dtos.TicketRefundInput) (dtos.TicketRefundResponse, error)
+	GetTicketRefunds(page, limit int, userID uint, status string) ([]dtos.TicketRefundResponse, int, error)
+}
+
+type ticketRefundUsecase struct {
+	ticketRefundRepo         repositories.TicketRefundRepository
+	ticketOrderRepo          repositories.TicketOrderRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	trainCarriageRepo        repositories.TrainCarriageRepository
+	orderStateMachine        OrderStateMachine
+}
+
+func NewTicketRefundUsecase(ticketRefundRepo repositories.TicketRefundRepository, ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, trainCarriageRepo repositories.TrainCarriageRepository, orderStateMachine OrderStateMachine) TicketRefundUsecase {
+	return &ticketRefundUsecase{ticketRefundRepo, ticketOrderRepo, ticketTravelerDetailRepo, trainCarriageRepo, orderStateMachine}
+}
+
+// =============================== ADMIN ================================== \\
+
+// GetRefundPolicies godoc
+// @Summary      Get refund policies
+// @Description  Get refund policies per carriage class
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} dtos.GetAllRefundPolicyStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/refund-policy [get]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) GetRefundPolicies() ([]dtos.RefundPolicyResponse, error) {
+	refundPolicies, err := u.ticketRefundRepo.GetRefundPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	var refundPolicyResponses []dtos.RefundPolicyResponse
+	for _, refundPolicy := range refundPolicies {
+		refundPolicyResponse, err := u.newRefundPolicyResponse(refundPolicy)
+		if err != nil {
+			return refundPolicyResponses, err
+		}
+		refundPolicyResponses = append(refundPolicyResponses, refundPolicyResponse)
+	}
+	return refundPolicyResponses, nil
+}
+
+// UpdateRefundPolicy godoc
+// @Summary      Update refund policy
+// @Description  Create or replace the refund policy of a carriage class. Each rule refunds a percentage when cancelled at least the given hours before departure, a class that is not refundable never refunds.
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.RefundPolicyInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.RefundPolicyStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/refund-policy [put]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) UpdateRefundPolicy(refundPolicyInput dtos.RefundPolicyInput) (dtos.RefundPolicyResponse, error) {
+	var refundPolicyResponse dtos.RefundPolicyResponse
+
+	if refundPolicyInput.Class == "" {
+		return refundPolicyResponse, errors.New("Class is required")
+	}
+
+	hours := make(map[int]bool)
+	for _, rule := range refundPolicyInput.Rules {
+		if rule.MinHoursBefore < 0 {
+			return refundPolicyResponse, errors.New("Min hours before must not be negative")
+		}
+		if !isPercent(rule.RefundPercent) {
+			return refundPolicyResponse, errors.New("Refund percent must be between 0 and 100")
+		}
+		if hours[rule.MinHoursBefore] {
+			return refundPolicyResponse, errors.New("Min hours before must be unique")
+		}
+		hours[rule.MinHoursBefore] = true
+	}
+
+	refundPolicy, _ := u.ticketRefundRepo.GetRefundPolicyByClass(refundPolicyInput.Class)
+	refundPolicy.Class = refundPolicyInput.Class
+	refundPolicy.Refundable = refundPolicyInput.Refundable
+
+	refundPolicy, err := u.ticketRefundRepo.SaveRefundPolicy(refundPolicy)
+	if err != nil {
+		return refundPolicyResponse, err
+	}
+
+	var refundPolicyRules []models.RefundPolicyRule
+	for _, rule := range refundPolicyInput.Rules {
+		refundPolicyRules = append(refundPolicyRules, models.RefundPolicyRule{
+			RefundPolicyID: refundPolicy.ID,
+			MinHoursBefore: rule.MinHoursBefore,
+			RefundPercent:  rule.RefundPercent,
+		})
+	}
+
+	err = u.ticketRefundRepo.ReplaceRefundPolicyRules(refundPolicy.ID, refundPolicyRules)
+	if err != nil {
+		return refundPolicyResponse, err
+	}
+
+	return u.newRefundPolicyResponse(refundPolicy)
+}
+
+// DeleteRefundPolicy godoc
+// @Summary      Delete refund policy
+// @Description  Delete refund policy, the class falls back to a full refund before departure
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID refund policy"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/refund-policy/{id} [delete]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) DeleteRefundPolicy(id uint) error {
+	refundPolicy, err := u.ticketRefundRepo.GetRefundPolicyByID(id)
+	if err != nil {
+		return err
+	}
+	return u.ticketRefundRepo.DeleteRefundPolicy(refundPolicy)
+}
+
+// GetTicketRefundsByAdmin godoc
+// @Summary      Get ticket refunds
+// @Description  Get ticket refund requests
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected)
+// @Success      200 {object} dtos.GetAllTicketRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/ticket/refund [get]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) GetTicketRefundsByAdmin(page, limit int, status string) ([]dtos.TicketRefundResponse, int, error) {
+	return u.GetTicketRefunds(page, limit, 0, status)
+}
+
+// ApproveTicketRefund godoc
+// @Summary      Approve ticket refund
+// @Description  Approve a pending refund request, the order becomes refund and its seats are released. The credit of a reschedule is paid out and the order stays paid
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID ticket refund"
+// @Param        request body dtos.TicketRefundProcessInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.TicketRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/ticket/refund/{id}/approve [put]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) ApproveTicketRefund(adminID, id uint, ticketRefundProcessInput dtos.TicketRefundProcessInput) (dtos.TicketRefundResponse, error) {
+	var ticketRefundResponse dtos.TicketRefundResponse
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+
+	ticketRefundRepo := u.ticketRefundRepo.WithTx(tx)
+	ticketOrderRepo := u.ticketOrderRepo.WithTx(tx)
+	orderStateMachine := u.orderStateMachine.WithTx(tx)
+
+	ticketRefund, err := ticketRefundRepo.GetTicketRefundByIDForUpdate(id)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+	if ticketRefund.Status != "pending" {
+		return ticketRefundResponse, errors.New("Ticket refund has already been processed")
+	}
+
+	// Lock the order so an expiry or a cancel can not change it while the refund settles,
+	// and check its status only once the lock is held
+	ticketOrder, err := ticketOrderRepo.GetTicketOrderByIDForUpdate(ticketRefund.TicketOrderID)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+	if ticketOrder.Status != "paid" {
+		return ticketRefundResponse, errors.New("Only paid ticket orders can be refunded")
+	}
+
+	// The credit of a reschedule is paid out without touching the order, any other refund ends it and
+	// the state machine releases the seats and notifies the user
+	if ticketRefund.TicketRescheduleID == 0 {
+		_, err = orderStateMachine.TransitionTicketOrder(ticketOrder, "refund", orderActorAdmin, adminID, "Refund approved")
+		if err != nil {
+			return ticketRefundResponse, err
+		}
+	}
+
+	processedAt := time.Now()
+	ticketRefund.Status = "approved"
+	ticketRefund.ProcessedBy = adminID
+	ticketRefund.ProcessedAt = &processedAt
+	ticketRefund.Note = ticketRefundProcessInput.Note
+	ticketRefund, err = ticketRefundRepo.UpdateTicketRefund(ticketRefund)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	return newTicketRefundResponse(ticketRefund), nil
+}
+
+// RejectTicketRefund godoc
+// @Summary      Reject ticket refund
+// @Description  Reject a pending refund request, the order stays paid
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID ticket refund"
+// @Param        request body dtos.TicketRefundProcessInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.TicketRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/ticket/refund/{id}/reject [put]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) RejectTicketRefund(adminID, id uint, ticketRefundProcessInput dtos.TicketRefundProcessInput) (dtos.TicketRefundResponse, error) {
+	var ticketRefundResponse dtos.TicketRefundResponse
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketRefundRepo := u.ticketRefundRepo.WithTx(tx)
+
+	// Locked like an approval, so the two can not both process the same request
+	ticketRefund, err := ticketRefundRepo.GetTicketRefundByIDForUpdate(id)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+	if ticketRefund.Status != "pending" {
+		return ticketRefundResponse, errors.New("Ticket refund has already been processed")
+	}
+
+	processedAt := time.Now()
+	ticketRefund.Status = "rejected"
+	ticketRefund.ProcessedBy = adminID
+	ticketRefund.ProcessedAt = &processedAt
+	ticketRefund.Note = ticketRefundProcessInput.Note
+	ticketRefund, err = ticketRefundRepo.UpdateTicketRefund(ticketRefund)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	return newTicketRefundResponse(ticketRefund), nil
+}
+
+// =============================== ADMIN END ================================== \\
+
+// =============================== USER ================================== \\
+
+// QuoteTicketRefund godoc
+// @Summary      Quote ticket refund
+// @Description  Compute how much of a paid ticket order would be refunded now under the refund policy of each carriage class
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param ticket_order_id query int true "Ticket Order ID"
+// @Success      200 {object} dtos.TicketRefundQuoteStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/order/refund [get]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) QuoteTicketRefund(userID, ticketOrderID uint) (dtos.TicketRefundQuoteResponse, error) {
+	var ticketRefundQuoteResponse dtos.TicketRefundQuoteResponse
+
+	ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByID(ticketOrderID, userID)
+	if err != nil {
+		return ticketRefundQuoteResponse, err
+	}
+	if ticketOrder.Status != "paid" {
+		return ticketRefundQuoteResponse, errors.New("Only paid ticket orders can be refunded")
+	}
+
+	return u.quoteTicketRefund(ticketOrder, time.Now())
+}
+
+// RequestTicketRefund godoc
+// @Summary      Request ticket refund
+// @Description  Request a refund of a paid ticket order, the amount is fixed at request time and waits for admin approval
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TicketRefundInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TicketRefundCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/order/refund [post]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) RequestTicketRefund(userID uint, ticketRefundInput dtos.TicketRefundInput) (dtos.TicketRefundResponse, error) {
+	var ticketRefundResponse dtos.TicketRefundResponse
+
+	ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByID(ticketRefundInput.TicketOrderID, userID)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketRefundRepo := u.ticketRefundRepo.WithTx(tx)
+
+	// The order is locked so two requests sent at once can not both pass the pending check
+	ticketOrder, err = u.ticketOrderRepo.WithTx(tx).GetTicketOrderByIDForUpdate(ticketOrder.ID)
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+	if ticketOrder.Status != "paid" {
+		return ticketRefundResponse, errors.New("Only paid ticket orders can be refunded")
+	}
+
+	pendingTicketRefund, _ := ticketRefundRepo.GetPendingTicketRefundByTicketOrderID(ticketOrder.ID)
+	if pendingTicketRefund.ID > 0 {
+		return ticketRefundResponse, errors.New("Refund has already been requested")
+	}
+
+	ticketRefundQuote, err := u.quoteTicketRefund(ticketOrder, time.Now())
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+	if !ticketRefundQuote.Refundable {
+		return ticketRefundResponse, errors.New("Ticket order is not refundable")
+	}
+
+	ticketRefund, err := ticketRefundRepo.CreateTicketRefund(models.TicketRefund{
+		TicketOrderID: ticketOrder.ID,
+		UserID:        ticketOrder.UserID,
+		Amount:        ticketRefundQuote.RefundAmount,
+		Reason:        ticketRefundInput.Reason,
+		Status:        "pending",
+	})
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return ticketRefundResponse, err
+	}
+
+	return newTicketRefundResponse(ticketRefund), nil
+}
+
+// GetTicketRefunds godoc
+// @Summary      Get my ticket refunds
+// @Description  Get refund requests of the user
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected)
+// @Success      200 {object} dtos.GetAllTicketRefundStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/order/ticket/refund [get]
+// @Security BearerAuth
+func (u *ticketRefundUsecase) GetTicketRefunds(page, limit int, userID uint, status string) ([]dtos.TicketRefundResponse, int, error) {
+	ticketRefunds, count, err := u.ticketRefundRepo.GetTicketRefunds(page, limit, userID, strings.ToLower(status))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ticketRefundResponses []dtos.TicketRefundResponse
+	for _, ticketRefund := range ticketRefunds {
+		ticketRefundResponses = append(ticketRefundResponses, newTicketRefundResponse(ticketRefund))
+	}
+	return ticketRefundResponses, count, nil
+}
+
+// quoteTicketRefund prices the refund of every traveler of an order at the given time. A class without a
+// policy refunds in full until departure, a non-refundable class or a cancellation inside every rule refunds nothing.
+func (u *ticketRefundUsecase) quoteTicketRefund(ticketOrder models.TicketOrder, at time.Time) (dtos.TicketRefundQuoteResponse, error) {
+	ticketRefundQuoteResponse := dtos.TicketRefundQuoteResponse{
+		TicketOrderID: ticketOrder.ID,
+		TotalAmount:   ticketOrder.TotalAmount,
+	}
+
+	ticketTravelerDetails, err := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTicketOrderID(ticketOrder.ID)
+	if err != nil {
+		return ticketRefundQuoteResponse, err
+	}
+
+	for _, ticketTravelerDetail := range ticketTravelerDetails {
+		trainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID(ticketTravelerDetail.TrainCarriageID)
+		if err != nil {
+			return ticketRefundQuoteResponse, err
+		}
+
+		minute, err := helpers.FormatTimeToMinutes(ticketTravelerDetail.DepartureTime)
+		if err != nil {
+			return ticketRefundQuoteResponse, err
+		}
+		dateOfDeparture := ticketTravelerDetail.DateOfDeparture
+		departureAt := time.Date(dateOfDeparture.Year(), dateOfDeparture.Month(), dateOfDeparture.Day(), 0, minute, 0, 0, time.Local)
+		hoursBefore := int(departureAt.Sub(at).Hours())
+
+		refundPercent := 0
+		if departureAt.After(at) {
+			refundPercent = 100
+			refundPolicy, _ := u.ticketRefundRepo.GetRefundPolicyByClass(trainCarriage.Class)
+			if refundPolicy.ID > 0 {
+				refundPercent = 0
+				if refundPolicy.Refundable {
+					refundPolicyRule, _ := u.ticketRefundRepo.GetRefundPolicyRuleByRefundPolicyIDAndHours(refundPolicy.ID, hoursBefore)
+					if refundPolicyRule.ID > 0 {
+						refundPercent = refundPolicyRule.RefundPercent
+					}
+				}
+			}
+		}
+
+		refundAmount := ticketTravelerDetail.TrainPrice * refundPercent / 100
+		ticketRefundQuoteResponse.RefundAmount += refundAmount
+		ticketRefundQuoteResponse.Travelers = append(ticketRefundQuoteResponse.Travelers, dtos.TicketRefundTravelerResponse{
+			TicketTravelerDetailID: ticketTravelerDetail.ID,
+			Class:                  trainCarriage.Class,
+			TrainPrice:             ticketTravelerDetail.TrainPrice,
+			HoursBeforeDeparture:   hoursBefore,
+			RefundPercent:          refundPercent,
+			RefundAmount:           refundAmount,
+		})
+	}
+
+	ticketRefundQuoteResponse.Refundable = ticketRefundQuoteResponse.RefundAmount > 0
+	return ticketRefundQuoteResponse, nil
+}
+
+func (u *ticketRefundUsecase) newRefundPolicyResponse(refundPolicy models.RefundPolicy) (dtos.RefundPolicyResponse, error) {
+	refundPolicyResponse := dtos.RefundPolicyResponse{
+		RefundPolicyID: refundPolicy.ID,
+		Class:          refundPolicy.Class,
+		Refundable:     refundPolicy.Refundable,
+		Rules:          []dtos.RefundPolicyRuleResponse{},
+		CreatedAt:      refundPolicy.CreatedAt,
+		UpdatedAt:      refundPolicy.UpdatedAt,
+	}
+
+	refundPolicyRules, err := u.ticketRefundRepo.GetRefundPolicyRulesByRefundPolicyID(refundPolicy.ID)
+	if err != nil {
+		return refundPolicyResponse, err
+	}
+	for _, refundPolicyRule := range refundPolicyRules {
+		refundPolicyResponse.Rules = append(refundPolicyResponse.Rules, dtos.RefundPolicyRuleResponse{
+			MinHoursBefore: refundPolicyRule.MinHoursBefore,
+			RefundPercent:  refundPolicyRule.RefundPercent,
+		})
+	}
+	return refundPolicyResponse, nil
+}
+
+func newTicketRefundResponse(ticketRefund models.TicketRefund) dtos.TicketRefundResponse {
+	return dtos.TicketRefundResponse{
+		TicketRefundID:     ticketRefund.ID,
+		TicketOrderID:      ticketRefund.TicketOrderID,
+		UserID:             ticketRefund.UserID,
+		Amount:             ticketRefund.Amount,
+		Reason:             ticketRefund.Reason,
+		Status:             ticketRefund.Status,
+		ProcessedBy:        ticketRefund.ProcessedBy,
+		ProcessedAt:        ticketRefund.ProcessedAt,
+		Note:               ticketRefund.Note,
+		TicketRescheduleID: ticketRefund.TicketRescheduleID,
+		CreatedAt:          ticketRefund.CreatedAt,
+		UpdatedAt:          ticketRefund.UpdatedAt,
+	}
+}
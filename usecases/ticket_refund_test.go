@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeTicketRefundRepo serves the refund policies by class and their rules, the other methods are not used.
+type fakeTicketRefundRepo struct {
+	repositories.TicketRefundRepository
+	refundPolicies    map[string]models.RefundPolicy
+	refundPolicyRules []models.RefundPolicyRule
+}
+
+func (r fakeTicketRefundRepo) GetRefundPolicyByClass(class string) (models.RefundPolicy, error) {
+	refundPolicy, ok := r.refundPolicies[class]
+	if !ok {
+		return refundPolicy, errors.New("record not found")
+	}
+	return refundPolicy, nil
+}
+
+func (r fakeTicketRefundRepo) GetRefundPolicyRuleByRefundPolicyIDAndHours(refundPolicyID uint, hoursBefore int) (models.RefundPolicyRule, error) {
+	var best models.RefundPolicyRule
+	for _, refundPolicyRule := range r.refundPolicyRules {
+		if refundPolicyRule.RefundPolicyID == refundPolicyID && refundPolicyRule.MinHoursBefore <= hoursBefore && (best.ID == 0 || refundPolicyRule.MinHoursBefore > best.MinHoursBefore) {
+			best = refundPolicyRule
+		}
+	}
+	if best.ID == 0 {
+		return best, errors.New("record not found")
+	}
+	return best, nil
+}
+
+// fakeTicketTravelerDetailRepo serves the tickets of every order, the other methods are not used.
+type fakeTicketTravelerDetailRepo struct {
+	repositories.TicketTravelerDetailRepository
+	ticketTravelerDetails []models.TicketTravelerDetail
+}
+
+func (r fakeTicketTravelerDetailRepo) GetTicketTravelerDetailByTicketOrderID(id uint) ([]models.TicketTravelerDetail, error) {
+	return r.ticketTravelerDetails, nil
+}
+
+// fakeTrainCarriageRepo serves carriages by ID, the other methods are not used.
+type fakeTrainCarriageRepo struct {
+	repositories.TrainCarriageRepository
+	trainCarriages map[uint]models.TrainCarriage
+}
+
+func (r fakeTrainCarriageRepo) GetTrainCarriageByID(id uint) (models.TrainCarriage, error) {
+	trainCarriage, ok := r.trainCarriages[id]
+	if !ok {
+		return trainCarriage, errors.New("record not found")
+	}
+	return trainCarriage, nil
+}
+
+func TestQuoteTicketRefund(t *testing.T) {
+	dateOfDeparture := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	departureAt := dateOfDeparture.Add(8 * time.Hour)
+	trainCarriageRepo := fakeTrainCarriageRepo{trainCarriages: map[uint]models.TrainCarriage{
+		1: {Model: gorm.Model{ID: 1}, Class: "ekonomi"},
+		2: {Model: gorm.Model{ID: 2}, Class: "eksekutif"},
+	}}
+	ticketRefundRepo := fakeTicketRefundRepo{
+		refundPolicies: map[string]models.RefundPolicy{
+			"ekonomi": {Model: gorm.Model{ID: 1}, Class: "ekonomi", Refundable: true},
+			"promo":   {Model: gorm.Model{ID: 2}, Class: "promo", Refundable: false},
+		},
+		refundPolicyRules: []models.RefundPolicyRule{
+			{Model: gorm.Model{ID: 1}, RefundPolicyID: 1, MinHoursBefore: 72, RefundPercent: 75},
+			{Model: gorm.Model{ID: 2}, RefundPolicyID: 1, MinHoursBefore: 24, RefundPercent: 50},
+		},
+	}
+	ticket := func(id, trainCarriageID uint, trainPrice int) models.TicketTravelerDetail {
+		return models.TicketTravelerDetail{Model: gorm.Model{ID: id}, TrainCarriageID: trainCarriageID, TrainPrice: trainPrice, DepartureTime: "08:00", DateOfDeparture: dateOfDeparture}
+	}
+
+	tests := []struct {
+		name               string
+		trainCarriages     map[uint]models.TrainCarriage
+		tickets            []models.TicketTravelerDetail
+		at                 time.Time
+		wantRefundPercents []int
+		wantRefundAmount   int
+	}{
+		{
+			name:               "rule for the hours left before departure",
+			tickets:            []models.TicketTravelerDetail{ticket(1, 1, 100000)},
+			at:                 departureAt.Add(-48 * time.Hour),
+			wantRefundPercents: []int{50},
+			wantRefundAmount:   50000,
+		},
+		{
+			name:               "earliest rule",
+			tickets:            []models.TicketTravelerDetail{ticket(1, 1, 100000)},
+			at:                 departureAt.Add(-100 * time.Hour),
+			wantRefundPercents: []int{75},
+			wantRefundAmount:   75000,
+		},
+		{
+			name:               "inside every rule refunds nothing",
+			tickets:            []models.TicketTravelerDetail{ticket(1, 1, 100000)},
+			at:                 departureAt.Add(-2 * time.Hour),
+			wantRefundPercents: []int{0},
+			wantRefundAmount:   0,
+		},
+		{
+			name:               "class without a policy refunds in full",
+			tickets:            []models.TicketTravelerDetail{ticket(1, 2, 200000)},
+			at:                 departureAt.Add(-2 * time.Hour),
+			wantRefundPercents: []int{100},
+			wantRefundAmount:   200000,
+		},
+		{
+			name:               "departed train refunds nothing",
+			tickets:            []models.TicketTravelerDetail{ticket(1, 2, 200000)},
+			at:                 departureAt.Add(time.Minute),
+			wantRefundPercents: []int{0},
+			wantRefundAmount:   0,
+		},
+		{
+			name:               "non-refundable class",
+			trainCarriages:     map[uint]models.TrainCarriage{3: {Model: gorm.Model{ID: 3}, Class: "promo"}},
+			tickets:            []models.TicketTravelerDetail{ticket(1, 3, 50000)},
+			at:                 departureAt.Add(-100 * time.Hour),
+			wantRefundPercents: []int{0},
+			wantRefundAmount:   0,
+		},
+		{
+			name:               "every traveler is priced on their own class",
+			tickets:            []models.TicketTravelerDetail{ticket(1, 1, 100000), ticket(2, 2, 200000)},
+			at:                 departureAt.Add(-48 * time.Hour),
+			wantRefundPercents: []int{50, 100},
+			wantRefundAmount:   250000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carriageRepo := trainCarriageRepo
+			if tt.trainCarriages != nil {
+				carriageRepo = fakeTrainCarriageRepo{trainCarriages: tt.trainCarriages}
+			}
+			u := &ticketRefundUsecase{
+				ticketRefundRepo:         ticketRefundRepo,
+				ticketTravelerDetailRepo: fakeTicketTravelerDetailRepo{ticketTravelerDetails: tt.tickets},
+				trainCarriageRepo:        carriageRepo,
+			}
+
+			got, err := u.quoteTicketRefund(models.TicketOrder{Model: gorm.Model{ID: 1}, TotalAmount: 300000}, tt.at)
+			if err != nil {
+				t.Fatalf("quoteTicketRefund() error = %v", err)
+			}
+
+			var refundPercents []int
+			for _, traveler := range got.Travelers {
+				refundPercents = append(refundPercents, traveler.RefundPercent)
+			}
+			if !reflect.DeepEqual(refundPercents, tt.wantRefundPercents) {
+				t.Errorf("refund percents = %v, want %v", refundPercents, tt.wantRefundPercents)
+			}
+			if got.RefundAmount != tt.wantRefundAmount {
+				t.Errorf("refund amount = %d, want %d", got.RefundAmount, tt.wantRefundAmount)
+			}
+			if got.Refundable != (tt.wantRefundAmount > 0) {
+				t.Errorf("refundable = %v, want %v", got.Refundable, tt.wantRefundAmount > 0)
+			}
+		})
+	}
+}
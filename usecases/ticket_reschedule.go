@@ -0,0 +1,641 @@
+package usecases
+
+import (
+	"back-end-golang/configs"
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TicketRescheduleUsecase interface {
+	QuoteTicketReschedule(userID uint, ticketRescheduleInput dtos.TicketRescheduleInput) (dtos.TicketRescheduleQuoteResponse, error)
+	CreateTicketReschedule(userID uint, ticketRescheduleInput dtos.TicketRescheduleInput) (dtos.TicketRescheduleResponse, error)
+	UpdateTicketReschedule(userID, ticketRescheduleID uint, status string) (dtos.TicketRescheduleResponse, error)
+	GetTicketReschedules(page, limit int, userID uint, status string) ([]dtos.TicketRescheduleResponse, int, error)
+
+	// admin
+	GetTicketReschedulesByAdmin(page, limit int, status string) ([]dtos.TicketRescheduleResponse, int, error)
+	ConfirmTicketReschedule(ticketRescheduleID uint) (dtos.TicketRescheduleResponse, error)
+}
+
+type ticketRescheduleUsecase struct {
+	ticketRescheduleRepo     repositories.TicketRescheduleRepository
+	ticketOrderRepo          repositories.TicketOrderRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	travelerDetailRepo       repositories.TravelerDetailRepository
+	trainCarriageRepo        repositories.TrainCarriageRepository
+	trainRepo                repositories.TrainRepository
+	trainSeatRepo            repositories.TrainSeatRepository
+	trainStationRepo         repositories.TrainStationRepository
+	trainScheduleRepo        repositories.TrainScheduleRepository
+	trainSeatHoldRepo        repositories.TrainSeatHoldRepository
+	trainFareRepo            repositories.TrainFareRepository
+	paymentRepo              repositories.PaymentRepository
+	ticketRefundRepo         repositories.TicketRefundRepository
+}
+
+func NewTicketRescheduleUsecase(ticketRescheduleRepo repositories.TicketRescheduleRepository, ticketOrderRepo repositories.TicketOrderRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, travelerDetailRepo repositories.TravelerDetailRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainRepo repositories.TrainRepository, trainSeatRepo repositories.TrainSeatRepository, trainStationRepo repositories.TrainStationRepository, trainScheduleRepo repositories.TrainScheduleRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, trainFareRepo repositories.TrainFareRepository, paymentRepo repositories.PaymentRepository, ticketRefundRepo repositories.TicketRefundRepository) TicketRescheduleUsecase {
+	return &ticketRescheduleUsecase{ticketRescheduleRepo, ticketOrderRepo, ticketTravelerDetailRepo, travelerDetailRepo, trainCarriageRepo, trainRepo, trainSeatRepo, trainStationRepo, trainScheduleRepo, trainSeatHoldRepo, trainFareRepo, paymentRepo, ticketRefundRepo}
+}
+
+// ticketRescheduleTarget is a checked move of one ticket to a new train, seat and date.
+type ticketRescheduleTarget struct {
+	ticketOrder             models.TicketOrder
+	ticketTravelerDetail    models.TicketTravelerDetail
+	trainCarriage           models.TrainCarriage
+	trainSeat               models.TrainSeat
+	trainStationOrigin      models.TrainStation
+	trainStationDestination models.TrainStation
+	dateOfDeparture         time.Time
+	serviceDate             time.Time
+	trainPrice              int
+}
+
+// QuoteTicketReschedule godoc
+// @Summary      Quote ticket reschedule
+// @Description  Price the move of a paid ticket to another train, seat or date against its current fare
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TicketRescheduleInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.TicketRescheduleQuoteStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/order/reschedule/quote [post]
+// @Security BearerAuth
+func (u *ticketRescheduleUsecase) QuoteTicketReschedule(userID uint, ticketRescheduleInput dtos.TicketRescheduleInput) (dtos.TicketRescheduleQuoteResponse, error) {
+	var ticketRescheduleQuoteResponse dtos.TicketRescheduleQuoteResponse
+
+	target, err := u.newTicketRescheduleTarget(userID, ticketRescheduleInput, time.Now())
+	if err != nil {
+		return ticketRescheduleQuoteResponse, err
+	}
+
+	fareDifference := target.trainPrice - target.ticketTravelerDetail.TrainPrice
+	ticketRescheduleQuoteResponse = dtos.TicketRescheduleQuoteResponse{
+		TicketTravelerDetailID: target.ticketTravelerDetail.ID,
+		TrainID:                target.trainCarriage.TrainID,
+		TrainCarriageID:        target.trainCarriage.ID,
+		TrainSeatID:            target.trainSeat.ID,
+		Date:                   helpers.FormatDateToYMD(&target.dateOfDeparture),
+		OldTrainPrice:          target.ticketTravelerDetail.TrainPrice,
+		NewTrainPrice:          target.trainPrice,
+		FareDifference:         fareDifference,
+	}
+	if fareDifference > 0 {
+		ticketRescheduleQuoteResponse.ExtraPayment = fareDifference
+	} else {
+		ticketRescheduleQuoteResponse.Credit = -fareDifference
+	}
+	return ticketRescheduleQuoteResponse, nil
+}
+
+// CreateTicketReschedule godoc
+// @Summary      Reschedule ticket
+// @Description  Move a paid ticket to another train, seat or date. A cheaper or equal fare is applied at once and the difference is issued as credit paid out through a pending refund, a higher fare holds the new seat until an admin confirms the extra payment
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TicketRescheduleInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TicketRescheduleCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/order/reschedule [post]
+// @Security BearerAuth
+func (u *ticketRescheduleUsecase) CreateTicketReschedule(userID uint, ticketRescheduleInput dtos.TicketRescheduleInput) (dtos.TicketRescheduleResponse, error) {
+	var ticketRescheduleResponse dtos.TicketRescheduleResponse
+
+	now := time.Now()
+	target, err := u.newTicketRescheduleTarget(userID, ticketRescheduleInput, now)
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	unpaidTicketReschedule, _ := u.ticketRescheduleRepo.GetUnpaidTicketRescheduleByTicketTravelerDetailID(target.ticketTravelerDetail.ID)
+	if unpaidTicketReschedule.ID > 0 {
+		return ticketRescheduleResponse, errors.New("Ticket already has a reschedule waiting for payment")
+	}
+
+	fareDifference := target.trainPrice - target.ticketTravelerDetail.TrainPrice
+	if fareDifference > 0 {
+		getPayment, err := u.paymentRepo.GetPaymentByID2(uint(ticketRescheduleInput.PaymentID))
+		if err != nil || getPayment.ID < 1 {
+			return ticketRescheduleResponse, errors.New("failed to get payment id")
+		}
+	}
+
+	createTicketReschedule := models.TicketReschedule{
+		TicketOrderID:          target.ticketOrder.ID,
+		TicketTravelerDetailID: target.ticketTravelerDetail.ID,
+		UserID:                 target.ticketOrder.UserID,
+		OldTrainID:             target.ticketTravelerDetail.TrainID,
+		OldTrainCarriageID:     target.ticketTravelerDetail.TrainCarriageID,
+		OldTrainSeatID:         target.ticketTravelerDetail.TrainSeatID,
+		OldDateOfDeparture:     target.ticketTravelerDetail.DateOfDeparture,
+		OldTrainPrice:          target.ticketTravelerDetail.TrainPrice,
+		OldBoardingTicketCode:  target.ticketTravelerDetail.BoardingTicketCode,
+		NewTrainID:             target.trainCarriage.TrainID,
+		NewTrainCarriageID:     target.trainCarriage.ID,
+		NewTrainSeatID:         target.trainSeat.ID,
+		NewDateOfDeparture:     target.dateOfDeparture,
+		NewServiceDate:         target.serviceDate,
+		NewTrainPrice:          target.trainPrice,
+		FareDifference:         fareDifference,
+		PaymentID:              ticketRescheduleInput.PaymentID,
+		Status:                 "unpaid",
+	}
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketRescheduleRepo := u.ticketRescheduleRepo.WithTx(tx)
+
+	if fareDifference > 0 {
+		// The new seat is held for the user until an admin confirms the extra payment, which takes longer
+		// than the seat hold of a booking
+		expiredAt := now.Add(time.Duration(configs.EnvReschedulePaymentMinutes()) * time.Minute)
+		createTicketReschedule.ExpiredAt = &expiredAt
+
+		trainSeatHoldRepo := u.trainSeatHoldRepo.WithTx(tx)
+		err = trainSeatHoldRepo.DeleteTrainSeatHoldsByUserIDAndTrainSeatID(target.ticketOrder.UserID, target.trainCarriage.TrainID, target.trainSeat.ID, helpers.FormatDateToYMD(&target.serviceDate))
+		if err != nil {
+			return ticketRescheduleResponse, err
+		}
+		_, err = trainSeatHoldRepo.CreateTrainSeatHold(models.TrainSeatHold{
+			UserID:               target.ticketOrder.UserID,
+			TrainID:              target.trainCarriage.TrainID,
+			TrainCarriageID:      target.trainCarriage.ID,
+			TrainSeatID:          target.trainSeat.ID,
+			StationOriginID:      target.trainStationOrigin.StationID,
+			StationDestinationID: target.trainStationDestination.StationID,
+			OriginSequence:       target.trainStationOrigin.Sequence,
+			DestinationSequence:  target.trainStationDestination.Sequence,
+			DateOfDeparture:      target.dateOfDeparture,
+			ServiceDate:          target.serviceDate,
+			ExpiredAt:            expiredAt,
+		})
+		if err != nil {
+			return ticketRescheduleResponse, err
+		}
+	} else {
+		createTicketReschedule, err = u.applyTicketReschedule(tx, createTicketReschedule, target)
+		if err != nil {
+			return ticketRescheduleResponse, err
+		}
+	}
+
+	createTicketReschedule, err = ticketRescheduleRepo.CreateTicketReschedule(createTicketReschedule)
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	// The credit of a fare drop is paid out like a refund once an admin approves it
+	if createTicketReschedule.Status == "credited" {
+		_, err = u.ticketRefundRepo.WithTx(tx).CreateTicketRefund(models.TicketRefund{
+			TicketOrderID:      createTicketReschedule.TicketOrderID,
+			UserID:             createTicketReschedule.UserID,
+			Amount:             -createTicketReschedule.FareDifference,
+			Reason:             "Reschedule to a cheaper fare",
+			Status:             "pending",
+			TicketRescheduleID: createTicketReschedule.ID,
+		})
+		if err != nil {
+			return ticketRescheduleResponse, err
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	return newTicketRescheduleResponse(createTicketReschedule), nil
+}
+
+// UpdateTicketReschedule godoc
+// @Summary      Cancel ticket reschedule
+// @Description  Cancel a reschedule waiting for its extra payment to keep the current ticket. The extra payment itself is confirmed by an admin
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param ticket_reschedule_id query int true "Ticket reschedule ID"
+// @Param status query string true "Update status" Enums(canceled)
+// @Success      200 {object} dtos.TicketRescheduleStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/order/reschedule [patch]
+// @Security BearerAuth
+func (u *ticketRescheduleUsecase) UpdateTicketReschedule(userID, ticketRescheduleID uint, status string) (dtos.TicketRescheduleResponse, error) {
+	var ticketRescheduleResponse dtos.TicketRescheduleResponse
+
+	if strings.ToLower(status) != "canceled" {
+		return ticketRescheduleResponse, errors.New("Status must be canceled, the extra payment is confirmed by an admin")
+	}
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketRescheduleRepo := u.ticketRescheduleRepo.WithTx(tx)
+
+	ticketReschedule, err := ticketRescheduleRepo.GetTicketRescheduleByIDForUpdate(ticketRescheduleID)
+	if err != nil || ticketReschedule.UserID != userID {
+		return ticketRescheduleResponse, errors.New("Failed to get ticket reschedule")
+	}
+	if ticketReschedule.Status != "unpaid" {
+		return ticketRescheduleResponse, errors.New("Ticket reschedule has already been processed")
+	}
+
+	ticketReschedule, err = u.cancelTicketReschedule(tx, ticketReschedule)
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	return newTicketRescheduleResponse(ticketReschedule), nil
+}
+
+// GetTicketReschedules godoc
+// @Summary      Get my ticket reschedules
+// @Description  Get reschedules of the user
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param status query string false "Filter by status" Enums(unpaid, paid, credited, canceled)
+// @Success      200 {object} dtos.GetAllTicketRescheduleStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/order/ticket/reschedule [get]
+// @Security BearerAuth
+func (u *ticketRescheduleUsecase) GetTicketReschedules(page, limit int, userID uint, status string) ([]dtos.TicketRescheduleResponse, int, error) {
+	ticketReschedules, count, err := u.ticketRescheduleRepo.GetTicketReschedules(page, limit, userID, strings.ToLower(status))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ticketRescheduleResponses []dtos.TicketRescheduleResponse
+	for _, ticketReschedule := range ticketReschedules {
+		ticketRescheduleResponses = append(ticketRescheduleResponses, newTicketRescheduleResponse(ticketReschedule))
+	}
+	return ticketRescheduleResponses, count, nil
+}
+
+// GetTicketReschedulesByAdmin godoc
+// @Summary      Get ticket reschedules
+// @Description  Get ticket reschedules of every user
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param status query string false "Filter by status" Enums(unpaid, paid, credited, canceled)
+// @Success      200 {object} dtos.GetAllTicketRescheduleStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/ticket/reschedule [get]
+// @Security BearerAuth
+func (u *ticketRescheduleUsecase) GetTicketReschedulesByAdmin(page, limit int, status string) ([]dtos.TicketRescheduleResponse, int, error) {
+	return u.GetTicketReschedules(page, limit, 0, status)
+}
+
+// ConfirmTicketReschedule godoc
+// @Summary      Confirm ticket reschedule payment
+// @Description  Confirm the extra payment of a reschedule, the ticket moves to the new train, seat and date at the quoted fare
+// @Tags         Admin - Order
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID ticket reschedule"
+// @Success      200 {object} dtos.TicketRescheduleStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/order/ticket/reschedule/{id}/paid [put]
+// @Security BearerAuth
+func (u *ticketRescheduleUsecase) ConfirmTicketReschedule(ticketRescheduleID uint) (dtos.TicketRescheduleResponse, error) {
+	var ticketRescheduleResponse dtos.TicketRescheduleResponse
+
+	tx := u.ticketOrderRepo.BeginTransaction()
+	defer tx.Rollback()
+	ticketRescheduleRepo := u.ticketRescheduleRepo.WithTx(tx)
+
+	ticketReschedule, err := ticketRescheduleRepo.GetTicketRescheduleByIDForUpdate(ticketRescheduleID)
+	if err != nil {
+		return ticketRescheduleResponse, errors.New("Failed to get ticket reschedule")
+	}
+	if ticketReschedule.Status != "unpaid" {
+		return ticketRescheduleResponse, errors.New("Ticket reschedule has already been processed")
+	}
+
+	// A payment confirmed after the seat hold ran out does not move the ticket
+	if ticketReschedule.ExpiredAt != nil && time.Now().After(*ticketReschedule.ExpiredAt) {
+		_, err = u.cancelTicketReschedule(tx, ticketReschedule)
+		if err != nil {
+			return ticketRescheduleResponse, err
+		}
+		err = tx.Commit().Error
+		if err != nil {
+			return ticketRescheduleResponse, err
+		}
+		return ticketRescheduleResponse, errors.New("Reschedule payment window has expired")
+	}
+
+	// Availability is checked again, the agreed fare stays as it was quoted
+	target, err := u.newTicketRescheduleTarget(ticketReschedule.UserID, dtos.TicketRescheduleInput{
+		TicketTravelerDetailID: ticketReschedule.TicketTravelerDetailID,
+		TrainCarriageID:        ticketReschedule.NewTrainCarriageID,
+		TrainSeatID:            ticketReschedule.NewTrainSeatID,
+		Date:                   helpers.FormatDateToYMD(&ticketReschedule.NewDateOfDeparture),
+	}, time.Now())
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+	target.trainPrice = ticketReschedule.NewTrainPrice
+
+	ticketReschedule, err = u.applyTicketReschedule(tx, ticketReschedule, target)
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	ticketReschedule, err = ticketRescheduleRepo.UpdateTicketReschedule(ticketReschedule)
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return ticketRescheduleResponse, err
+	}
+
+	return newTicketRescheduleResponse(ticketReschedule), nil
+}
+
+// cancelTicketReschedule releases the seat held for an unpaid reschedule, the ticket stays as it is.
+func (u *ticketRescheduleUsecase) cancelTicketReschedule(tx *gorm.DB, ticketReschedule models.TicketReschedule) (models.TicketReschedule, error) {
+	_ = u.trainSeatHoldRepo.WithTx(tx).DeleteTrainSeatHoldsByUserIDAndTrainSeatID(ticketReschedule.UserID, ticketReschedule.NewTrainID, ticketReschedule.NewTrainSeatID, helpers.FormatDateToYMD(&ticketReschedule.NewServiceDate))
+
+	ticketReschedule.Status = "canceled"
+	return u.ticketRescheduleRepo.WithTx(tx).UpdateTicketReschedule(ticketReschedule)
+}
+
+// newTicketRescheduleTarget checks that the ticket may be moved and that the new train stops at the same
+// stations on the new date with the seat free, then prices the ticket with the fare engine.
+func (u *ticketRescheduleUsecase) newTicketRescheduleTarget(userID uint, ticketRescheduleInput dtos.TicketRescheduleInput, now time.Time) (ticketRescheduleTarget, error) {
+	var target ticketRescheduleTarget
+
+	if ticketRescheduleInput.TicketTravelerDetailID < 1 || ticketRescheduleInput.TrainCarriageID < 1 || ticketRescheduleInput.TrainSeatID < 1 || ticketRescheduleInput.Date == "" {
+		return target, errors.New("Failed to reschedule ticket")
+	}
+
+	ticketTravelerDetail, err := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByID(ticketRescheduleInput.TicketTravelerDetailID)
+	if err != nil {
+		return target, errors.New("Failed to get ticket traveler detail")
+	}
+	ticketOrder, err := u.ticketOrderRepo.GetTicketOrderByID(ticketTravelerDetail.TicketOrderID, userID)
+	if err != nil {
+		return target, errors.New("Failed to get ticket order")
+	}
+	if ticketOrder.Status != "paid" {
+		return target, errors.New("Only paid ticket orders can be rescheduled")
+	}
+	if ticketTravelerDetail.BoardedAt != nil {
+		return target, errors.New("Ticket has already boarded")
+	}
+
+	travelerDetail, err := u.travelerDetailRepo.GetTravelerDetailByID(ticketTravelerDetail.TravelerDetailID)
+	if err != nil {
+		return target, err
+	}
+	passengerType := travelerDetail.PassengerType
+	if passengerType == "" {
+		passengerType = passengerTypeAdult
+	}
+	if passengerType == passengerTypeInfant {
+		return target, errors.New("Infant tickets follow the adult they travel with and cannot be rescheduled")
+	}
+
+	minute, err := helpers.FormatTimeToMinutes(ticketTravelerDetail.DepartureTime)
+	if err != nil {
+		return target, err
+	}
+	dateOfDeparture := ticketTravelerDetail.DateOfDeparture
+	departureAt := time.Date(dateOfDeparture.Year(), dateOfDeparture.Month(), dateOfDeparture.Day(), 0, minute, 0, 0, time.Local)
+	if !departureAt.After(now) {
+		return target, errors.New("Ticket has already departed")
+	}
+
+	if now.Format("2006-01-02") > ticketRescheduleInput.Date {
+		return target, errors.New("Departure date must not be in the past")
+	}
+	dateDepartureParse, err := helpers.FormatStringToDate(ticketRescheduleInput.Date)
+	if err != nil {
+		return target, errors.New("Failed to parsing date")
+	}
+
+	getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(ticketRescheduleInput.TrainCarriageID)
+	if err != nil {
+		return target, errors.New("Failed to get train carriage id")
+	}
+	getTrain, err := u.trainRepo.GetTrainByID2(getTrainCarriage.TrainID)
+	if err != nil || getTrain.Status != "available" {
+		return target, errors.New("Failed to get train")
+	}
+
+	// The ticket keeps its stations, only the train, seat and date change
+	getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, ticketTravelerDetail.StationOriginID, ticketTravelerDetail.StationDestinationID)
+	if err != nil {
+		return target, errors.New("Failed to get train station")
+	}
+	if !isForwardRoute(getTrainStation, ticketTravelerDetail.StationOriginID, ticketTravelerDetail.StationDestinationID) {
+		return target, errors.New("Train does not travel from station origin to station destination")
+	}
+	trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, ticketTravelerDetail.StationOriginID)
+	if err != nil {
+		return target, err
+	}
+	trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, ticketTravelerDetail.StationDestinationID)
+	if err != nil {
+		return target, err
+	}
+
+	getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(ticketRescheduleInput.TrainSeatID)
+	if err != nil {
+		return target, errors.New("Failed to get train seat id")
+	}
+	if !isTrainSeatInCarriage(getTrainSeat, getTrainCarriage) {
+		return target, errors.New("Train seat is not available in this train carriage")
+	}
+
+	serviceDate := trainServiceDate(dateDepartureParse, trainStationOrigin)
+	if !isTrainRunning(u.trainScheduleRepo, getTrain.ID, serviceDate) {
+		return target, errors.New("Train does not run on this date")
+	}
+
+	currentServiceDate := ticketTravelerDetail.DateOfDeparture
+	if ticketTravelerDetail.ServiceDate != nil {
+		currentServiceDate = *ticketTravelerDetail.ServiceDate
+	}
+	if getTrain.ID == ticketTravelerDetail.TrainID && getTrainSeat.ID == ticketTravelerDetail.TrainSeatID && helpers.FormatDateToYMD(&serviceDate) == helpers.FormatDateToYMD(&currentServiceDate) {
+		return target, errors.New("Ticket is already on this train, seat and date")
+	}
+
+	// The ticket being moved does not block its own seat
+	trainOrder, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatIDSegment(getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+	if trainOrder.ID > 0 && trainOrder.ID != ticketTravelerDetail.ID {
+		return target, errors.New("Train seat is not available")
+	}
+	trainSeatHold, _ := u.trainSeatHoldRepo.GetTrainSeatHoldBySegment(ticketOrder.UserID, getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+	if trainSeatHold.ID > 0 {
+		return target, errors.New("Train seat is held by another user")
+	}
+
+	target = ticketRescheduleTarget{
+		ticketOrder:             ticketOrder,
+		ticketTravelerDetail:    ticketTravelerDetail,
+		trainCarriage:           getTrainCarriage,
+		trainSeat:               getTrainSeat,
+		trainStationOrigin:      trainStationOrigin,
+		trainStationDestination: trainStationDestination,
+		dateOfDeparture:         dateDepartureParse,
+		serviceDate:             serviceDate,
+		trainPrice:              quoteTrainFare(u.trainFareRepo, getTrainCarriage, trainStationOrigin, trainStationDestination, serviceDate, now, passengerType).Total,
+	}
+	return target, nil
+}
+
+// applyTicketReschedule moves the ticket inside the transaction: the seat hops of the old ticket are
+// released, the new ones claimed and a new boarding ticket code replaces the old one. Infants riding with
+// the ticket move to the same seat and keep their fare. A fare drop is issued as credit.
+func (u *ticketRescheduleUsecase) applyTicketReschedule(tx *gorm.DB, ticketReschedule models.TicketReschedule, target ticketRescheduleTarget) (models.TicketReschedule, error) {
+	ticketOrderRepo := u.ticketOrderRepo.WithTx(tx)
+	ticketTravelerDetailRepo := u.ticketTravelerDetailRepo.WithTx(tx)
+	trainSeatHoldRepo := u.trainSeatHoldRepo.WithTx(tx)
+
+	ticketTravelerDetail, err := ticketTravelerDetailRepo.GetTicketTravelerDetailByIDForUpdate(target.ticketTravelerDetail.ID)
+	if err != nil {
+		return ticketReschedule, err
+	}
+	if ticketTravelerDetail.BoardingTicketCode != ticketReschedule.OldBoardingTicketCode {
+		return ticketReschedule, errors.New("Ticket has changed since the reschedule was requested")
+	}
+
+	err = ticketTravelerDetailRepo.DeleteTrainSeatBookingsByTicketTravelerDetailID(ticketTravelerDetail.ID)
+	if err != nil {
+		return ticketReschedule, err
+	}
+
+	fareDifference := target.trainPrice - ticketTravelerDetail.TrainPrice
+	ticketTravelerDetail.TrainID = target.trainCarriage.TrainID
+	ticketTravelerDetail.TrainPrice = target.trainPrice
+	ticketTravelerDetail.TrainCarriageID = target.trainCarriage.ID
+	ticketTravelerDetail.TrainSeatID = target.trainSeat.ID
+	ticketTravelerDetail.DepartureTime = target.trainStationOrigin.ArriveTime
+	ticketTravelerDetail.ArrivalTime = target.trainStationDestination.ArriveTime
+	ticketTravelerDetail.OriginSequence = target.trainStationOrigin.Sequence
+	ticketTravelerDetail.DestinationSequence = target.trainStationDestination.Sequence
+	ticketTravelerDetail.DateOfDeparture = target.dateOfDeparture
+	ticketTravelerDetail.ServiceDate = &target.serviceDate
+	ticketTravelerDetail.BoardingTicketCode = "boarding-ticket-" + uuid.New().String()
+	ticketTravelerDetail, err = ticketTravelerDetailRepo.UpdateTicketTravelerDetail(ticketTravelerDetail)
+	if err != nil {
+		return ticketReschedule, err
+	}
+
+	err = ticketTravelerDetailRepo.CreateTrainSeatBookings(newTrainSeatBookings(ticketTravelerDetail))
+	if err != nil {
+		return ticketReschedule, errors.New("Train seat is not available")
+	}
+
+	infantTicketTravelerDetails, err := ticketTravelerDetailRepo.GetTicketTravelerDetailsByAccompaniedByID(ticketTravelerDetail.ID)
+	if err != nil {
+		return ticketReschedule, err
+	}
+	for _, infantTicketTravelerDetail := range infantTicketTravelerDetails {
+		infantTicketTravelerDetail.TrainID = ticketTravelerDetail.TrainID
+		infantTicketTravelerDetail.TrainCarriageID = ticketTravelerDetail.TrainCarriageID
+		infantTicketTravelerDetail.TrainSeatID = ticketTravelerDetail.TrainSeatID
+		infantTicketTravelerDetail.DepartureTime = ticketTravelerDetail.DepartureTime
+		infantTicketTravelerDetail.ArrivalTime = ticketTravelerDetail.ArrivalTime
+		infantTicketTravelerDetail.OriginSequence = ticketTravelerDetail.OriginSequence
+		infantTicketTravelerDetail.DestinationSequence = ticketTravelerDetail.DestinationSequence
+		infantTicketTravelerDetail.DateOfDeparture = ticketTravelerDetail.DateOfDeparture
+		infantTicketTravelerDetail.ServiceDate = ticketTravelerDetail.ServiceDate
+		infantTicketTravelerDetail.BoardingTicketCode = "boarding-ticket-" + uuid.New().String()
+		_, err = ticketTravelerDetailRepo.UpdateTicketTravelerDetail(infantTicketTravelerDetail)
+		if err != nil {
+			return ticketReschedule, err
+		}
+	}
+	_ = trainSeatHoldRepo.DeleteTrainSeatHoldsByUserIDAndTrainSeatID(target.ticketOrder.UserID, ticketTravelerDetail.TrainID, ticketTravelerDetail.TrainSeatID, helpers.FormatDateToYMD(&target.serviceDate))
+
+	// The order total only grows by what was collected, a fare drop is paid back through its refund
+	ticketOrder := target.ticketOrder
+	ticketOrder.Price += fareDifference
+	if fareDifference > 0 {
+		ticketOrder.TotalAmount += fareDifference
+	}
+	_, err = ticketOrderRepo.UpdateTicketOrder(ticketOrder)
+	if err != nil {
+		return ticketReschedule, err
+	}
+
+	ticketReschedule.NewBoardingTicketCode = ticketTravelerDetail.BoardingTicketCode
+	ticketReschedule.ExpiredAt = nil
+	ticketReschedule.Status = "paid"
+	if fareDifference < 0 {
+		ticketReschedule.Status = "credited"
+	}
+	return ticketReschedule, nil
+}
+
+func newTicketRescheduleResponse(ticketReschedule models.TicketReschedule) dtos.TicketRescheduleResponse {
+	return dtos.TicketRescheduleResponse{
+		TicketRescheduleID:     ticketReschedule.ID,
+		TicketOrderID:          ticketReschedule.TicketOrderID,
+		TicketTravelerDetailID: ticketReschedule.TicketTravelerDetailID,
+		OldTrainID:             ticketReschedule.OldTrainID,
+		OldTrainCarriageID:     ticketReschedule.OldTrainCarriageID,
+		OldTrainSeatID:         ticketReschedule.OldTrainSeatID,
+		OldDate:                ticketReschedule.OldDateOfDeparture,
+		OldTrainPrice:          ticketReschedule.OldTrainPrice,
+		OldBoardingTicketCode:  ticketReschedule.OldBoardingTicketCode,
+		NewTrainID:             ticketReschedule.NewTrainID,
+		NewTrainCarriageID:     ticketReschedule.NewTrainCarriageID,
+		NewTrainSeatID:         ticketReschedule.NewTrainSeatID,
+		NewDate:                ticketReschedule.NewDateOfDeparture,
+		NewTrainPrice:          ticketReschedule.NewTrainPrice,
+		NewBoardingTicketCode:  ticketReschedule.NewBoardingTicketCode,
+		FareDifference:         ticketReschedule.FareDifference,
+		PaymentID:              ticketReschedule.PaymentID,
+		Status:                 ticketReschedule.Status,
+		ExpiredAt:              ticketReschedule.ExpiredAt,
+		CreatedAt:              ticketReschedule.CreatedAt,
+		UpdatedAt:              ticketReschedule.UpdatedAt,
+	}
+}
@@ -2,11 +2,13 @@ package usecases
 
 import (
 	"back-end-golang/dtos"
+	"back-end-golang/helpers"
 	"back-end-golang/models"
 	"back-end-golang/repositories"
 	"errors"
 	"sort"
 	"strings"
+	"time"
 )
 
 type TrainUsecase interface {
@@ -19,17 +21,21 @@ type TrainUsecase interface {
 	DeleteTrain(id uint) error
 
 	// user
-	SearchTrainAvailable(userId uint, page, limit, stationOrigin, stationDestination, sortByTrainId int, sortClassName, sortByPrice, sortByArriveTime string) ([]dtos.TrainResponse, int, error)
+	SearchTrainAvailable(userId uint, page, limit, stationOrigin, stationDestination, sortByTrainId int, originCity, destinationCity, date, sortClassName, sortByPrice, sortByArriveTime string) ([]dtos.TrainResponse, int, error)
+	SearchTrainJourney(userId uint, page, limit, stationOrigin, stationDestination, maxTransfer, minConnectionTime int, date, sortClassName string) ([]dtos.TrainJourneyResponse, int, error)
 }
 
 type trainUsecase struct {
 	trainRepo                 repositories.TrainRepository
 	trainStationRepo          repositories.TrainStationRepository
 	historySeenStationUsecase HistorySeenStationUsecase
+	trainScheduleRepo         repositories.TrainScheduleRepository
+	trainFareRepo             repositories.TrainFareRepository
+	stationRepo               repositories.StationRepository
 }
 
-func NewTrainUsecase(TrainRepo repositories.TrainRepository, TrainStationRepo repositories.TrainStationRepository, historySeenStationUsecase HistorySeenStationUsecase) TrainUsecase {
-	return &trainUsecase{TrainRepo, TrainStationRepo, historySeenStationUsecase}
+func NewTrainUsecase(TrainRepo repositories.TrainRepository, TrainStationRepo repositories.TrainStationRepository, historySeenStationUsecase HistorySeenStationUsecase, trainScheduleRepo repositories.TrainScheduleRepository, trainFareRepo repositories.TrainFareRepository, stationRepo repositories.StationRepository) TrainUsecase {
+	return &trainUsecase{TrainRepo, TrainStationRepo, historySeenStationUsecase, trainScheduleRepo, trainFareRepo, stationRepo}
 }
 
 // =============================== ADMIN ================================== \\
@@ -88,6 +94,9 @@ func (u *trainUsecase) GetAllTrains(page, limit int) ([]dtos.TrainResponses, int
 					Initial: getStation.Initial,
 				},
 				ArriveTime: train.ArriveTime,
+				Sequence:   train.Sequence,
+				DayOffset:  train.DayOffset,
+				DistanceKm: train.DistanceKm,
 			}
 
 			trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -194,6 +203,9 @@ func (u *trainUsecase) GetAllTrainsByAdmin(page, limit int, search, sortBy, filt
 					Initial: getStation.Initial,
 				},
 				ArriveTime: train.ArriveTime,
+				Sequence:   train.Sequence,
+				DayOffset:  train.DayOffset,
+				DistanceKm: train.DistanceKm,
 			}
 
 			trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -282,6 +294,9 @@ func (u *trainUsecase) GetTrainByID(id uint) (dtos.TrainResponses, error) {
 				Initial: getStation.Initial,
 			},
 			ArriveTime: train.ArriveTime,
+			Sequence:   train.Sequence,
+			DayOffset:  train.DayOffset,
+			DistanceKm: train.DistanceKm,
 		}
 
 		trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -330,12 +345,25 @@ func (u *trainUsecase) CreateTrain(train *dtos.TrainInput) (dtos.TrainResponses,
 		return trainResponse, errors.New("Route must be at least 2 station")
 	}
 
+	if hasDuplicateRouteStation(train.Route) {
+		return trainResponse, errors.New("Route must not visit the same station twice")
+	}
+
+	if !isIncreasingRouteDistance(train.Route) {
+		return trainResponse, errors.New("Route distance must not decrease along the route")
+	}
+
+	dayOffsets, err := routeDayOffsets(train.Route)
+	if err != nil {
+		return trainResponse, err
+	}
+
 	createdTrain, err := u.trainRepo.CreateTrain(createTrain)
 	if err != nil {
 		return trainResponse, err
 	}
 
-	for _, train := range train.Route {
+	for i, train := range train.Route {
 		if train.ArriveTime == "" || train.StationID < 1 {
 			return trainResponse, errors.New("Failed to create train")
 		}
@@ -348,6 +376,9 @@ func (u *trainUsecase) CreateTrain(train *dtos.TrainInput) (dtos.TrainResponses,
 			TrainID:    createdTrain.ID,
 			StationID:  station.ID,
 			ArriveTime: train.ArriveTime,
+			Sequence:   i + 1,
+			DayOffset:  dayOffsets[i],
+			DistanceKm: train.DistanceKm,
 		}
 		_, err = u.trainStationRepo.CreateTrainStation(trainStation)
 		if err != nil {
@@ -379,6 +410,9 @@ func (u *trainUsecase) CreateTrain(train *dtos.TrainInput) (dtos.TrainResponses,
 				Initial: getStation.Initial,
 			},
 			ArriveTime: train.ArriveTime,
+			Sequence:   train.Sequence,
+			DayOffset:  train.DayOffset,
+			DistanceKm: train.DistanceKm,
 		}
 		trainStationResponses = append(trainStationResponses, trainStationResponse)
 	}
@@ -430,6 +464,19 @@ func (u *trainUsecase) UpdateTrain(id uint, train dtos.TrainInput) (dtos.TrainRe
 	trains.Name = train.Name
 	trains.Status = train.Status
 
+	if hasDuplicateRouteStation(train.Route) {
+		return trainResponse, errors.New("Route must not visit the same station twice")
+	}
+
+	if !isIncreasingRouteDistance(train.Route) {
+		return trainResponse, errors.New("Route distance must not decrease along the route")
+	}
+
+	dayOffsets, err := routeDayOffsets(train.Route)
+	if err != nil {
+		return trainResponse, err
+	}
+
 	createdTrain, err := u.trainRepo.UpdateTrain(trains)
 	if err != nil {
 		return trainResponse, err
@@ -442,7 +489,7 @@ func (u *trainUsecase) UpdateTrain(id uint, train dtos.TrainInput) (dtos.TrainRe
 		return trainResponse, err
 	}
 
-	for _, train := range train.Route {
+	for i, train := range train.Route {
 		station, err := u.trainRepo.GetStationByID2(train.StationID)
 		if err != nil {
 			return trainResponse, err
@@ -451,6 +498,9 @@ func (u *trainUsecase) UpdateTrain(id uint, train dtos.TrainInput) (dtos.TrainRe
 			TrainID:    createdTrain.ID,
 			StationID:  station.ID,
 			ArriveTime: train.ArriveTime,
+			Sequence:   i + 1,
+			DayOffset:  dayOffsets[i],
+			DistanceKm: train.DistanceKm,
 		}
 		_, err = u.trainStationRepo.CreateTrainStation(trainStation)
 		if err != nil {
@@ -482,6 +532,9 @@ func (u *trainUsecase) UpdateTrain(id uint, train dtos.TrainInput) (dtos.TrainRe
 				Initial: getStation.Initial,
 			},
 			ArriveTime: train.ArriveTime,
+			Sequence:   train.Sequence,
+			DayOffset:  train.DayOffset,
+			DistanceKm: train.DistanceKm,
 		}
 		trainStationResponses = append(trainStationResponses, trainStationResponse)
 	}
@@ -533,8 +586,11 @@ func (u *trainUsecase) DeleteTrain(id uint) error {
 // @Produce      json
 // @Param page query int false "Page number"
 // @Param limit query int false "Number of items per page"
-// @Param station_origin_id query int true "Station origin id"
-// @Param station_destination_id query int true "Station destination id"
+// @Param station_origin_id query int false "Station origin id"
+// @Param station_destination_id query int false "Station destination id"
+// @Param origin_city query string false "Origin city, used when station origin id is empty"
+// @Param destination_city query string false "Destination city, used when station destination id is empty"
+// @Param date query string false "Travel date (2006-01-02)"
 // @Param sort_by_train_id query int false "Filter by train id"
 // @Param sort_by_class query string false "Filter by class name" Enums(Ekonomi, Bisnis, Eksekutif)
 // @Param sort_by_price query string false "Filter by price" Enums(asc, desc)
@@ -547,128 +603,215 @@ func (u *trainUsecase) DeleteTrain(id uint) error {
 // @Failure      500 {object} dtos.InternalServerErrorResponse
 // @Router       /user/train/search [get]
 // @Security BearerAuth
-func (u *trainUsecase) SearchTrainAvailable(userId uint, page, limit, stationOriginId, stationDestinationId, sortByTrainId int, sortClassName, sortByPrice, sortByArriveTime string) ([]dtos.TrainResponse, int, error) {
-	trains, err := u.trainRepo.GetAllTrains(sortClassName, sortByTrainId)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	var trainResponses []dtos.TrainResponse
-
-	for _, train := range trains {
-		getTrain, err := u.trainRepo.GetTrainByID(train.TrainID)
+func (u *trainUsecase) SearchTrainAvailable(userId uint, page, limit, stationOriginId, stationDestinationId, sortByTrainId int, originCity, destinationCity, date, sortClassName, sortByPrice, sortByArriveTime string) ([]dtos.TrainResponse, int, error) {
+	var travelDate time.Time
+	if date != "" {
+		dateParse, err := helpers.FormatStringToDate(date)
 		if err != nil {
-			return trainResponses, 0, err
-		}
-
-		getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, uint(stationOriginId), uint(stationDestinationId))
-		if err != nil {
-			return trainResponses, 0, err
+			return nil, 0, errors.New("Failed to parse date")
 		}
+		travelDate = dateParse
+	}
 
-		if getTrain.Status != "available" {
-			continue
-		}
+	// A city expands to every station in it, an explicit station id wins
+	stationOriginIds, err := u.searchStationIDs(stationOriginId, originCity)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(stationOriginIds) == 0 {
+		return nil, 0, errors.New("Origin city not found")
+	}
+	stationDestinationIds, err := u.searchStationIDs(stationDestinationId, destinationCity)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(stationDestinationIds) == 0 {
+		return nil, 0, errors.New("Destination city not found")
+	}
 
-		// Check if route[0] matches stationOriginId and route[1] matches stationDestinationId
-		if len(getTrainStation) < 2 || getTrainStation[0].StationID != uint(stationOriginId) || getTrainStation[1].StationID != uint(stationDestinationId) {
-			continue
-		}
+	now := time.Now()
+	trainSearchFilter := repositories.TrainSearchFilter{
+		StationOriginIDs:      stationOriginIds,
+		StationDestinationIDs: stationDestinationIds,
+		TrainID:               uint(sortByTrainId),
+		Class:                 sortClassName,
+		PurchaseDate:          now.Format("2006-01-02"),
+		SortByPrice:           sortByPrice,
+		SortByArriveTime:      sortByArriveTime,
+	}
+	if date != "" {
+		trainSearchFilter.TravelDate = helpers.FormatDateToYMD(&travelDate)
+	}
 
-		// Check if class has already been added or doesn't match the filterClass
-		if getTrain.ID != uint(sortByTrainId) && sortByTrainId != 0 {
-			continue
-		}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	trains, count, err := u.trainRepo.SearchTrainAvailablePaginated(trainSearchFilter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		// Check if class name matches the desired class filter
-		if strings.ToLower(sortClassName) != "" && strings.ToLower(train.Class) != strings.ToLower(sortClassName) {
-			continue
-		}
-		var trainStationResponses []dtos.TrainStationResponse
+	var trainResponses []dtos.TrainResponse
 
-		for _, trainStation := range getTrainStation {
-			getStation, err := u.trainRepo.GetStationByID2(trainStation.StationID)
+	for _, train := range trains {
+		trainStationOrigin := models.TrainStation{
+			TrainID:    train.TrainID,
+			StationID:  train.OriginStationID,
+			ArriveTime: train.OriginArriveTime,
+			Sequence:   train.OriginSequence,
+			DayOffset:  train.OriginDayOffset,
+			DistanceKm: train.OriginDistanceKm,
+		}
+		trainStationDestination := models.TrainStation{
+			TrainID:    train.TrainID,
+			StationID:  train.DestinationStationID,
+			ArriveTime: train.DestinationArriveTime,
+			Sequence:   train.DestinationSequence,
+			DayOffset:  train.DestinationDayOffset,
+			DistanceKm: train.DestinationDistanceKm,
+		}
+
+		// Resolve real timestamps, the query already dropped trains not running that day
+		serviceDate := time.Date(train.ServiceDate.Year(), train.ServiceDate.Month(), train.ServiceDate.Day(), 0, 0, 0, 0, time.Local)
+		var departureAt, arrivalAt *time.Time
+		if date != "" {
+			departureTime, err := trainStationDateTime(serviceDate, trainStationOrigin)
+			if err != nil {
+				return trainResponses, 0, err
+			}
+			arrivalTime, err := trainStationDateTime(serviceDate, trainStationDestination)
 			if err != nil {
 				return trainResponses, 0, err
 			}
+			departureAt, arrivalAt = &departureTime, &arrivalTime
+		}
 
-			trainStationResponse := dtos.TrainStationResponse{
-				StationID: trainStation.StationID,
+		trainStationResponses := []dtos.TrainStationResponse{
+			{
+				StationID: train.OriginStationID,
 				Station: dtos.StationInput{
-					Origin:  getStation.Origin,
-					Name:    getStation.Name,
-					Initial: getStation.Initial,
+					Origin:  train.OriginOrigin,
+					Name:    train.OriginName,
+					Initial: train.OriginInitial,
 				},
-				ArriveTime: trainStation.ArriveTime,
-			}
-
-			trainStationResponses = append(trainStationResponses, trainStationResponse)
+				ArriveTime: train.OriginArriveTime,
+				Sequence:   train.OriginSequence,
+				DayOffset:  train.OriginDayOffset,
+				DistanceKm: train.OriginDistanceKm,
+			},
+			{
+				StationID: train.DestinationStationID,
+				Station: dtos.StationInput{
+					Origin:  train.DestinationOrigin,
+					Name:    train.DestinationName,
+					Initial: train.DestinationInitial,
+				},
+				ArriveTime: train.DestinationArriveTime,
+				Sequence:   train.DestinationSequence,
+				DayOffset:  train.DestinationDayOffset,
+				DistanceKm: train.DestinationDistanceKm,
+			},
 		}
 
-		// getTrainCarriage, err := u.trainRepo.GetTrainCarriageByTrainID(train.)
+		// The query prices the adult fare the same way the order is charged, the price it sorted by is the one shown
+		trainFareQuoteResponse := newTrainFareQuoteResponse(trainFareQuote{
+			BaseFare:        train.BaseFare,
+			PeakSurcharge:   train.PeakSurcharge,
+			AdvanceDiscount: train.AdvanceDiscount,
+			Total:           train.Price,
+		})
 
 		trainResponse := dtos.TrainResponse{
-			TrainID:         getTrain.ID,
-			CodeTrain:       getTrain.CodeTrain,
-			Name:            getTrain.Name,
+			TrainID:         train.TrainID,
+			CodeTrain:       train.CodeTrain,
+			Name:            train.Name,
 			Class:           train.Class,
 			Price:           train.Price,
+			Fare:            &trainFareQuoteResponse,
 			Route:           trainStationResponses,
-			TrainCarriageID: train.ID,
-			Status:          getTrain.Status,
-			CreatedAt:       getTrain.CreatedAt,
-			UpdatedAt:       getTrain.UpdatedAt,
+			TrainCarriageID: train.TrainCarriageID,
+			DepartureAt:     departureAt,
+			ArrivalAt:       arrivalAt,
+			Status:          train.Status,
+			CreatedAt:       train.CreatedAt,
+			UpdatedAt:       train.UpdatedAt,
 		}
 		trainResponses = append(trainResponses, trainResponse)
+	}
 
-		// Sort trainResponses based on price
-		if strings.ToLower(sortByPrice) == "asc" {
-			sort.SliceStable(trainResponses, func(i, j int) bool {
-				return trainResponses[i].Price < trainResponses[j].Price
-			})
-		} else if strings.ToLower(sortByPrice) == "desc" {
-			sort.SliceStable(trainResponses, func(i, j int) bool {
-				return trainResponses[i].Price > trainResponses[j].Price
-			})
+	// History keeps station pairs, so only a search by station is recorded
+	if stationOriginId != 0 && stationDestinationId != 0 {
+		historySeenStationInput := dtos.HistorySeenStationInput{
+			StationOriginID:      uint(stationOriginId),
+			StationDestinationID: uint(stationDestinationId),
 		}
 
-		// Sort trainResponses based on arrive time
-		if strings.ToLower(sortByArriveTime) == "asc" {
-			sort.SliceStable(trainResponses, func(i, j int) bool {
-				return trainResponses[i].Route[0].ArriveTime < trainResponses[j].Route[0].ArriveTime
-			})
-		} else if strings.ToLower(sortByArriveTime) == "desc" {
-			sort.SliceStable(trainResponses, func(i, j int) bool {
-				return trainResponses[i].Route[0].ArriveTime > trainResponses[j].Route[0].ArriveTime
-			})
+		_, err = u.historySeenStationUsecase.CreateHistorySeenStation(userId, historySeenStationInput)
+		if err != nil {
+			return trainResponses, 0, err
 		}
 	}
 
-	historySeenStationInput := dtos.HistorySeenStationInput{
-		StationOriginID:      uint(stationOriginId),
-		StationDestinationID: uint(stationDestinationId),
+	return trainResponses, count, nil
+}
+
+// searchStationIDs resolves one side of a train search to station ids, either
+// the given station or every station in the given city.
+func (u *trainUsecase) searchStationIDs(stationId int, city string) ([]uint, error) {
+	if stationId != 0 || strings.TrimSpace(city) == "" {
+		return []uint{uint(stationId)}, nil
 	}
 
-	_, err = u.historySeenStationUsecase.CreateHistorySeenStation(userId, historySeenStationInput)
+	stations, err := u.stationRepo.GetStationsByOrigin(city)
 	if err != nil {
-		return trainResponses, 0, err
+		return nil, err
 	}
 
-	// Apply offset and limit to trainResponses
-	start := (page - 1) * limit
-	end := start + limit
-
-	// Ensure that `start` is within the range of trainResponses
-	if start >= len(trainResponses) {
-		return nil, 0, nil
+	var stationIds []uint
+	for _, station := range stations {
+		stationIds = append(stationIds, station.ID)
 	}
+	return stationIds, nil
+}
 
-	// Ensure that `end` does not exceed the length of trainResponses
-	if end > len(trainResponses) {
-		end = len(trainResponses)
+// isIncreasingRouteDistance reports whether the kilometre marks of a route never go
+// back, distance based fares are the difference between two marks.
+func isIncreasingRouteDistance(route []dtos.TrainStationInput) bool {
+	for i := 1; i < len(route); i++ {
+		if route[i].DistanceKm < route[i-1].DistanceKm {
+			return false
+		}
 	}
+	return true
+}
 
-	subsetTrainResponses := trainResponses[start:end]
+// hasDuplicateRouteStation reports whether a route stops at the same station
+// more than once, which would make the travel direction ambiguous.
+func hasDuplicateRouteStation(route []dtos.TrainStationInput) bool {
+	visitedStations := make(map[uint]bool)
+	for _, trainStation := range route {
+		if visitedStations[trainStation.StationID] {
+			return true
+		}
+		visitedStations[trainStation.StationID] = true
+	}
+	return false
+}
 
-	return subsetTrainResponses, len(trainResponses), nil
+// isForwardRoute reports whether the train stops at the origin station before
+// the destination station, based on the stop sequence.
+func isForwardRoute(trainStations []models.TrainStation, originID, destinationID uint) bool {
+	originSequence, destinationSequence := 0, 0
+	for _, trainStation := range trainStations {
+		if trainStation.StationID == originID {
+			originSequence = trainStation.Sequence
+		}
+		if trainStation.StationID == destinationID {
+			destinationSequence = trainStation.Sequence
+		}
+	}
+	return originSequence > 0 && destinationSequence > 0 && originSequence < destinationSequence
 }
@@ -2,14 +2,16 @@ package usecases
 
 import (
 	"back-end-golang/dtos"
+	"back-end-golang/helpers"
 	"back-end-golang/models"
 	"back-end-golang/repositories"
 	"errors"
 	"strings"
+	"time"
 )
 
 type TrainCarriageUsecase interface {
-	GetAllTrainCarriages(trainId, page, limit int, class, date, status string) ([]dtos.TrainCarriageSeatResponses, int, error)
+	GetAllTrainCarriages(trainId, stationOriginId, stationDestinationId, page, limit int, class, date, status string) ([]dtos.TrainCarriageSeatResponses, int, error)
 	GetTrainCarriageByID(id uint) (dtos.TrainCarriageResponse, error)
 	CreateTrainCarriage(trainCarriage []dtos.TrainCarriageInput) ([]dtos.TrainCarriageResponse, error)
 	UpdateTrainCarriage(id uint, trainCarriageInput dtos.TrainCarriageInput) (dtos.TrainCarriageResponse, error)
@@ -20,10 +22,13 @@ type trainCarriageUsecase struct {
 	trainCarriageRepo        repositories.TrainCarriageRepository
 	trainRepo                repositories.TrainRepository
 	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	seatLayoutRepo           repositories.SeatLayoutRepository
+	trainSeatHoldRepo        repositories.TrainSeatHoldRepository
+	trainFareRepo            repositories.TrainFareRepository
 }
 
-func NewTrainCarriageUsecase(TrainCarriageRepo repositories.TrainCarriageRepository, TrainRepo repositories.TrainRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository) TrainCarriageUsecase {
-	return &trainCarriageUsecase{TrainCarriageRepo, TrainRepo, ticketTravelerDetailRepo}
+func NewTrainCarriageUsecase(TrainCarriageRepo repositories.TrainCarriageRepository, TrainRepo repositories.TrainRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, seatLayoutRepo repositories.SeatLayoutRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, trainFareRepo repositories.TrainFareRepository) TrainCarriageUsecase {
+	return &trainCarriageUsecase{TrainCarriageRepo, TrainRepo, ticketTravelerDetailRepo, seatLayoutRepo, trainSeatHoldRepo, trainFareRepo}
 }
 
 // GetAllTrainCarriages godoc
@@ -35,6 +40,8 @@ func NewTrainCarriageUsecase(TrainCarriageRepo repositories.TrainCarriageReposit
 // @Param train_id query int false "Train id"
 // @Param class query string false "Class train" Enums(Ekonomi, Bisnis, Eksekutif)
 // @Param date query string false "Date order"
+// @Param station_origin_id query int false "Station origin id"
+// @Param station_destination_id query int false "Station destination id"
 // @Param status query string false "Status train" Enums(available, unavailable)
 // @Param page query int false "Page number"
 // @Param limit query int false "Number of items per page"
@@ -45,7 +52,7 @@ func NewTrainCarriageUsecase(TrainCarriageRepo repositories.TrainCarriageReposit
 // @Failure      404 {object} dtos.NotFoundResponse
 // @Failure      500 {object} dtos.InternalServerErrorResponse
 // @Router       /public/train-carriage [get]
-func (u *trainCarriageUsecase) GetAllTrainCarriages(trainId, page, limit int, class, date, status string) ([]dtos.TrainCarriageSeatResponses, int, error) {
+func (u *trainCarriageUsecase) GetAllTrainCarriages(trainId, stationOriginId, stationDestinationId, page, limit int, class, date, status string) ([]dtos.TrainCarriageSeatResponses, int, error) {
 	trainCarriages, count, err := u.trainCarriageRepo.GetAllTrainCarriages(page, limit)
 	if err != nil {
 		return nil, 0, err
@@ -75,36 +82,87 @@ func (u *trainCarriageUsecase) GetAllTrainCarriages(trainId, page, limit int, cl
 		if status != "" && strings.ToLower(status) != strings.ToLower(train.Status) {
 			continue
 		}
-		trainSeat, err := u.trainCarriageRepo.GetTrainSeatsByClass(trainCarriage.Class)
+		trainSeat, err := u.getTrainSeats(trainCarriage)
 		if err != nil {
 			return trainCarriageResponses, 0, err
 		}
 
+		getTrainStation, err := u.trainRepo.GetTrainStationByTrainID(train.ID)
+		if err != nil {
+			return trainCarriageResponses, count, err
+		}
+		if len(getTrainStation) < 2 {
+			continue
+		}
+
+		// Seats are occupied per segment, defaulting to the whole route
+		trainStationOrigin := getTrainStation[0]
+		trainStationDestination := getTrainStation[len(getTrainStation)-1]
+		if stationOriginId > 0 && stationDestinationId > 0 && isForwardRoute(getTrainStation, uint(stationOriginId), uint(stationDestinationId)) {
+			for _, trainStation := range getTrainStation {
+				if trainStation.StationID == uint(stationOriginId) {
+					trainStationOrigin = trainStation
+				}
+				if trainStation.StationID == uint(stationDestinationId) {
+					trainStationDestination = trainStation
+				}
+			}
+		}
+
+		// The date is the travel date at the origin, tickets are kept per service date
+		serviceDate := date
+		quoteDate, _ := helpers.FormatStringToDate(time.Now().Format("2006-01-02"))
+		if dateParse, err := helpers.FormatStringToDate(date); err == nil {
+			dateParse = trainServiceDate(dateParse, trainStationOrigin)
+			serviceDate = helpers.FormatDateToYMD(&dateParse)
+			quoteDate = dateParse
+		}
+
+		// Quote the adult fare of the segment with the same engine the order is charged with
+		trainFareQuote := quoteTrainFare(u.trainFareRepo, trainCarriage, trainStationOrigin, trainStationDestination, quoteDate, time.Now(), passengerTypeAdult)
+		trainFareQuoteResponse := newTrainFareQuoteResponse(trainFareQuote)
+
+		var seatMapResponse dtos.SeatMapResponse
+		if trainCarriage.SeatLayoutID > 0 {
+			seatLayout, _ := u.seatLayoutRepo.GetSeatLayoutByID(trainCarriage.SeatLayoutID)
+			seatMapResponse.AisleAfterColumn = seatLayout.AisleAfterColumn
+		}
+
 		var trainSeatResponses []dtos.TrainSeatAvailableResponse
 		for _, trainSeat := range trainSeat {
-			isAvailable := true
-			if date != "" && trainId != 0 {
-				trainOrder, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatID(trainCarriage.TrainID, trainSeat.ID, date)
-				if trainOrder.ID > 0 {
+			row, column := trainSeat.Row, trainSeat.Column
+			if trainCarriage.SeatLayoutID == 0 {
+				row, column = parseTrainSeatName(trainSeat.Name)
+			}
+			if row > seatMapResponse.Rows {
+				seatMapResponse.Rows = row
+			}
+			if column > seatMapResponse.Columns {
+				seatMapResponse.Columns = column
+			}
+
+			isAvailable := !trainSeat.IsDisabled
+			if isAvailable && date != "" && trainId != 0 {
+				trainOrder, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatIDSegment(trainCarriage.TrainID, trainSeat.ID, serviceDate, trainStationOrigin.Sequence, trainStationDestination.Sequence)
+				trainSeatHold, _ := u.trainSeatHoldRepo.GetTrainSeatHoldBySegment(0, trainCarriage.TrainID, trainSeat.ID, serviceDate, trainStationOrigin.Sequence, trainStationDestination.Sequence)
+				if trainOrder.ID > 0 || trainSeatHold.ID > 0 {
 					isAvailable = false
 				}
 			}
 
 			trainSeatRespon := dtos.TrainSeatAvailableResponse{
-				ID:        int(trainSeat.ID),
-				Name:      trainSeat.Name,
-				Available: isAvailable,
+				ID:         int(trainSeat.ID),
+				Name:       trainSeat.Name,
+				Row:        row,
+				Column:     column,
+				IsWindow:   trainSeat.IsWindow,
+				IsAisle:    trainSeat.IsAisle,
+				IsDisabled: trainSeat.IsDisabled,
+				Available:  isAvailable,
 			}
 			trainSeatResponses = append(trainSeatResponses, trainSeatRespon)
 		}
 
-		getTrainStation, err := u.trainRepo.GetTrainStationByTrainID(train.ID)
-		if err != nil {
-			return trainCarriageResponses, count, err
-		}
-		if len(getTrainStation) < 2 {
-			continue
-		}
 		for _, train := range getTrainStation {
 			getStation, err := u.trainRepo.GetStationByID(train.StationID)
 			if err != nil {
@@ -127,6 +185,9 @@ func (u *trainCarriageUsecase) GetAllTrainCarriages(trainId, page, limit int, cl
 					Initial: getStation.Initial,
 				},
 				ArriveTime: train.ArriveTime,
+				Sequence:   train.Sequence,
+				DayOffset:  train.DayOffset,
+				DistanceKm: train.DistanceKm,
 			}
 
 			trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -139,11 +200,13 @@ func (u *trainCarriageUsecase) GetAllTrainCarriages(trainId, page, limit int, cl
 				CodeTrain: train.CodeTrain,
 				Name:      train.Name,
 				Class:     trainCarriage.Class,
-				Price:     trainCarriage.Price,
+				Price:     trainFareQuote.Total,
+				Fare:      &trainFareQuoteResponse,
 				Route:     trainStationResponses,
 				Status:    train.Status,
 			},
 			Name:      trainCarriage.Name,
+			SeatMap:   seatMapResponse,
 			Seat:      trainSeatResponses,
 			CreatedAt: trainCarriage.CreatedAt,
 			UpdatedAt: trainCarriage.UpdatedAt,
@@ -179,7 +242,7 @@ func (u *trainCarriageUsecase) GetTrainCarriageByID(id uint) (dtos.TrainCarriage
 		return trainCarriageResponses, err
 	}
 
-	trainSeat, err := u.trainCarriageRepo.GetTrainSeatsByClass(trainCarriage.Class)
+	trainSeat, err := u.getTrainSeats(trainCarriage)
 	if err != nil {
 		return trainCarriageResponses, err
 	}
@@ -223,6 +286,9 @@ func (u *trainCarriageUsecase) GetTrainCarriageByID(id uint) (dtos.TrainCarriage
 				Initial: getStation.Initial,
 			},
 			ArriveTime: train.ArriveTime,
+			Sequence:   train.Sequence,
+			DayOffset:  train.DayOffset,
+			DistanceKm: train.DistanceKm,
 		}
 
 		trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -268,11 +334,21 @@ func (u *trainCarriageUsecase) CreateTrainCarriage(trainCarriages []dtos.TrainCa
 		if trainCarriageInput.TrainID < 1 || trainCarriageInput.Name == "" || trainCarriageInput.Class == "" || trainCarriageInput.Price < 1 {
 			return trainCarriageResponses, errors.New("Failed to create train carriage")
 		}
+		var seatLayout models.SeatLayout
+		if trainCarriageInput.SeatLayoutID > 0 {
+			getSeatLayout, err := u.getSeatLayout(trainCarriageInput.SeatLayoutID, trainCarriageInput.Class)
+			if err != nil {
+				return trainCarriageResponses, err
+			}
+			seatLayout = getSeatLayout
+		}
+
 		createTrainCarriage := models.TrainCarriage{
-			TrainID: trainCarriageInput.TrainID,
-			Class:   trainCarriageInput.Class,
-			Name:    trainCarriageInput.Name,
-			Price:   trainCarriageInput.Price,
+			TrainID:      trainCarriageInput.TrainID,
+			Class:        trainCarriageInput.Class,
+			Name:         trainCarriageInput.Name,
+			Price:        trainCarriageInput.Price,
+			SeatLayoutID: seatLayout.ID,
 		}
 
 		createdTrainCarriage, err := u.trainCarriageRepo.CreateTrainCarriage(createTrainCarriage)
@@ -280,12 +356,19 @@ func (u *trainCarriageUsecase) CreateTrainCarriage(trainCarriages []dtos.TrainCa
 			return trainCarriageResponses, err
 		}
 
+		if seatLayout.ID > 0 {
+			err = generateTrainSeats(u.trainCarriageRepo, seatLayout, createdTrainCarriage)
+			if err != nil {
+				return trainCarriageResponses, err
+			}
+		}
+
 		train, err := u.trainCarriageRepo.GetTrainByID2(createdTrainCarriage.TrainID)
 		if err != nil {
 			return trainCarriageResponses, err
 		}
 
-		trainSeats, err := u.trainCarriageRepo.GetTrainSeatsByClass(trainCarriageInput.Class)
+		trainSeats, err := u.getTrainSeats(createdTrainCarriage)
 		if err != nil {
 			return trainCarriageResponses, err
 		}
@@ -319,6 +402,9 @@ func (u *trainCarriageUsecase) CreateTrainCarriage(trainCarriages []dtos.TrainCa
 					Initial: getStation.Initial,
 				},
 				ArriveTime: train.ArriveTime,
+				Sequence:   train.Sequence,
+				DayOffset:  train.DayOffset,
+				DistanceKm: train.DistanceKm,
 			}
 
 			trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -377,13 +463,52 @@ func (u *trainCarriageUsecase) UpdateTrainCarriage(id uint, trainCarriageInput d
 		return trainCarriageResponse, err
 	}
 
+	var seatLayout models.SeatLayout
+	if trainCarriageInput.SeatLayoutID > 0 {
+		seatLayout, err = u.getSeatLayout(trainCarriageInput.SeatLayoutID, trainCarriageInput.Class)
+		if err != nil {
+			return trainCarriageResponse, err
+		}
+	}
+
+	// Seats that were already sold must keep their position
+	isSeatLayoutChanged := trainCarriage.SeatLayoutID != seatLayout.ID || (seatLayout.ID == 0 && trainCarriage.Class != trainCarriageInput.Class)
+	if isSeatLayoutChanged {
+		ticketTravelerDetail, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainCarriageID(trainCarriage.ID)
+		if ticketTravelerDetail.ID > 0 {
+			return trainCarriageResponse, errors.New("Seat layout of a train carriage with orders can not be changed")
+		}
+	}
+
 	trainCarriage.TrainID = trainCarriageInput.TrainID
 	trainCarriage.Class = trainCarriageInput.Class
 	trainCarriage.Name = trainCarriageInput.Name
 	trainCarriage.Price = trainCarriageInput.Price
+	trainCarriage.SeatLayoutID = seatLayout.ID
+
+	// The carriage and its seat map change together, a failure leaves the old ones in place
+	tx := u.trainCarriageRepo.BeginTransaction()
+	defer tx.Rollback()
+	trainCarriageRepo := u.trainCarriageRepo.WithTx(tx)
+
+	trainCarriage, err = trainCarriageRepo.UpdateTrainCarriage(trainCarriage)
+
+	if err != nil {
+		return trainCarriageResponsee, err
+	}
 
-	trainCarriage, err = u.trainCarriageRepo.UpdateTrainCarriage(trainCarriage)
+	if isSeatLayoutChanged {
+		if seatLayout.ID > 0 {
+			err = generateTrainSeats(trainCarriageRepo, seatLayout, trainCarriage)
+		} else {
+			err = trainCarriageRepo.DeleteTrainSeatsByTrainCarriageID(trainCarriage.ID)
+		}
+		if err != nil {
+			return trainCarriageResponsee, err
+		}
+	}
 
+	err = tx.Commit().Error
 	if err != nil {
 		return trainCarriageResponsee, err
 	}
@@ -393,7 +518,7 @@ func (u *trainCarriageUsecase) UpdateTrainCarriage(id uint, trainCarriageInput d
 		return trainCarriageResponsee, err
 	}
 
-	trainSeat, err := u.trainCarriageRepo.GetTrainSeatsByClass(trainCarriage.Class)
+	trainSeat, err := u.getTrainSeats(trainCarriage)
 	if err != nil {
 		return trainCarriageResponsee, err
 	}
@@ -427,6 +552,9 @@ func (u *trainCarriageUsecase) UpdateTrainCarriage(id uint, trainCarriageInput d
 				Initial: getStation.Initial,
 			},
 			ArriveTime: train.ArriveTime,
+			Sequence:   train.Sequence,
+			DayOffset:  train.DayOffset,
+			DistanceKm: train.DistanceKm,
 		}
 
 		trainStationResponses = append(trainStationResponses, trainStationResponse)
@@ -474,3 +602,23 @@ func (u *trainCarriageUsecase) DeleteTrainCarriage(id uint) error {
 	}
 	return u.trainCarriageRepo.DeleteTrainCarriage(id)
 }
+
+// getTrainSeats returns the seats of a train carriage, falling back to the
+// shared seats of its class when no seat layout is assigned.
+func (u *trainCarriageUsecase) getTrainSeats(trainCarriage models.TrainCarriage) ([]models.TrainSeat, error) {
+	if trainCarriage.SeatLayoutID > 0 {
+		return u.trainCarriageRepo.GetTrainSeatsByTrainCarriageID(trainCarriage.ID)
+	}
+	return u.trainCarriageRepo.GetTrainSeatsByClass(trainCarriage.Class)
+}
+
+func (u *trainCarriageUsecase) getSeatLayout(id uint, class string) (models.SeatLayout, error) {
+	seatLayout, err := u.seatLayoutRepo.GetSeatLayoutByID(id)
+	if err != nil {
+		return seatLayout, errors.New("Seat layout not available")
+	}
+	if !strings.EqualFold(seatLayout.Class, class) {
+		return seatLayout, errors.New("Seat layout class does not match train carriage class")
+	}
+	return seatLayout, nil
+}
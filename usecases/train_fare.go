@@ -0,0 +1,407 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	trainFareBasisStop     = "stop"
+	trainFareBasisDistance = "distance"
+
+	passengerTypeAdult  = "adult"
+	passengerTypeSenior = "senior"
+	passengerTypeChild  = "child"
+	passengerTypeInfant = "infant"
+)
+
+// trainFareQuote is the price of one passenger on one leg, broken down the way the fare engine built it.
+type trainFareQuote struct {
+	BaseFare          int
+	PeakSurcharge     int
+	AdvanceDiscount   int
+	PassengerDiscount int
+	Total             int
+}
+
+type TrainFareUsecase interface {
+	GetTrainFare(trainID uint) (dtos.TrainFareResponse, error)
+	UpdateTrainFare(trainID uint, trainFareInput dtos.TrainFareInput) (dtos.TrainFareResponse, error)
+	CreateTrainFarePeak(trainID uint, trainFarePeakInput dtos.TrainFarePeakInput) (dtos.TrainFarePeakResponse, error)
+	DeleteTrainFarePeak(trainID, id uint) error
+	CreateTrainFareAdvance(trainID uint, trainFareAdvanceInput dtos.TrainFareAdvanceInput) (dtos.TrainFareAdvanceResponse, error)
+	DeleteTrainFareAdvance(trainID, id uint) error
+}
+
+type trainFareUsecase struct {
+	trainFareRepo repositories.TrainFareRepository
+	trainRepo     repositories.TrainRepository
+}
+
+func NewTrainFareUsecase(trainFareRepo repositories.TrainFareRepository, trainRepo repositories.TrainRepository) TrainFareUsecase {
+	return &trainFareUsecase{trainFareRepo, trainRepo}
+}
+
+// GetTrainFare godoc
+// @Summary      Get train fare
+// @Description  Get fare rules per class, peak surcharges and advance purchase discounts of a train
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Success      200 {object} dtos.TrainFareStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/fare [get]
+// @Security BearerAuth
+func (u *trainFareUsecase) GetTrainFare(trainID uint) (dtos.TrainFareResponse, error) {
+	var trainFareResponse dtos.TrainFareResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainFareResponse, errors.New("Failed to get train")
+	}
+
+	return u.getTrainFareResponse(train.ID)
+}
+
+// UpdateTrainFare godoc
+// @Summary      Update train fare
+// @Description  Set the fare rule of a class, priced per stop or per kilometre travelled
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param        request body dtos.TrainFareInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.TrainFareStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/fare [put]
+// @Security BearerAuth
+func (u *trainFareUsecase) UpdateTrainFare(trainID uint, trainFareInput dtos.TrainFareInput) (dtos.TrainFareResponse, error) {
+	var trainFareResponse dtos.TrainFareResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainFareResponse, errors.New("Failed to get train")
+	}
+
+	if trainFareInput.Class == "" || trainFareInput.BaseFare < 0 || trainFareInput.RatePerUnit < 0 {
+		return trainFareResponse, errors.New("Failed to update train fare")
+	}
+
+	basis := strings.ToLower(trainFareInput.Basis)
+	if basis == "" {
+		basis = trainFareBasisStop
+	}
+	if basis != trainFareBasisStop && basis != trainFareBasisDistance {
+		return trainFareResponse, errors.New("Basis must be stop or distance")
+	}
+
+	if !isPercent(trainFareInput.SeniorDiscount) || !isPercent(trainFareInput.ChildDiscount) || !isPercent(trainFareInput.InfantDiscount) {
+		return trainFareResponse, errors.New("Discount must be between 0 and 100")
+	}
+
+	trainFare, _ := u.trainFareRepo.GetTrainFareByTrainIDAndClass(train.ID, trainFareInput.Class)
+	trainFare.TrainID = train.ID
+	trainFare.Class = trainFareInput.Class
+	trainFare.Basis = basis
+	trainFare.BaseFare = trainFareInput.BaseFare
+	trainFare.RatePerUnit = trainFareInput.RatePerUnit
+	trainFare.SeniorDiscount = trainFareInput.SeniorDiscount
+	trainFare.ChildDiscount = trainFareInput.ChildDiscount
+	trainFare.InfantDiscount = trainFareInput.InfantDiscount
+
+	_, err = u.trainFareRepo.SaveTrainFare(trainFare)
+	if err != nil {
+		return trainFareResponse, err
+	}
+
+	return u.getTrainFareResponse(train.ID)
+}
+
+// CreateTrainFarePeak godoc
+// @Summary      Create train fare peak
+// @Description  Add a surcharge to every fare of a train between two dates, e.g. a holiday season
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param        request body dtos.TrainFarePeakInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TrainFarePeakCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/fare/peak [post]
+// @Security BearerAuth
+func (u *trainFareUsecase) CreateTrainFarePeak(trainID uint, trainFarePeakInput dtos.TrainFarePeakInput) (dtos.TrainFarePeakResponse, error) {
+	var trainFarePeakResponse dtos.TrainFarePeakResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainFarePeakResponse, errors.New("Failed to get train")
+	}
+
+	dateStart, err := helpers.FormatStringToDate(trainFarePeakInput.DateStart)
+	if err != nil {
+		return trainFarePeakResponse, errors.New("Failed to parse date start")
+	}
+	dateEnd, err := helpers.FormatStringToDate(trainFarePeakInput.DateEnd)
+	if err != nil {
+		return trainFarePeakResponse, errors.New("Failed to parse date end")
+	}
+	if dateEnd.Before(dateStart) {
+		return trainFarePeakResponse, errors.New("Date end must be after date start")
+	}
+
+	if trainFarePeakInput.SurchargePercent < 1 {
+		return trainFarePeakResponse, errors.New("Surcharge percent must be at least 1")
+	}
+
+	createTrainFarePeak := models.TrainFarePeak{
+		TrainID:          train.ID,
+		DateStart:        dateStart,
+		DateEnd:          dateEnd,
+		SurchargePercent: trainFarePeakInput.SurchargePercent,
+		Note:             trainFarePeakInput.Note,
+	}
+
+	createTrainFarePeak, err = u.trainFareRepo.CreateTrainFarePeak(createTrainFarePeak)
+	if err != nil {
+		return trainFarePeakResponse, err
+	}
+
+	return newTrainFarePeakResponse(createTrainFarePeak), nil
+}
+
+// DeleteTrainFarePeak godoc
+// @Summary      Delete train fare peak
+// @Description  Delete train fare peak
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param peak_id path integer true "ID train fare peak"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/fare/peak/{peak_id} [delete]
+// @Security BearerAuth
+func (u *trainFareUsecase) DeleteTrainFarePeak(trainID, id uint) error {
+	trainFarePeak, err := u.trainFareRepo.GetTrainFarePeakByID(id)
+	if err != nil || trainFarePeak.TrainID != trainID {
+		return errors.New("Failed to get train fare peak")
+	}
+	return u.trainFareRepo.DeleteTrainFarePeak(trainFarePeak)
+}
+
+// CreateTrainFareAdvance godoc
+// @Summary      Create train fare advance discount
+// @Description  Discount every fare of a train when bought a number of days before the service
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param        request body dtos.TrainFareAdvanceInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TrainFareAdvanceCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/fare/advance [post]
+// @Security BearerAuth
+func (u *trainFareUsecase) CreateTrainFareAdvance(trainID uint, trainFareAdvanceInput dtos.TrainFareAdvanceInput) (dtos.TrainFareAdvanceResponse, error) {
+	var trainFareAdvanceResponse dtos.TrainFareAdvanceResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainFareAdvanceResponse, errors.New("Failed to get train")
+	}
+
+	if trainFareAdvanceInput.MinDaysBefore < 1 {
+		return trainFareAdvanceResponse, errors.New("Min days before must be at least 1")
+	}
+	if trainFareAdvanceInput.DiscountPercent < 1 || !isPercent(trainFareAdvanceInput.DiscountPercent) {
+		return trainFareAdvanceResponse, errors.New("Discount must be between 1 and 100")
+	}
+
+	createTrainFareAdvance := models.TrainFareAdvance{
+		TrainID:         train.ID,
+		MinDaysBefore:   trainFareAdvanceInput.MinDaysBefore,
+		DiscountPercent: trainFareAdvanceInput.DiscountPercent,
+	}
+
+	createTrainFareAdvance, err = u.trainFareRepo.CreateTrainFareAdvance(createTrainFareAdvance)
+	if err != nil {
+		return trainFareAdvanceResponse, err
+	}
+
+	return newTrainFareAdvanceResponse(createTrainFareAdvance), nil
+}
+
+// DeleteTrainFareAdvance godoc
+// @Summary      Delete train fare advance discount
+// @Description  Delete train fare advance discount
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param advance_id path integer true "ID train fare advance discount"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/fare/advance/{advance_id} [delete]
+// @Security BearerAuth
+func (u *trainFareUsecase) DeleteTrainFareAdvance(trainID, id uint) error {
+	trainFareAdvance, err := u.trainFareRepo.GetTrainFareAdvanceByID(id)
+	if err != nil || trainFareAdvance.TrainID != trainID {
+		return errors.New("Failed to get train fare advance discount")
+	}
+	return u.trainFareRepo.DeleteTrainFareAdvance(trainFareAdvance)
+}
+
+func (u *trainFareUsecase) getTrainFareResponse(trainID uint) (dtos.TrainFareResponse, error) {
+	trainFareResponse := dtos.TrainFareResponse{
+		TrainID:  trainID,
+		Fares:    make([]dtos.TrainFareRuleResponse, 0),
+		Peaks:    make([]dtos.TrainFarePeakResponse, 0),
+		Advances: make([]dtos.TrainFareAdvanceResponse, 0),
+	}
+
+	trainFares, err := u.trainFareRepo.GetTrainFaresByTrainID(trainID)
+	if err != nil {
+		return trainFareResponse, err
+	}
+	for _, trainFare := range trainFares {
+		trainFareResponse.Fares = append(trainFareResponse.Fares, dtos.TrainFareRuleResponse{
+			TrainFareID:    trainFare.ID,
+			Class:          trainFare.Class,
+			Basis:          trainFare.Basis,
+			BaseFare:       trainFare.BaseFare,
+			RatePerUnit:    trainFare.RatePerUnit,
+			SeniorDiscount: trainFare.SeniorDiscount,
+			ChildDiscount:  trainFare.ChildDiscount,
+			InfantDiscount: trainFare.InfantDiscount,
+		})
+	}
+
+	trainFarePeaks, err := u.trainFareRepo.GetTrainFarePeaksByTrainID(trainID)
+	if err != nil {
+		return trainFareResponse, err
+	}
+	for _, trainFarePeak := range trainFarePeaks {
+		trainFareResponse.Peaks = append(trainFareResponse.Peaks, newTrainFarePeakResponse(trainFarePeak))
+	}
+
+	trainFareAdvances, err := u.trainFareRepo.GetTrainFareAdvancesByTrainID(trainID)
+	if err != nil {
+		return trainFareResponse, err
+	}
+	for _, trainFareAdvance := range trainFareAdvances {
+		trainFareResponse.Advances = append(trainFareResponse.Advances, newTrainFareAdvanceResponse(trainFareAdvance))
+	}
+
+	return trainFareResponse, nil
+}
+
+func newTrainFarePeakResponse(trainFarePeak models.TrainFarePeak) dtos.TrainFarePeakResponse {
+	return dtos.TrainFarePeakResponse{
+		TrainFarePeakID:  trainFarePeak.ID,
+		DateStart:        helpers.FormatDateToYMD(&trainFarePeak.DateStart),
+		DateEnd:          helpers.FormatDateToYMD(&trainFarePeak.DateEnd),
+		SurchargePercent: trainFarePeak.SurchargePercent,
+		Note:             trainFarePeak.Note,
+	}
+}
+
+func newTrainFareAdvanceResponse(trainFareAdvance models.TrainFareAdvance) dtos.TrainFareAdvanceResponse {
+	return dtos.TrainFareAdvanceResponse{
+		TrainFareAdvanceID: trainFareAdvance.ID,
+		MinDaysBefore:      trainFareAdvance.MinDaysBefore,
+		DiscountPercent:    trainFareAdvance.DiscountPercent,
+	}
+}
+
+func newTrainFareQuoteResponse(quote trainFareQuote) dtos.TrainFareQuoteResponse {
+	return dtos.TrainFareQuoteResponse{
+		BaseFare:          quote.BaseFare,
+		PeakSurcharge:     quote.PeakSurcharge,
+		AdvanceDiscount:   quote.AdvanceDiscount,
+		PassengerDiscount: quote.PassengerDiscount,
+		Total:             quote.Total,
+	}
+}
+
+func isPercent(percent int) bool {
+	return percent >= 0 && percent <= 100
+}
+
+// quoteTrainFare prices one passenger of passengerType in the carriage between two stops of the service
+// running on serviceDate, bought at purchaseAt. Search and ordering both call it so the quoted price is
+// the charged price. Classes without a fare rule keep the flat carriage price.
+func quoteTrainFare(trainFareRepo repositories.TrainFareRepository, trainCarriage models.TrainCarriage, origin, destination models.TrainStation, serviceDate, purchaseAt time.Time, passengerType string) trainFareQuote {
+	quote := trainFareQuote{BaseFare: trainCarriage.Price}
+	seniorDiscount, childDiscount, infantDiscount := 0, 0, 100
+
+	trainFare, _ := trainFareRepo.GetTrainFareByTrainIDAndClass(trainCarriage.TrainID, trainCarriage.Class)
+	if trainFare.ID > 0 {
+		units := destination.Sequence - origin.Sequence
+		if trainFare.Basis == trainFareBasisDistance {
+			units = destination.DistanceKm - origin.DistanceKm
+		}
+		if units < 0 {
+			units = 0
+		}
+		quote.BaseFare = trainFare.BaseFare + trainFare.RatePerUnit*units
+		seniorDiscount, childDiscount, infantDiscount = trainFare.SeniorDiscount, trainFare.ChildDiscount, trainFare.InfantDiscount
+	}
+
+	trainFarePeak, _ := trainFareRepo.GetTrainFarePeakByTrainIDAndDate(trainCarriage.TrainID, helpers.FormatDateToYMD(&serviceDate))
+	if trainFarePeak.ID > 0 {
+		quote.PeakSurcharge = quote.BaseFare * trainFarePeak.SurchargePercent / 100
+	}
+
+	// Whole days between the purchase and the service, both taken as calendar dates
+	purchaseDate := time.Date(purchaseAt.Year(), purchaseAt.Month(), purchaseAt.Day(), 0, 0, 0, 0, serviceDate.Location())
+	daysBefore := int(serviceDate.Sub(purchaseDate).Hours() / 24)
+	trainFareAdvance, _ := trainFareRepo.GetTrainFareAdvanceByTrainIDAndDays(trainCarriage.TrainID, daysBefore)
+	if trainFareAdvance.ID > 0 {
+		quote.AdvanceDiscount = quote.BaseFare * trainFareAdvance.DiscountPercent / 100
+	}
+
+	subtotal := quote.BaseFare + quote.PeakSurcharge - quote.AdvanceDiscount
+	switch passengerType {
+	case passengerTypeSenior:
+		quote.PassengerDiscount = subtotal * seniorDiscount / 100
+	case passengerTypeChild:
+		quote.PassengerDiscount = subtotal * childDiscount / 100
+	case passengerTypeInfant:
+		quote.PassengerDiscount = subtotal * infantDiscount / 100
+	}
+
+	quote.Total = subtotal - quote.PassengerDiscount
+	if quote.Total < 0 {
+		quote.Total = 0
+	}
+	return quote
+}
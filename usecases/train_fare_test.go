@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeTrainFareRepo serves the fare rule, peak and advance discounts of one train, the other methods are
+// not used.
+type fakeTrainFareRepo struct {
+	repositories.TrainFareRepository
+	trainFare         models.TrainFare
+	trainFarePeaks    map[string]models.TrainFarePeak
+	trainFareAdvances []models.TrainFareAdvance
+}
+
+func (r fakeTrainFareRepo) GetTrainFareByTrainIDAndClass(trainID uint, class string) (models.TrainFare, error) {
+	if r.trainFare.ID == 0 || r.trainFare.Class != class {
+		return models.TrainFare{}, errors.New("record not found")
+	}
+	return r.trainFare, nil
+}
+
+func (r fakeTrainFareRepo) GetTrainFarePeakByTrainIDAndDate(trainID uint, date string) (models.TrainFarePeak, error) {
+	trainFarePeak, ok := r.trainFarePeaks[date]
+	if !ok {
+		return trainFarePeak, errors.New("record not found")
+	}
+	return trainFarePeak, nil
+}
+
+func (r fakeTrainFareRepo) GetTrainFareAdvanceByTrainIDAndDays(trainID uint, daysBefore int) (models.TrainFareAdvance, error) {
+	var best models.TrainFareAdvance
+	for _, trainFareAdvance := range r.trainFareAdvances {
+		if trainFareAdvance.MinDaysBefore <= daysBefore && trainFareAdvance.DiscountPercent > best.DiscountPercent {
+			best = trainFareAdvance
+		}
+	}
+	if best.ID == 0 {
+		return best, errors.New("record not found")
+	}
+	return best, nil
+}
+
+func TestQuoteTrainFare(t *testing.T) {
+	serviceDate := time.Date(2026, 12, 24, 0, 0, 0, 0, time.Local)
+	trainCarriage := models.TrainCarriage{TrainID: 1, Class: "ekonomi", Price: 100000}
+	origin := models.TrainStation{Sequence: 1, DistanceKm: 30}
+	destination := models.TrainStation{Sequence: 4, DistanceKm: 150}
+	stopFare := models.TrainFare{Model: gorm.Model{ID: 1}, Class: "ekonomi", Basis: trainFareBasisStop, BaseFare: 20000, RatePerUnit: 10000, SeniorDiscount: 20, ChildDiscount: 25, InfantDiscount: 100}
+	distanceFare := models.TrainFare{Model: gorm.Model{ID: 1}, Class: "ekonomi", Basis: trainFareBasisDistance, BaseFare: 20000, RatePerUnit: 100}
+
+	tests := []struct {
+		name          string
+		trainFareRepo fakeTrainFareRepo
+		purchaseAt    time.Time
+		passengerType string
+		want          trainFareQuote
+	}{
+		{
+			name:          "class without a fare rule keeps the carriage price",
+			purchaseAt:    serviceDate,
+			passengerType: passengerTypeAdult,
+			want:          trainFareQuote{BaseFare: 100000, Total: 100000},
+		},
+		{
+			name:          "infant rides free without a fare rule",
+			purchaseAt:    serviceDate,
+			passengerType: passengerTypeInfant,
+			want:          trainFareQuote{BaseFare: 100000, PassengerDiscount: 100000, Total: 0},
+		},
+		{
+			name:          "fare by stops",
+			trainFareRepo: fakeTrainFareRepo{trainFare: stopFare},
+			purchaseAt:    serviceDate,
+			passengerType: passengerTypeAdult,
+			want:          trainFareQuote{BaseFare: 50000, Total: 50000},
+		},
+		{
+			name:          "fare by distance",
+			trainFareRepo: fakeTrainFareRepo{trainFare: distanceFare},
+			purchaseAt:    serviceDate,
+			passengerType: passengerTypeAdult,
+			want:          trainFareQuote{BaseFare: 32000, Total: 32000},
+		},
+		{
+			name: "peak surcharge on the service date",
+			trainFareRepo: fakeTrainFareRepo{trainFare: stopFare, trainFarePeaks: map[string]models.TrainFarePeak{
+				"2026-12-24": {Model: gorm.Model{ID: 1}, SurchargePercent: 20},
+			}},
+			purchaseAt:    serviceDate,
+			passengerType: passengerTypeAdult,
+			want:          trainFareQuote{BaseFare: 50000, PeakSurcharge: 10000, Total: 60000},
+		},
+		{
+			name: "advance discount counts whole days before the service",
+			trainFareRepo: fakeTrainFareRepo{trainFare: stopFare, trainFareAdvances: []models.TrainFareAdvance{
+				{Model: gorm.Model{ID: 1}, MinDaysBefore: 7, DiscountPercent: 10},
+				{Model: gorm.Model{ID: 2}, MinDaysBefore: 30, DiscountPercent: 30},
+			}},
+			purchaseAt:    serviceDate.AddDate(0, 0, -7).Add(23 * time.Hour),
+			passengerType: passengerTypeAdult,
+			want:          trainFareQuote{BaseFare: 50000, AdvanceDiscount: 5000, Total: 45000},
+		},
+		{
+			name: "advance discount not reached",
+			trainFareRepo: fakeTrainFareRepo{trainFare: stopFare, trainFareAdvances: []models.TrainFareAdvance{
+				{Model: gorm.Model{ID: 1}, MinDaysBefore: 7, DiscountPercent: 10},
+			}},
+			purchaseAt:    serviceDate.AddDate(0, 0, -6),
+			passengerType: passengerTypeAdult,
+			want:          trainFareQuote{BaseFare: 50000, Total: 50000},
+		},
+		{
+			name: "child discount applies after peak and advance",
+			trainFareRepo: fakeTrainFareRepo{trainFare: stopFare, trainFarePeaks: map[string]models.TrainFarePeak{
+				"2026-12-24": {Model: gorm.Model{ID: 1}, SurchargePercent: 20},
+			}, trainFareAdvances: []models.TrainFareAdvance{
+				{Model: gorm.Model{ID: 1}, MinDaysBefore: 7, DiscountPercent: 10},
+			}},
+			purchaseAt:    serviceDate.AddDate(0, 0, -10),
+			passengerType: passengerTypeChild,
+			want:          trainFareQuote{BaseFare: 50000, PeakSurcharge: 10000, AdvanceDiscount: 5000, PassengerDiscount: 13750, Total: 41250},
+		},
+		{
+			name:          "senior discount",
+			trainFareRepo: fakeTrainFareRepo{trainFare: stopFare},
+			purchaseAt:    serviceDate,
+			passengerType: passengerTypeSenior,
+			want:          trainFareQuote{BaseFare: 50000, PassengerDiscount: 10000, Total: 40000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := quoteTrainFare(tt.trainFareRepo, trainCarriage, origin, destination, serviceDate, tt.purchaseAt, tt.passengerType)
+			if got != tt.want {
+				t.Errorf("quoteTrainFare() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
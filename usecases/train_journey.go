@@ -0,0 +1,468 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultMinConnectionTime is the minimum number of minutes a traveler needs
+// to change trains at a transfer station.
+const defaultMinConnectionTime = 30
+
+// maxJourneyTransfer is the highest number of train changes the journey
+// planner will look for.
+const maxJourneyTransfer = 2
+
+type journeyStop struct {
+	StationID  uint
+	ArriveTime string
+	Minute     int
+}
+
+// journeyLeg is one train ridden between two stops. ServiceDay is the service the leg rides, counted in
+// days from the travel date.
+type journeyLeg struct {
+	TrainID     uint
+	ServiceDay  int
+	Origin      journeyStop
+	Destination journeyStop
+}
+
+// buildTrainRoutes groups the train stations by train, keeping the order of
+// the given rows which is expected to be sorted by stop sequence.
+func buildTrainRoutes(trainStations []models.TrainStation) (map[uint][]journeyStop, []uint) {
+	routes := make(map[uint][]journeyStop)
+	var trainIDs []uint
+
+	for _, trainStation := range trainStations {
+		minute, err := helpers.FormatTimeToMinutes(trainStation.ArriveTime)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := routes[trainStation.TrainID]; !ok {
+			trainIDs = append(trainIDs, trainStation.TrainID)
+		}
+
+		routes[trainStation.TrainID] = append(routes[trainStation.TrainID], journeyStop{
+			StationID:  trainStation.StationID,
+			ArriveTime: trainStation.ArriveTime,
+			Minute:     minute + trainStation.DayOffset*minutesPerDay,
+		})
+	}
+
+	return routes, trainIDs
+}
+
+// planTrainJourneys walks the station graph and returns every itinerary from
+// origin to destination departing on the travel date that uses at most
+// maxTransfer train changes. Stop minutes of the legs count from midnight of
+// the travel date, so a connection may wait overnight for the first service
+// leaving after the connection time. isRunning tells whether a train runs the
+// service starting the given number of days after the travel date.
+func planTrainJourneys(routes map[uint][]journeyStop, trainIDs []uint, originID, destinationID uint, maxTransfer, minConnectionTime int, isRunning func(trainID uint, serviceDay int) bool) [][]journeyLeg {
+	var journeys [][]journeyLeg
+
+	visitedStations := map[uint]bool{originID: true}
+	usedTrains := make(map[uint]bool)
+
+	var walk func(stationID uint, arrival int, legs []journeyLeg)
+	walk = func(stationID uint, arrival int, legs []journeyLeg) {
+		for _, trainID := range trainIDs {
+			if usedTrains[trainID] {
+				continue
+			}
+
+			route := routes[trainID]
+			for i, board := range route {
+				if board.StationID != stationID {
+					continue
+				}
+
+				// The first train leaves on the travel date, a connecting one is the first service
+				// reaching the stop once the connection time has passed
+				serviceDay := -floorDiv(board.Minute, minutesPerDay)
+				if len(legs) > 0 {
+					serviceDay = -floorDiv(board.Minute-arrival-minConnectionTime, minutesPerDay)
+				}
+				if !isRunning(trainID, serviceDay) {
+					break
+				}
+				origin := board
+				origin.Minute += serviceDay * minutesPerDay
+
+				for _, alight := range route[i+1:] {
+					if visitedStations[alight.StationID] {
+						continue
+					}
+
+					destination := alight
+					destination.Minute += serviceDay * minutesPerDay
+					nextLegs := append(append([]journeyLeg{}, legs...), journeyLeg{
+						TrainID:     trainID,
+						ServiceDay:  serviceDay,
+						Origin:      origin,
+						Destination: destination,
+					})
+
+					if alight.StationID == destinationID {
+						journeys = append(journeys, nextLegs)
+						continue
+					}
+
+					if len(nextLegs) > maxTransfer {
+						continue
+					}
+
+					visitedStations[alight.StationID] = true
+					usedTrains[trainID] = true
+					walk(alight.StationID, destination.Minute, nextLegs)
+					delete(visitedStations, alight.StationID)
+					delete(usedTrains, trainID)
+				}
+				break
+			}
+		}
+	}
+
+	walk(originID, 0, nil)
+
+	return journeys
+}
+
+// floorDiv divides rounding toward negative infinity, stop minutes before the
+// travel date are negative.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// validateJourneyLegs checks that consecutive legs connect at the same station
+// and leave enough time to change trains.
+func validateJourneyLegs(legs []journeyLeg, minConnectionTime int) error {
+	for i := 1; i < len(legs); i++ {
+		previous := legs[i-1]
+		current := legs[i]
+
+		if previous.Destination.StationID != current.Origin.StationID {
+			return errors.New("Journey legs are not connected")
+		}
+
+		if current.Origin.Minute-previous.Destination.Minute < minConnectionTime {
+			return errors.New("Connection time between trains is too short")
+		}
+	}
+	return nil
+}
+
+// ticketTravelerDetailLeg is one booked train segment. Transfer marks a leg
+// that continues the itinerary of the leg booked right before it.
+type ticketTravelerDetailLeg struct {
+	dtos.TicketTravelerDetailInput
+	Transfer bool
+}
+
+// expandTicketTravelerDetailLegs flattens itinerary entries into their legs so
+// a whole journey can be booked in a single ticket order.
+func expandTicketTravelerDetailLegs(ticketTravelerDetails []dtos.TicketTravelerDetailInput) []ticketTravelerDetailLeg {
+	var legs []ticketTravelerDetailLeg
+	for _, ticketTravelerDetail := range ticketTravelerDetails {
+		if len(ticketTravelerDetail.Legs) == 0 {
+			legs = append(legs, ticketTravelerDetailLeg{TicketTravelerDetailInput: ticketTravelerDetail})
+			continue
+		}
+
+		for i, leg := range ticketTravelerDetail.Legs {
+			if leg.Date == "" {
+				leg.Date = ticketTravelerDetail.Date
+			}
+			legs = append(legs, ticketTravelerDetailLeg{TicketTravelerDetailInput: leg, Transfer: i > 0})
+		}
+	}
+	return legs
+}
+
+// newJourneyLeg builds a journey leg from the boarding and alighting stops of
+// a booked train.
+func newJourneyLeg(trainID uint, origin, destination models.TrainStation) (journeyLeg, error) {
+	originMinute, err := helpers.FormatTimeToMinutes(origin.ArriveTime)
+	if err != nil {
+		return journeyLeg{}, err
+	}
+	destinationMinute, err := helpers.FormatTimeToMinutes(destination.ArriveTime)
+	if err != nil {
+		return journeyLeg{}, err
+	}
+	// Overnight legs arrive on a later day than they depart
+	destinationMinute += (destination.DayOffset - origin.DayOffset) * minutesPerDay
+
+	return journeyLeg{
+		TrainID:     trainID,
+		Origin:      journeyStop{StationID: origin.StationID, ArriveTime: origin.ArriveTime, Minute: originMinute},
+		Destination: journeyStop{StationID: destination.StationID, ArriveTime: destination.ArriveTime, Minute: destinationMinute},
+	}, nil
+}
+
+// SearchTrainJourney godoc
+// @Summary      Search Train Journey
+// @Description  Search direct and transfer itineraries between two stations
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param station_origin_id query int true "Station origin id"
+// @Param station_destination_id query int true "Station destination id"
+// @Param date query string false "Travel date (2006-01-02), today when empty"
+// @Param max_transfer query int false "Maximum number of transfers" Enums(0, 1, 2)
+// @Param min_connection_time query int false "Minimum connection time in minutes"
+// @Param sort_by_class query string false "Filter by class name" Enums(Ekonomi, Bisnis, Eksekutif)
+// @Success      200 {object} dtos.GetAllTrainJourneyStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/journey [get]
+// @Security BearerAuth
+func (u *trainUsecase) SearchTrainJourney(userId uint, page, limit, stationOriginId, stationDestinationId, maxTransfer, minConnectionTime int, date, sortClassName string) ([]dtos.TrainJourneyResponse, int, error) {
+	if stationOriginId < 1 || stationDestinationId < 1 || stationOriginId == stationDestinationId {
+		return nil, 0, errors.New("Station origin and destination must be different")
+	}
+	if maxTransfer < 0 || maxTransfer > maxJourneyTransfer {
+		return nil, 0, errors.New("Maximum transfer must be between 0 and 2")
+	}
+	if minConnectionTime < 0 {
+		return nil, 0, errors.New("Minimum connection time must not be negative")
+	}
+
+	now := time.Now()
+	if date == "" {
+		date = now.Format("2006-01-02")
+	}
+	if now.Format("2006-01-02") > date {
+		return nil, 0, errors.New("Departure date must not be in the past")
+	}
+	travelDate, err := helpers.FormatStringToDate(date)
+	if err != nil {
+		return nil, 0, errors.New("Failed to parse date")
+	}
+
+	trainStations, err := u.trainRepo.GetAllTrainStationsAvailable()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The planner asks for the same service many times, each is looked up once
+	runningServices := make(map[uint]map[int]bool)
+	isRunning := func(trainID uint, serviceDay int) bool {
+		if runningServices[trainID] == nil {
+			runningServices[trainID] = make(map[int]bool)
+		}
+		running, ok := runningServices[trainID][serviceDay]
+		if !ok {
+			running = isTrainRunning(u.trainScheduleRepo, trainID, travelDate.AddDate(0, 0, serviceDay))
+			runningServices[trainID][serviceDay] = running
+		}
+		return running
+	}
+
+	routes, trainIDs := buildTrainRoutes(trainStations)
+	journeys := planTrainJourneys(routes, trainIDs, uint(stationOriginId), uint(stationDestinationId), maxTransfer, minConnectionTime, isRunning)
+
+	trains := make(map[uint]models.Train)
+	trainCarriages := make(map[uint][]models.TrainCarriage)
+	stations := make(map[uint]models.Station)
+
+	var trainJourneyResponses []dtos.TrainJourneyResponse
+
+	for _, journey := range journeys {
+		var legResponses []dtos.TrainJourneyLegResponse
+		totalFare := 0
+		isAvailable := true
+
+		for i, leg := range journey {
+			train, ok := trains[leg.TrainID]
+			if !ok {
+				train, err = u.trainRepo.GetTrainByID(leg.TrainID)
+				if err != nil {
+					return nil, 0, err
+				}
+				trains[leg.TrainID] = train
+			}
+
+			carriages, ok := trainCarriages[leg.TrainID]
+			if !ok {
+				getTrainCarriage, err := u.trainRepo.GetTrainCarriageByTrainID(leg.TrainID)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				visitedClass := make(map[string]bool)
+				for _, trainCarriage := range getTrainCarriage {
+					if visitedClass[strings.ToLower(trainCarriage.Class)] {
+						continue
+					}
+					visitedClass[strings.ToLower(trainCarriage.Class)] = true
+
+					carriages = append(carriages, trainCarriage)
+				}
+				trainCarriages[leg.TrainID] = carriages
+			}
+
+			// Quote every class of the leg with the same engine the order is charged with
+			trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(leg.TrainID, leg.Origin.StationID)
+			if err != nil {
+				return nil, 0, err
+			}
+			trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID(leg.TrainID, leg.Destination.StationID)
+			if err != nil {
+				return nil, 0, err
+			}
+			serviceDate := travelDate.AddDate(0, 0, leg.ServiceDay)
+
+			var carriageResponses []dtos.TrainJourneyCarriageResponse
+			for _, trainCarriage := range carriages {
+				trainFareQuote := quoteTrainFare(u.trainFareRepo, trainCarriage, trainStationOrigin, trainStationDestination, serviceDate, now, passengerTypeAdult)
+				carriageResponses = append(carriageResponses, dtos.TrainJourneyCarriageResponse{
+					TrainCarriageID: trainCarriage.ID,
+					Class:           trainCarriage.Class,
+					Price:           trainFareQuote.Total,
+				})
+			}
+
+			// Pick the carriage of the requested class, or the cheapest one
+			var selected *dtos.TrainJourneyCarriageResponse
+			for j := range carriageResponses {
+				if sortClassName != "" {
+					if strings.EqualFold(carriageResponses[j].Class, sortClassName) {
+						selected = &carriageResponses[j]
+						break
+					}
+					continue
+				}
+				if selected == nil || carriageResponses[j].Price < selected.Price {
+					selected = &carriageResponses[j]
+				}
+			}
+			if selected == nil {
+				isAvailable = false
+				break
+			}
+
+			var stationResponses []dtos.StationResponseSimply
+			for _, stop := range []journeyStop{leg.Origin, leg.Destination} {
+				station, ok := stations[stop.StationID]
+				if !ok {
+					station, err = u.trainRepo.GetStationByID2(stop.StationID)
+					if err != nil {
+						return nil, 0, err
+					}
+					stations[stop.StationID] = station
+				}
+
+				stationResponses = append(stationResponses, dtos.StationResponseSimply{
+					StationID:  station.ID,
+					Origin:     station.Origin,
+					Name:       station.Name,
+					Initial:    station.Initial,
+					ArriveTime: stop.ArriveTime,
+				})
+			}
+
+			connectionTime := 0
+			if i+1 < len(journey) {
+				connectionTime = journey[i+1].Origin.Minute - leg.Destination.Minute
+			}
+
+			// The leg is booked on the date the traveler boards it
+			boardingDate := travelDate.AddDate(0, 0, floorDiv(leg.Origin.Minute, minutesPerDay))
+
+			legResponses = append(legResponses, dtos.TrainJourneyLegResponse{
+				TrainID:            train.ID,
+				Date:               helpers.FormatDateToYMD(&boardingDate),
+				CodeTrain:          train.CodeTrain,
+				Name:               train.Name,
+				StationOrigin:      stationResponses[0],
+				StationDestination: stationResponses[1],
+				TrainCarriageID:    selected.TrainCarriageID,
+				Class:              selected.Class,
+				Price:              selected.Price,
+				TrainCarriage:      carriageResponses,
+				ConnectionTime:     connectionTime,
+			})
+			totalFare += selected.Price
+		}
+
+		if !isAvailable {
+			continue
+		}
+
+		firstLeg := journey[0]
+		lastLeg := journey[len(journey)-1]
+
+		trainJourneyResponses = append(trainJourneyResponses, dtos.TrainJourneyResponse{
+			Transfer:      len(journey) - 1,
+			DepartureTime: firstLeg.Origin.ArriveTime,
+			ArrivalTime:   lastLeg.Destination.ArriveTime,
+			DepartureAt:   travelDate.Add(time.Duration(firstLeg.Origin.Minute) * time.Minute),
+			ArrivalAt:     travelDate.Add(time.Duration(lastLeg.Destination.Minute) * time.Minute),
+			TotalDuration: lastLeg.Destination.Minute - firstLeg.Origin.Minute,
+			TotalFare:     totalFare,
+			Legs:          legResponses,
+		})
+	}
+
+	// Sort itineraries by fewest transfers, then earliest arrival, then cheapest fare
+	sort.SliceStable(trainJourneyResponses, func(i, j int) bool {
+		if trainJourneyResponses[i].Transfer != trainJourneyResponses[j].Transfer {
+			return trainJourneyResponses[i].Transfer < trainJourneyResponses[j].Transfer
+		}
+		if !trainJourneyResponses[i].ArrivalAt.Equal(trainJourneyResponses[j].ArrivalAt) {
+			return trainJourneyResponses[i].ArrivalAt.Before(trainJourneyResponses[j].ArrivalAt)
+		}
+		return trainJourneyResponses[i].TotalFare < trainJourneyResponses[j].TotalFare
+	})
+
+	historySeenStationInput := dtos.HistorySeenStationInput{
+		StationOriginID:      uint(stationOriginId),
+		StationDestinationID: uint(stationDestinationId),
+	}
+
+	_, err = u.historySeenStationUsecase.CreateHistorySeenStation(userId, historySeenStationInput)
+	if err != nil {
+		return trainJourneyResponses, 0, err
+	}
+
+	// Apply offset and limit to trainJourneyResponses
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	start := (page - 1) * limit
+	end := start + limit
+
+	// Ensure that `start` is within the range of trainJourneyResponses
+	if start >= len(trainJourneyResponses) {
+		return nil, 0, nil
+	}
+
+	// Ensure that `end` does not exceed the length of trainJourneyResponses
+	if end > len(trainJourneyResponses) {
+		end = len(trainJourneyResponses)
+	}
+
+	subsetTrainJourneyResponses := trainJourneyResponses[start:end]
+
+	return subsetTrainJourneyResponses, len(trainJourneyResponses), nil
+}
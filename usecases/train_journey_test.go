@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFloorDiv(t *testing.T) {
+	tests := []struct {
+		a, b, want int
+	}{
+		{0, minutesPerDay, 0},
+		{480, minutesPerDay, 0},
+		{1500, minutesPerDay, 1},
+		{-1, minutesPerDay, -1},
+		{-1440, minutesPerDay, -1},
+		{-1441, minutesPerDay, -2},
+	}
+	for _, tt := range tests {
+		if got := floorDiv(tt.a, tt.b); got != tt.want {
+			t.Errorf("floorDiv(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPlanTrainJourneys(t *testing.T) {
+	stop := func(stationID uint, minute int) journeyStop {
+		return journeyStop{StationID: stationID, Minute: minute}
+	}
+	runsDaily := func(trainID uint, serviceDay int) bool { return true }
+
+	tests := []struct {
+		name          string
+		routes        map[uint][]journeyStop
+		trainIDs      []uint
+		destinationID uint
+		maxTransfer   int
+		isRunning     func(trainID uint, serviceDay int) bool
+		want          []string
+	}{
+		{
+			name:          "direct train",
+			routes:        map[uint][]journeyStop{1: {stop(1, 480), stop(2, 600)}},
+			trainIDs:      []uint{1},
+			destinationID: 2,
+			isRunning:     runsDaily,
+			want:          []string{"train 1 day 0 from 1 at 480 to 2 at 600"},
+		},
+		{
+			name:          "train not running on the travel date",
+			routes:        map[uint][]journeyStop{1: {stop(1, 480), stop(2, 600)}},
+			trainIDs:      []uint{1},
+			destinationID: 2,
+			isRunning:     func(trainID uint, serviceDay int) bool { return serviceDay != 0 },
+			want:          nil,
+		},
+		{
+			name:          "boarding after midnight rides the service of the day before",
+			routes:        map[uint][]journeyStop{1: {stop(3, 1320), stop(1, 1500), stop(2, 1620)}},
+			trainIDs:      []uint{1},
+			destinationID: 2,
+			isRunning:     runsDaily,
+			want:          []string{"train 1 day -1 from 1 at 60 to 2 at 180"},
+		},
+		{
+			name: "same day transfer",
+			routes: map[uint][]journeyStop{
+				1: {stop(1, 480), stop(2, 600)},
+				2: {stop(2, 660), stop(3, 780)},
+			},
+			trainIDs:      []uint{1, 2},
+			destinationID: 3,
+			maxTransfer:   1,
+			isRunning:     runsDaily,
+			want:          []string{"train 1 day 0 from 1 at 480 to 2 at 600, train 2 day 0 from 2 at 660 to 3 at 780"},
+		},
+		{
+			name: "connection shorter than the connection time waits for the next day",
+			routes: map[uint][]journeyStop{
+				1: {stop(1, 480), stop(2, 600)},
+				2: {stop(2, 610), stop(3, 780)},
+			},
+			trainIDs:      []uint{1, 2},
+			destinationID: 3,
+			maxTransfer:   1,
+			isRunning:     runsDaily,
+			want:          []string{"train 1 day 0 from 1 at 480 to 2 at 600, train 2 day 1 from 2 at 2050 to 3 at 2220"},
+		},
+		{
+			name: "transfer above the maximum",
+			routes: map[uint][]journeyStop{
+				1: {stop(1, 480), stop(2, 600)},
+				2: {stop(2, 660), stop(3, 780)},
+			},
+			trainIDs:      []uint{1, 2},
+			destinationID: 3,
+			maxTransfer:   0,
+			isRunning:     runsDaily,
+			want:          nil,
+		},
+		{
+			name:          "train running the other way",
+			routes:        map[uint][]journeyStop{1: {stop(2, 480), stop(1, 600)}},
+			trainIDs:      []uint{1},
+			destinationID: 2,
+			isRunning:     runsDaily,
+			want:          nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journeys := planTrainJourneys(tt.routes, tt.trainIDs, 1, tt.destinationID, tt.maxTransfer, defaultMinConnectionTime, tt.isRunning)
+
+			var got []string
+			for _, legs := range journeys {
+				journey := ""
+				for i, leg := range legs {
+					if i > 0 {
+						journey += ", "
+					}
+					journey += fmt.Sprintf("train %d day %d from %d at %d to %d at %d", leg.TrainID, leg.ServiceDay, leg.Origin.StationID, leg.Origin.Minute, leg.Destination.StationID, leg.Destination.Minute)
+				}
+				got = append(got, journey)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planTrainJourneys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJourneyLegs(t *testing.T) {
+	leg := func(originID uint, departure int, destinationID uint, arrival int) journeyLeg {
+		return journeyLeg{Origin: journeyStop{StationID: originID, Minute: departure}, Destination: journeyStop{StationID: destinationID, Minute: arrival}}
+	}
+
+	tests := []struct {
+		name    string
+		legs    []journeyLeg
+		wantErr bool
+	}{
+		{"single leg", []journeyLeg{leg(1, 480, 2, 600)}, false},
+		{"connected legs", []journeyLeg{leg(1, 480, 2, 600), leg(2, 630, 3, 700)}, false},
+		{"legs at different stations", []journeyLeg{leg(1, 480, 2, 600), leg(3, 660, 4, 700)}, true},
+		{"connection too short", []journeyLeg{leg(1, 480, 2, 600), leg(2, 620, 3, 700)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateJourneyLegs(tt.legs, defaultMinConnectionTime); (err != nil) != tt.wantErr {
+				t.Errorf("validateJourneyLegs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
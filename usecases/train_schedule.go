@@ -0,0 +1,319 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+var trainOperatingDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+type TrainScheduleUsecase interface {
+	GetTrainSchedule(trainID uint) (dtos.TrainScheduleResponse, error)
+	UpdateTrainSchedule(trainID uint, trainScheduleInput dtos.TrainScheduleInput) (dtos.TrainScheduleResponse, error)
+	CreateTrainScheduleException(trainID uint, trainScheduleExceptionInput dtos.TrainScheduleExceptionInput) (dtos.TrainScheduleExceptionResponse, error)
+	DeleteTrainScheduleException(trainID, id uint) error
+}
+
+type trainScheduleUsecase struct {
+	trainScheduleRepo repositories.TrainScheduleRepository
+	trainRepo         repositories.TrainRepository
+}
+
+func NewTrainScheduleUsecase(trainScheduleRepo repositories.TrainScheduleRepository, trainRepo repositories.TrainRepository) TrainScheduleUsecase {
+	return &trainScheduleUsecase{trainScheduleRepo, trainRepo}
+}
+
+// GetTrainSchedule godoc
+// @Summary      Get train schedule
+// @Description  Get operating days, effective range and exceptions of a train
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Success      200 {object} dtos.TrainScheduleStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/schedule [get]
+// @Security BearerAuth
+func (u *trainScheduleUsecase) GetTrainSchedule(trainID uint) (dtos.TrainScheduleResponse, error) {
+	var trainScheduleResponse dtos.TrainScheduleResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainScheduleResponse, errors.New("Failed to get train")
+	}
+
+	trainSchedule, _ := u.trainScheduleRepo.GetTrainScheduleByTrainID(train.ID)
+	trainScheduleExceptions, err := u.trainScheduleRepo.GetTrainScheduleExceptionsByTrainID(train.ID)
+	if err != nil {
+		return trainScheduleResponse, err
+	}
+
+	return newTrainScheduleResponse(train.ID, trainSchedule, trainScheduleExceptions), nil
+}
+
+// UpdateTrainSchedule godoc
+// @Summary      Update train schedule
+// @Description  Set the operating days and effective range of a train
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param        request body dtos.TrainScheduleInput true "Payload Body [RAW]"
+// @Success      200 {object} dtos.TrainScheduleStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/schedule [put]
+// @Security BearerAuth
+func (u *trainScheduleUsecase) UpdateTrainSchedule(trainID uint, trainScheduleInput dtos.TrainScheduleInput) (dtos.TrainScheduleResponse, error) {
+	var trainScheduleResponse dtos.TrainScheduleResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainScheduleResponse, errors.New("Failed to get train")
+	}
+
+	if len(trainScheduleInput.OperatingDays) < 1 {
+		return trainScheduleResponse, errors.New("Operating days must be at least 1 day")
+	}
+
+	var operatingDays []string
+	for _, operatingDay := range trainScheduleInput.OperatingDays {
+		operatingDay = strings.ToLower(strings.TrimSpace(operatingDay))
+		if !isTrainOperatingDay(operatingDay) {
+			return trainScheduleResponse, errors.New("Operating day must be a day of the week")
+		}
+		operatingDays = append(operatingDays, operatingDay)
+	}
+
+	trainSchedule, _ := u.trainScheduleRepo.GetTrainScheduleByTrainID(train.ID)
+	trainSchedule.TrainID = train.ID
+	trainSchedule.OperatingDays = strings.Join(operatingDays, ",")
+	trainSchedule.EffectiveFrom = nil
+	trainSchedule.EffectiveUntil = nil
+
+	if trainScheduleInput.EffectiveFrom != "" {
+		effectiveFrom, err := helpers.FormatStringToDate(trainScheduleInput.EffectiveFrom)
+		if err != nil {
+			return trainScheduleResponse, errors.New("Failed to parse effective from")
+		}
+		trainSchedule.EffectiveFrom = &effectiveFrom
+	}
+	if trainScheduleInput.EffectiveUntil != "" {
+		effectiveUntil, err := helpers.FormatStringToDate(trainScheduleInput.EffectiveUntil)
+		if err != nil {
+			return trainScheduleResponse, errors.New("Failed to parse effective until")
+		}
+		trainSchedule.EffectiveUntil = &effectiveUntil
+	}
+	if trainSchedule.EffectiveFrom != nil && trainSchedule.EffectiveUntil != nil && trainSchedule.EffectiveUntil.Before(*trainSchedule.EffectiveFrom) {
+		return trainScheduleResponse, errors.New("Effective until must be after effective from")
+	}
+
+	trainSchedule, err = u.trainScheduleRepo.SaveTrainSchedule(trainSchedule)
+	if err != nil {
+		return trainScheduleResponse, err
+	}
+
+	trainScheduleExceptions, err := u.trainScheduleRepo.GetTrainScheduleExceptionsByTrainID(train.ID)
+	if err != nil {
+		return trainScheduleResponse, err
+	}
+
+	return newTrainScheduleResponse(train.ID, trainSchedule, trainScheduleExceptions), nil
+}
+
+// CreateTrainScheduleException godoc
+// @Summary      Create train schedule exception
+// @Description  Cancel or add a train service on a specific date, e.g. a holiday
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param        request body dtos.TrainScheduleExceptionInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TrainScheduleExceptionCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/schedule/exception [post]
+// @Security BearerAuth
+func (u *trainScheduleUsecase) CreateTrainScheduleException(trainID uint, trainScheduleExceptionInput dtos.TrainScheduleExceptionInput) (dtos.TrainScheduleExceptionResponse, error) {
+	var trainScheduleExceptionResponse dtos.TrainScheduleExceptionResponse
+
+	train, err := u.trainRepo.GetTrainByID2(trainID)
+	if err != nil {
+		return trainScheduleExceptionResponse, errors.New("Failed to get train")
+	}
+
+	date, err := helpers.FormatStringToDate(trainScheduleExceptionInput.Date)
+	if err != nil {
+		return trainScheduleExceptionResponse, errors.New("Failed to parse date")
+	}
+
+	getTrainScheduleException, _ := u.trainScheduleRepo.GetTrainScheduleExceptionByTrainIDAndDate(train.ID, trainScheduleExceptionInput.Date)
+	if getTrainScheduleException.ID > 0 {
+		return trainScheduleExceptionResponse, errors.New("Train schedule exception already exists on this date")
+	}
+
+	createTrainScheduleException := models.TrainScheduleException{
+		TrainID:   train.ID,
+		Date:      date,
+		IsRunning: trainScheduleExceptionInput.IsRunning,
+		Note:      trainScheduleExceptionInput.Note,
+	}
+
+	createTrainScheduleException, err = u.trainScheduleRepo.CreateTrainScheduleException(createTrainScheduleException)
+	if err != nil {
+		return trainScheduleExceptionResponse, err
+	}
+
+	trainScheduleExceptionResponse = dtos.TrainScheduleExceptionResponse{
+		TrainScheduleExceptionID: createTrainScheduleException.ID,
+		Date:                     helpers.FormatDateToYMD(&createTrainScheduleException.Date),
+		IsRunning:                createTrainScheduleException.IsRunning,
+		Note:                     createTrainScheduleException.Note,
+	}
+	return trainScheduleExceptionResponse, nil
+}
+
+// DeleteTrainScheduleException godoc
+// @Summary      Delete train schedule exception
+// @Description  Delete train schedule exception
+// @Tags         Admin - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train"
+// @Param exception_id path integer true "ID train schedule exception"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /admin/train/{id}/schedule/exception/{exception_id} [delete]
+// @Security BearerAuth
+func (u *trainScheduleUsecase) DeleteTrainScheduleException(trainID, id uint) error {
+	trainScheduleException, err := u.trainScheduleRepo.GetTrainScheduleExceptionByID(id)
+	if err != nil || trainScheduleException.TrainID != trainID {
+		return errors.New("Failed to get train schedule exception")
+	}
+	return u.trainScheduleRepo.DeleteTrainScheduleException(trainScheduleException)
+}
+
+func newTrainScheduleResponse(trainID uint, trainSchedule models.TrainSchedule, trainScheduleExceptions []models.TrainScheduleException) dtos.TrainScheduleResponse {
+	// Trains without a schedule run every day
+	operatingDays := trainOperatingDays
+	if trainSchedule.ID > 0 {
+		operatingDays = strings.Split(trainSchedule.OperatingDays, ",")
+	}
+
+	trainScheduleExceptionResponses := make([]dtos.TrainScheduleExceptionResponse, 0)
+	for _, trainScheduleException := range trainScheduleExceptions {
+		trainScheduleExceptionResponses = append(trainScheduleExceptionResponses, dtos.TrainScheduleExceptionResponse{
+			TrainScheduleExceptionID: trainScheduleException.ID,
+			Date:                     helpers.FormatDateToYMD(&trainScheduleException.Date),
+			IsRunning:                trainScheduleException.IsRunning,
+			Note:                     trainScheduleException.Note,
+		})
+	}
+
+	return dtos.TrainScheduleResponse{
+		TrainID:        trainID,
+		OperatingDays:  operatingDays,
+		EffectiveFrom:  helpers.FormatDateToYMD(trainSchedule.EffectiveFrom),
+		EffectiveUntil: helpers.FormatDateToYMD(trainSchedule.EffectiveUntil),
+		Exceptions:     trainScheduleExceptionResponses,
+		UpdatedAt:      trainSchedule.UpdatedAt,
+	}
+}
+
+func isTrainOperatingDay(day string) bool {
+	for _, operatingDay := range trainOperatingDays {
+		if operatingDay == day {
+			return true
+		}
+	}
+	return false
+}
+
+// isTrainRunning reports whether the train runs a service departing its first
+// station on serviceDate. Exceptions take precedence over the regular schedule
+// and trains without a schedule run every day.
+func isTrainRunning(trainScheduleRepo repositories.TrainScheduleRepository, trainID uint, serviceDate time.Time) bool {
+	date := helpers.FormatDateToYMD(&serviceDate)
+
+	trainScheduleException, _ := trainScheduleRepo.GetTrainScheduleExceptionByTrainIDAndDate(trainID, date)
+	if trainScheduleException.ID > 0 {
+		return trainScheduleException.IsRunning
+	}
+
+	trainSchedule, _ := trainScheduleRepo.GetTrainScheduleByTrainID(trainID)
+	if trainSchedule.ID == 0 {
+		return true
+	}
+	if trainSchedule.EffectiveFrom != nil && date < helpers.FormatDateToYMD(trainSchedule.EffectiveFrom) {
+		return false
+	}
+	if trainSchedule.EffectiveUntil != nil && date > helpers.FormatDateToYMD(trainSchedule.EffectiveUntil) {
+		return false
+	}
+
+	weekday := strings.ToLower(serviceDate.Weekday().String())
+	for _, operatingDay := range strings.Split(trainSchedule.OperatingDays, ",") {
+		if operatingDay == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// routeDayOffsets returns how many days after departing the first station the
+// train reaches each stop. A stop earlier in the day than the previous one
+// means the train ran past midnight.
+func routeDayOffsets(route []dtos.TrainStationInput) ([]int, error) {
+	dayOffsets := make([]int, len(route))
+	dayOffset, previousMinute := 0, 0
+	for i, trainStation := range route {
+		minute, err := helpers.FormatTimeToMinutes(trainStation.ArriveTime)
+		if err != nil {
+			return nil, errors.New("Arrive time must be in HH:MM format")
+		}
+		if i > 0 && minute < previousMinute {
+			dayOffset++
+		}
+		dayOffsets[i] = dayOffset
+		previousMinute = minute
+	}
+	return dayOffsets, nil
+}
+
+// trainServiceDate returns the date the train leaves its first station for a
+// passenger boarding at the given stop on travelDate.
+func trainServiceDate(travelDate time.Time, trainStation models.TrainStation) time.Time {
+	return travelDate.AddDate(0, 0, -trainStation.DayOffset)
+}
+
+// trainStationDateTime returns when the train reaches the stop on the service
+// departing its first station on serviceDate.
+func trainStationDateTime(serviceDate time.Time, trainStation models.TrainStation) (time.Time, error) {
+	minute, err := helpers.FormatTimeToMinutes(trainStation.ArriveTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(serviceDate.Year(), serviceDate.Month(), serviceDate.Day()+trainStation.DayOffset, 0, minute, 0, 0, time.Local), nil
+}
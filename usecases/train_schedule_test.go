@@ -0,0 +1,123 @@
+package usecases
+
+import (
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeTrainScheduleRepo serves one schedule and its exceptions by date, the other methods are not used.
+type fakeTrainScheduleRepo struct {
+	repositories.TrainScheduleRepository
+	trainSchedule           models.TrainSchedule
+	trainScheduleExceptions map[string]models.TrainScheduleException
+}
+
+func (r fakeTrainScheduleRepo) GetTrainScheduleByTrainID(trainID uint) (models.TrainSchedule, error) {
+	if r.trainSchedule.ID == 0 {
+		return r.trainSchedule, errors.New("record not found")
+	}
+	return r.trainSchedule, nil
+}
+
+func (r fakeTrainScheduleRepo) GetTrainScheduleExceptionByTrainIDAndDate(trainID uint, date string) (models.TrainScheduleException, error) {
+	trainScheduleException, ok := r.trainScheduleExceptions[date]
+	if !ok {
+		return trainScheduleException, errors.New("record not found")
+	}
+	return trainScheduleException, nil
+}
+
+func TestTrainServiceDate(t *testing.T) {
+	travelDate := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		dayOffset int
+		want      time.Time
+	}{
+		{0, travelDate},
+		{1, time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)},
+		{2, time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got := trainServiceDate(travelDate, models.TrainStation{DayOffset: tt.dayOffset})
+		if !got.Equal(tt.want) {
+			t.Errorf("trainServiceDate(day offset %d) = %v, want %v", tt.dayOffset, got, tt.want)
+		}
+	}
+}
+
+func TestIsTrainRunning(t *testing.T) {
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	effectiveFrom := monday.AddDate(0, 0, 1)
+	effectiveUntil := monday.AddDate(0, 0, -1)
+	weekdays := models.TrainSchedule{Model: gorm.Model{ID: 1}, OperatingDays: "monday,tuesday,wednesday,thursday,friday"}
+
+	tests := []struct {
+		name                    string
+		trainSchedule           models.TrainSchedule
+		trainScheduleExceptions map[string]models.TrainScheduleException
+		serviceDate             time.Time
+		want                    bool
+	}{
+		{
+			name:        "no schedule runs every day",
+			serviceDate: monday,
+			want:        true,
+		},
+		{
+			name:          "operating day",
+			trainSchedule: weekdays,
+			serviceDate:   monday,
+			want:          true,
+		},
+		{
+			name:          "day off",
+			trainSchedule: weekdays,
+			serviceDate:   monday.AddDate(0, 0, -1),
+			want:          false,
+		},
+		{
+			name:          "before the schedule takes effect",
+			trainSchedule: models.TrainSchedule{Model: weekdays.Model, OperatingDays: weekdays.OperatingDays, EffectiveFrom: &effectiveFrom},
+			serviceDate:   monday,
+			want:          false,
+		},
+		{
+			name:          "after the schedule ends",
+			trainSchedule: models.TrainSchedule{Model: weekdays.Model, OperatingDays: weekdays.OperatingDays, EffectiveUntil: &effectiveUntil},
+			serviceDate:   monday,
+			want:          false,
+		},
+		{
+			name:          "exception cancels an operating day",
+			trainSchedule: weekdays,
+			trainScheduleExceptions: map[string]models.TrainScheduleException{
+				"2026-10-19": {Model: weekdays.Model, IsRunning: false},
+			},
+			serviceDate: monday,
+			want:        false,
+		},
+		{
+			name:          "exception adds a day off",
+			trainSchedule: weekdays,
+			trainScheduleExceptions: map[string]models.TrainScheduleException{
+				"2026-10-18": {Model: weekdays.Model, IsRunning: true},
+			},
+			serviceDate: monday.AddDate(0, 0, -1),
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trainScheduleRepo := fakeTrainScheduleRepo{trainSchedule: tt.trainSchedule, trainScheduleExceptions: tt.trainScheduleExceptions}
+			if got := isTrainRunning(trainScheduleRepo, 1, tt.serviceDate); got != tt.want {
+				t.Errorf("isTrainRunning() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"back-end-golang/configs"
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"time"
+)
+
+type TrainSeatHoldUsecase interface {
+	GetTrainSeatHolds(userID uint) ([]dtos.TrainSeatHoldResponse, error)
+	CreateTrainSeatHold(userID uint, trainSeatHoldInput dtos.TrainSeatHoldInput) (dtos.TrainSeatHoldResponse, error)
+	DeleteTrainSeatHold(userID, id uint) error
+}
+
+type trainSeatHoldUsecase struct {
+	trainSeatHoldRepo        repositories.TrainSeatHoldRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	trainCarriageRepo        repositories.TrainCarriageRepository
+	trainRepo                repositories.TrainRepository
+	trainSeatRepo            repositories.TrainSeatRepository
+	stationRepo              repositories.StationRepository
+	trainStationRepo         repositories.TrainStationRepository
+	trainScheduleRepo        repositories.TrainScheduleRepository
+	trainFareRepo            repositories.TrainFareRepository
+}
+
+func NewTrainSeatHoldUsecase(trainSeatHoldRepo repositories.TrainSeatHoldRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainRepo repositories.TrainRepository, trainSeatRepo repositories.TrainSeatRepository, stationRepo repositories.StationRepository, trainStationRepo repositories.TrainStationRepository, trainScheduleRepo repositories.TrainScheduleRepository, trainFareRepo repositories.TrainFareRepository) TrainSeatHoldUsecase {
+	return &trainSeatHoldUsecase{trainSeatHoldRepo, ticketTravelerDetailRepo, trainCarriageRepo, trainRepo, trainSeatRepo, stationRepo, trainStationRepo, trainScheduleRepo, trainFareRepo}
+}
+
+// GetTrainSeatHolds godoc
+// @Summary      Get train seat holds
+// @Description  Get the active seat holds of the user
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} dtos.GetAllTrainSeatHoldStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/seat-hold [get]
+// @Security BearerAuth
+func (u *trainSeatHoldUsecase) GetTrainSeatHolds(userID uint) ([]dtos.TrainSeatHoldResponse, error) {
+	trainSeatHolds, err := u.trainSeatHoldRepo.GetTrainSeatHoldsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	trainSeatHoldResponses := make([]dtos.TrainSeatHoldResponse, 0)
+	for _, trainSeatHold := range trainSeatHolds {
+		trainSeatHoldResponse, err := u.newTrainSeatHoldResponse(trainSeatHold)
+		if err != nil {
+			return trainSeatHoldResponses, err
+		}
+		trainSeatHoldResponses = append(trainSeatHoldResponses, trainSeatHoldResponse)
+	}
+	return trainSeatHoldResponses, nil
+}
+
+// CreateTrainSeatHold godoc
+// @Summary      Hold a train seat
+// @Description  Reserve a seat on a segment for a few minutes while filling traveler details
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TrainSeatHoldInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TrainSeatHoldCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/seat-hold [post]
+// @Security BearerAuth
+func (u *trainSeatHoldUsecase) CreateTrainSeatHold(userID uint, trainSeatHoldInput dtos.TrainSeatHoldInput) (dtos.TrainSeatHoldResponse, error) {
+	var trainSeatHoldResponse dtos.TrainSeatHoldResponse
+
+	if trainSeatHoldInput.TrainCarriageID < 1 || trainSeatHoldInput.TrainSeatID < 1 || trainSeatHoldInput.StationOriginID < 1 || trainSeatHoldInput.StationDestinationID < 1 || trainSeatHoldInput.Date == "" {
+		return trainSeatHoldResponse, errors.New("Failed to create train seat hold")
+	}
+
+	dateDepartureParse, err := helpers.FormatStringToDate(trainSeatHoldInput.Date)
+	if err != nil {
+		return trainSeatHoldResponse, errors.New("Failed to parse date")
+	}
+
+	getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID2(trainSeatHoldInput.TrainCarriageID)
+	if err != nil {
+		return trainSeatHoldResponse, errors.New("Failed to get train carriage id")
+	}
+
+	getTrain, err := u.trainRepo.GetTrainByID2(getTrainCarriage.TrainID)
+	if err != nil || getTrain.Status != "available" {
+		return trainSeatHoldResponse, errors.New("Failed to get train")
+	}
+
+	getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, trainSeatHoldInput.StationOriginID, trainSeatHoldInput.StationDestinationID)
+	if err != nil {
+		return trainSeatHoldResponse, errors.New("Failed to get train station")
+	}
+	if !isForwardRoute(getTrainStation, trainSeatHoldInput.StationOriginID, trainSeatHoldInput.StationDestinationID) {
+		return trainSeatHoldResponse, errors.New("Train does not travel from station origin to station destination")
+	}
+
+	trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, trainSeatHoldInput.StationOriginID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+	trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, trainSeatHoldInput.StationDestinationID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+
+	getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(trainSeatHoldInput.TrainSeatID)
+	if err != nil {
+		return trainSeatHoldResponse, errors.New("Failed to get train seat id")
+	}
+	if !isTrainSeatInCarriage(getTrainSeat, getTrainCarriage) {
+		return trainSeatHoldResponse, errors.New("Train seat is not available in this train carriage")
+	}
+
+	serviceDate := trainServiceDate(dateDepartureParse, trainStationOrigin)
+	if !isTrainRunning(u.trainScheduleRepo, getTrain.ID, serviceDate) {
+		return trainSeatHoldResponse, errors.New("Train does not run on this date")
+	}
+
+	_ = u.trainSeatHoldRepo.DeleteExpiredTrainSeatHolds()
+
+	tx := u.trainSeatHoldRepo.BeginTransaction()
+	defer tx.Rollback()
+	trainSeatHoldRepo := u.trainSeatHoldRepo.WithTx(tx)
+
+	// Lock the seat so a concurrent hold or order waits until this hold is checked and created
+	if _, err := u.trainSeatRepo.WithTx(tx).GetTrainSeatByIDForUpdate(getTrainSeat.ID); err != nil {
+		return trainSeatHoldResponse, errors.New("Failed to get train seat id")
+	}
+
+	trainOrder, _ := u.ticketTravelerDetailRepo.WithTx(tx).GetTicketTravelerDetailByTrainSeatIDSegment(getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+	if trainOrder.ID > 0 {
+		return trainSeatHoldResponse, errors.New("Train seat is not available")
+	}
+
+	trainSeatHold, _ := trainSeatHoldRepo.GetTrainSeatHoldBySegment(userID, getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate), trainStationOrigin.Sequence, trainStationDestination.Sequence)
+	if trainSeatHold.ID > 0 {
+		return trainSeatHoldResponse, errors.New("Train seat is held by another user")
+	}
+
+	// Holding the same seat again extends the hold instead of stacking holds
+	err = trainSeatHoldRepo.DeleteTrainSeatHoldsByUserIDAndTrainSeatID(userID, getTrain.ID, getTrainSeat.ID, helpers.FormatDateToYMD(&serviceDate))
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+
+	createTrainSeatHold := models.TrainSeatHold{
+		UserID:               userID,
+		TrainID:              getTrain.ID,
+		TrainCarriageID:      getTrainCarriage.ID,
+		TrainSeatID:          getTrainSeat.ID,
+		StationOriginID:      trainStationOrigin.StationID,
+		StationDestinationID: trainStationDestination.StationID,
+		OriginSequence:       trainStationOrigin.Sequence,
+		DestinationSequence:  trainStationDestination.Sequence,
+		DateOfDeparture:      dateDepartureParse,
+		ServiceDate:          serviceDate,
+		ExpiredAt:            time.Now().Add(time.Duration(configs.EnvSeatHoldMinutes()) * time.Minute),
+	}
+
+	createTrainSeatHold, err = trainSeatHoldRepo.CreateTrainSeatHold(createTrainSeatHold)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return trainSeatHoldResponse, err
+	}
+
+	return u.newTrainSeatHoldResponse(createTrainSeatHold)
+}
+
+// DeleteTrainSeatHold godoc
+// @Summary      Release a train seat hold
+// @Description  Release a train seat hold
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train seat hold"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/seat-hold/{id} [delete]
+// @Security BearerAuth
+func (u *trainSeatHoldUsecase) DeleteTrainSeatHold(userID, id uint) error {
+	trainSeatHold, err := u.trainSeatHoldRepo.GetTrainSeatHoldByID(id)
+	if err != nil || trainSeatHold.UserID != userID {
+		return errors.New("Failed to get train seat hold")
+	}
+	return u.trainSeatHoldRepo.DeleteTrainSeatHold(trainSeatHold)
+}
+
+func (u *trainSeatHoldUsecase) newTrainSeatHoldResponse(trainSeatHold models.TrainSeatHold) (dtos.TrainSeatHoldResponse, error) {
+	var trainSeatHoldResponse dtos.TrainSeatHoldResponse
+
+	getTrain, err := u.trainRepo.GetTrainByID2(trainSeatHold.TrainID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+	getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID(trainSeatHold.TrainCarriageID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+	getTrainSeat, err := u.trainSeatRepo.GetTrainSeatByID(trainSeatHold.TrainSeatID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+	getStationOrigin, err := u.stationRepo.GetStationByID(trainSeatHold.StationOriginID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+	getStationDestination, err := u.stationRepo.GetStationByID(trainSeatHold.StationDestinationID)
+	if err != nil {
+		return trainSeatHoldResponse, err
+	}
+	trainStationOrigin, _ := u.trainStationRepo.GetTrainStationByTrainIDStationID(trainSeatHold.TrainID, trainSeatHold.StationOriginID)
+	trainStationDestination, _ := u.trainStationRepo.GetTrainStationByTrainIDStationID(trainSeatHold.TrainID, trainSeatHold.StationDestinationID)
+	trainFareQuote := quoteTrainFare(u.trainFareRepo, getTrainCarriage, trainStationOrigin, trainStationDestination, trainSeatHold.ServiceDate, trainSeatHold.CreatedAt, passengerTypeAdult)
+
+	trainSeatHoldResponse = dtos.TrainSeatHoldResponse{
+		TrainSeatHoldID: trainSeatHold.ID,
+		Train: dtos.TrainResponsesSimply{
+			TrainID:         getTrain.ID,
+			CodeTrain:       getTrain.CodeTrain,
+			Name:            getTrain.Name,
+			Class:           getTrainCarriage.Class,
+			TrainPrice:      trainFareQuote.Total,
+			TrainCarriageID: getTrainCarriage.ID,
+			TrainCarriage:   getTrainCarriage.Name,
+			TrainSeatID:     getTrainSeat.ID,
+			TrainSeat:       getTrainSeat.Name,
+		},
+		StationOrigin: dtos.StationResponseSimply{
+			StationID:  getStationOrigin.ID,
+			Origin:     getStationOrigin.Origin,
+			Name:       getStationOrigin.Name,
+			Initial:    getStationOrigin.Initial,
+			ArriveTime: trainStationOrigin.ArriveTime,
+		},
+		StationDestination: dtos.StationResponseSimply{
+			StationID:  getStationDestination.ID,
+			Origin:     getStationDestination.Origin,
+			Name:       getStationDestination.Name,
+			Initial:    getStationDestination.Initial,
+			ArriveTime: trainStationDestination.ArriveTime,
+		},
+		Date:      helpers.FormatDateToYMD(&trainSeatHold.DateOfDeparture),
+		ExpiredAt: trainSeatHold.ExpiredAt,
+	}
+	return trainSeatHoldResponse, nil
+}
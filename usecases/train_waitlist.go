@@ -0,0 +1,513 @@
+package usecases
+
+import (
+	"back-end-golang/configs"
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"back-end-golang/models"
+	"back-end-golang/repositories"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// trainWaitlistOfferTemplateID is the notification template telling a waitlisted user a seat is held for them.
+const trainWaitlistOfferTemplateID = 10
+
+// TrainWaitlistUsecase queues users for a sold-out class of a train. A seat that becomes free is held for
+// the first user in the queue it fits, who is notified and has until the hold expires to order it.
+type TrainWaitlistUsecase interface {
+	WithTx(tx *gorm.DB) TrainWaitlistUsecase
+	GetTrainWaitlists(userID uint) ([]dtos.TrainWaitlistResponse, error)
+	CreateTrainWaitlist(userID uint, trainWaitlistInput dtos.TrainWaitlistInput) (dtos.TrainWaitlistResponse, error)
+	DeleteTrainWaitlist(userID, id uint) error
+	OfferReleasedTrainSeats(ticketTravelerDetails []models.TicketTravelerDetail) (int, error)
+	ProcessTrainWaitlists() (int, error)
+}
+
+type trainWaitlistUsecase struct {
+	trainWaitlistRepo        repositories.TrainWaitlistRepository
+	trainSeatHoldRepo        repositories.TrainSeatHoldRepository
+	ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository
+	trainCarriageRepo        repositories.TrainCarriageRepository
+	trainRepo                repositories.TrainRepository
+	trainSeatRepo            repositories.TrainSeatRepository
+	stationRepo              repositories.StationRepository
+	trainStationRepo         repositories.TrainStationRepository
+	trainScheduleRepo        repositories.TrainScheduleRepository
+	notificationRepo         repositories.NotificationRepository
+	templateMessageRepo      repositories.TemplateMessageRepository
+	tx                       *gorm.DB
+}
+
+func NewTrainWaitlistUsecase(trainWaitlistRepo repositories.TrainWaitlistRepository, trainSeatHoldRepo repositories.TrainSeatHoldRepository, ticketTravelerDetailRepo repositories.TicketTravelerDetailRepository, trainCarriageRepo repositories.TrainCarriageRepository, trainRepo repositories.TrainRepository, trainSeatRepo repositories.TrainSeatRepository, stationRepo repositories.StationRepository, trainStationRepo repositories.TrainStationRepository, trainScheduleRepo repositories.TrainScheduleRepository, notificationRepo repositories.NotificationRepository, templateMessageRepo repositories.TemplateMessageRepository) TrainWaitlistUsecase {
+	return &trainWaitlistUsecase{trainWaitlistRepo, trainSeatHoldRepo, ticketTravelerDetailRepo, trainCarriageRepo, trainRepo, trainSeatRepo, stationRepo, trainStationRepo, trainScheduleRepo, notificationRepo, templateMessageRepo, nil}
+}
+
+func (u *trainWaitlistUsecase) WithTx(tx *gorm.DB) TrainWaitlistUsecase {
+	return &trainWaitlistUsecase{
+		trainWaitlistRepo:        u.trainWaitlistRepo.WithTx(tx),
+		trainSeatHoldRepo:        u.trainSeatHoldRepo.WithTx(tx),
+		ticketTravelerDetailRepo: u.ticketTravelerDetailRepo.WithTx(tx),
+		trainCarriageRepo:        u.trainCarriageRepo,
+		trainRepo:                u.trainRepo,
+		trainSeatRepo:            u.trainSeatRepo.WithTx(tx),
+		stationRepo:              u.stationRepo,
+		trainStationRepo:         u.trainStationRepo,
+		trainScheduleRepo:        u.trainScheduleRepo,
+		notificationRepo:         u.notificationRepo.WithTx(tx),
+		templateMessageRepo:      u.templateMessageRepo,
+		tx:                       tx,
+	}
+}
+
+// GetTrainWaitlists godoc
+// @Summary      Get train waitlists
+// @Description  Get the waitlist entries of the user with their place in the queue
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} dtos.GetAllTrainWaitlistStatusOKResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/waitlist [get]
+// @Security BearerAuth
+func (u *trainWaitlistUsecase) GetTrainWaitlists(userID uint) ([]dtos.TrainWaitlistResponse, error) {
+	trainWaitlists, err := u.trainWaitlistRepo.GetTrainWaitlistsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	trainWaitlistResponses := make([]dtos.TrainWaitlistResponse, 0)
+	for _, trainWaitlist := range trainWaitlists {
+		trainWaitlistResponse, err := u.newTrainWaitlistResponse(trainWaitlist)
+		if err != nil {
+			return trainWaitlistResponses, err
+		}
+		trainWaitlistResponses = append(trainWaitlistResponses, trainWaitlistResponse)
+	}
+	return trainWaitlistResponses, nil
+}
+
+// CreateTrainWaitlist godoc
+// @Summary      Join a train waitlist
+// @Description  Queue for a sold-out class of a train on a segment and date, a freed seat is held for the first user in the queue
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TrainWaitlistInput true "Payload Body [RAW]"
+// @Success      201 {object} dtos.TrainWaitlistCreatedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/waitlist [post]
+// @Security BearerAuth
+func (u *trainWaitlistUsecase) CreateTrainWaitlist(userID uint, trainWaitlistInput dtos.TrainWaitlistInput) (dtos.TrainWaitlistResponse, error) {
+	var trainWaitlistResponse dtos.TrainWaitlistResponse
+
+	if trainWaitlistInput.TrainID < 1 || trainWaitlistInput.Class == "" || trainWaitlistInput.StationOriginID < 1 || trainWaitlistInput.StationDestinationID < 1 || trainWaitlistInput.Date == "" {
+		return trainWaitlistResponse, errors.New("Failed to create train waitlist")
+	}
+
+	dateDepartureParse, err := helpers.FormatStringToDate(trainWaitlistInput.Date)
+	if err != nil {
+		return trainWaitlistResponse, errors.New("Failed to parse date")
+	}
+	if time.Now().Format("2006-01-02") > trainWaitlistInput.Date {
+		return trainWaitlistResponse, errors.New("Departure date must not be in the past")
+	}
+
+	getTrain, err := u.trainRepo.GetTrainByID2(trainWaitlistInput.TrainID)
+	if err != nil || getTrain.Status != "available" {
+		return trainWaitlistResponse, errors.New("Failed to get train")
+	}
+
+	getTrainCarriages, err := u.trainCarriageRepo.GetTrainCarriageByID3(getTrain.ID, trainWaitlistInput.Class)
+	if err != nil || len(getTrainCarriages) == 0 {
+		return trainWaitlistResponse, errors.New("Train has no carriage of this class")
+	}
+
+	getTrainStation, err := u.trainRepo.SearchTrainAvailable(getTrain.ID, trainWaitlistInput.StationOriginID, trainWaitlistInput.StationDestinationID)
+	if err != nil {
+		return trainWaitlistResponse, errors.New("Failed to get train station")
+	}
+	if !isForwardRoute(getTrainStation, trainWaitlistInput.StationOriginID, trainWaitlistInput.StationDestinationID) {
+		return trainWaitlistResponse, errors.New("Train does not travel from station origin to station destination")
+	}
+
+	trainStationOrigin, err := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, trainWaitlistInput.StationOriginID)
+	if err != nil {
+		return trainWaitlistResponse, err
+	}
+	trainStationDestination, err := u.trainStationRepo.GetTrainStationByTrainIDStationID2(getTrain.ID, trainWaitlistInput.StationDestinationID)
+	if err != nil {
+		return trainWaitlistResponse, err
+	}
+
+	serviceDate := trainServiceDate(dateDepartureParse, trainStationOrigin)
+	if !isTrainRunning(u.trainScheduleRepo, getTrain.ID, serviceDate) {
+		return trainWaitlistResponse, errors.New("Train does not run on this date")
+	}
+
+	createTrainWaitlist := models.TrainWaitlist{
+		UserID:               userID,
+		TrainID:              getTrain.ID,
+		Class:                getTrainCarriages[0].Class,
+		StationOriginID:      trainStationOrigin.StationID,
+		StationDestinationID: trainStationDestination.StationID,
+		OriginSequence:       trainStationOrigin.Sequence,
+		DestinationSequence:  trainStationDestination.Sequence,
+		DateOfDeparture:      dateDepartureParse,
+		ServiceDate:          serviceDate,
+		Status:               "waiting",
+	}
+
+	trainWaitlist, _ := u.trainWaitlistRepo.GetActiveTrainWaitlist(userID, getTrain.ID, createTrainWaitlist.Class, helpers.FormatDateToYMD(&serviceDate), createTrainWaitlist.OriginSequence, createTrainWaitlist.DestinationSequence)
+	if trainWaitlist.ID > 0 {
+		return trainWaitlistResponse, errors.New("Already on the waitlist of this train")
+	}
+
+	// Only a sold-out class can be waited for, otherwise the seat should just be booked
+	_, _, found := u.findFreeTrainSeat(createTrainWaitlist)
+	if found {
+		return trainWaitlistResponse, errors.New("Seats are still available in this class")
+	}
+
+	createTrainWaitlist, err = u.trainWaitlistRepo.CreateTrainWaitlist(createTrainWaitlist)
+	if err != nil {
+		return trainWaitlistResponse, err
+	}
+
+	return u.newTrainWaitlistResponse(createTrainWaitlist)
+}
+
+// DeleteTrainWaitlist godoc
+// @Summary      Leave a train waitlist
+// @Description  Leave a train waitlist, a seat offered to the user goes to the next user in the queue
+// @Tags         User - Train
+// @Accept       json
+// @Produce      json
+// @Param id path integer true "ID train waitlist"
+// @Success      200 {object} dtos.StatusOKDeletedResponse
+// @Failure      400 {object} dtos.BadRequestResponse
+// @Failure      401 {object} dtos.UnauthorizedResponse
+// @Failure      403 {object} dtos.ForbiddenResponse
+// @Failure      404 {object} dtos.NotFoundResponse
+// @Failure      500 {object} dtos.InternalServerErrorResponse
+// @Router       /user/train/waitlist/{id} [delete]
+// @Security BearerAuth
+func (u *trainWaitlistUsecase) DeleteTrainWaitlist(userID, id uint) error {
+	trainWaitlist, err := u.trainWaitlistRepo.GetTrainWaitlistByID(id)
+	if err != nil || trainWaitlist.UserID != userID {
+		return errors.New("Failed to get train waitlist")
+	}
+	if trainWaitlist.Status != "waiting" && trainWaitlist.Status != "offered" {
+		return errors.New("Train waitlist is already " + trainWaitlist.Status)
+	}
+
+	wasOffered := trainWaitlist.Status == "offered"
+	if wasOffered {
+		_ = u.trainSeatHoldRepo.DeleteTrainSeatHoldsByUserIDAndTrainSeatID(userID, trainWaitlist.TrainID, trainWaitlist.TrainSeatID, helpers.FormatDateToYMD(&trainWaitlist.ServiceDate))
+	}
+
+	trainWaitlist.Status = "canceled"
+	_, err = u.trainWaitlistRepo.UpdateTrainWaitlist(trainWaitlist)
+	if err != nil {
+		return err
+	}
+
+	// The seat held for the user goes to the next user in the queue
+	if wasOffered {
+		_, _ = u.offerTrainSeats(trainWaitlist.TrainID, trainWaitlist.Class, trainWaitlist.ServiceDate)
+	}
+	return nil
+}
+
+// OfferReleasedTrainSeats offers the seats of canceled or refunded tickets to the users waiting for the
+// same class of the train on the same service date, and returns how many offers were made.
+func (u *trainWaitlistUsecase) OfferReleasedTrainSeats(ticketTravelerDetails []models.TicketTravelerDetail) (int, error) {
+	offered := 0
+	done := make(map[string]bool)
+	for _, ticketTravelerDetail := range ticketTravelerDetails {
+		// Infants ride on the seat of their adult and release nothing of their own
+		if ticketTravelerDetail.AccompaniedByID > 0 {
+			continue
+		}
+		getTrainCarriage, err := u.trainCarriageRepo.GetTrainCarriageByID(ticketTravelerDetail.TrainCarriageID)
+		if err != nil {
+			continue
+		}
+		serviceDate := ticketTravelerDetail.DateOfDeparture
+		if ticketTravelerDetail.ServiceDate != nil {
+			serviceDate = *ticketTravelerDetail.ServiceDate
+		}
+
+		key := fmt.Sprintf("%d|%s|%s", ticketTravelerDetail.TrainID, getTrainCarriage.Class, helpers.FormatDateToYMD(&serviceDate))
+		if done[key] {
+			continue
+		}
+		done[key] = true
+
+		count, err := u.offerTrainSeats(ticketTravelerDetail.TrainID, getTrainCarriage.Class, serviceDate)
+		offered += count
+		if err != nil {
+			return offered, err
+		}
+	}
+	return offered, nil
+}
+
+// ProcessTrainWaitlists settles the offers and hands free seats to the queue. An offered entry is booked
+// once its seat is sold and expires with its hold otherwise, waiting entries for a date that has passed
+// expire, and every other waiting entry gets a seat when one is free on its segment. It returns how many
+// offers were made.
+func (u *trainWaitlistUsecase) ProcessTrainWaitlists() (int, error) {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	offeredTrainWaitlists, err := u.trainWaitlistRepo.GetOfferedTrainWaitlists()
+	if err != nil {
+		return 0, err
+	}
+	for _, trainWaitlist := range offeredTrainWaitlists {
+		ticketTravelerDetail, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatIDSegment(trainWaitlist.TrainID, trainWaitlist.TrainSeatID, helpers.FormatDateToYMD(&trainWaitlist.ServiceDate), trainWaitlist.OriginSequence, trainWaitlist.DestinationSequence)
+		switch {
+		case ticketTravelerDetail.ID > 0:
+			// The hold kept the seat for the user, so a ticket on it is theirs
+			trainWaitlist.Status = "booked"
+		case trainWaitlist.OfferExpiredAt != nil && !trainWaitlist.OfferExpiredAt.After(now):
+			trainWaitlist.Status = "expired"
+		default:
+			continue
+		}
+		_, err = u.trainWaitlistRepo.UpdateTrainWaitlist(trainWaitlist)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	waitingTrainWaitlists, err := u.trainWaitlistRepo.GetWaitingTrainWaitlists()
+	if err != nil {
+		return 0, err
+	}
+	offered := 0
+	for _, trainWaitlist := range waitingTrainWaitlists {
+		if helpers.FormatDateToYMD(&trainWaitlist.DateOfDeparture) < today {
+			trainWaitlist.Status = "expired"
+			_, err = u.trainWaitlistRepo.UpdateTrainWaitlist(trainWaitlist)
+			if err != nil {
+				return offered, err
+			}
+			continue
+		}
+
+		ok, err := u.offerTrainWaitlist(trainWaitlist)
+		if err != nil {
+			return offered, err
+		}
+		if ok {
+			offered++
+		}
+	}
+	return offered, nil
+}
+
+// offerTrainSeats walks the queue of a class of a train on a service date and offers a seat to every
+// entry one is free for.
+func (u *trainWaitlistUsecase) offerTrainSeats(trainID uint, class string, serviceDate time.Time) (int, error) {
+	trainWaitlists, err := u.trainWaitlistRepo.GetWaitingTrainWaitlistsByTrain(trainID, class, helpers.FormatDateToYMD(&serviceDate))
+	if err != nil {
+		return 0, err
+	}
+
+	offered := 0
+	for _, trainWaitlist := range trainWaitlists {
+		ok, err := u.offerTrainWaitlist(trainWaitlist)
+		if err != nil {
+			return offered, err
+		}
+		if ok {
+			offered++
+		}
+	}
+	return offered, nil
+}
+
+// offerTrainWaitlist holds a free seat on the segment of the entry for its user and notifies them. It
+// reports false when no seat is free. The seat stays locked from the check until the hold is saved, in the
+// caller's transaction when it gave one.
+func (u *trainWaitlistUsecase) offerTrainWaitlist(trainWaitlist models.TrainWaitlist) (bool, error) {
+	if u.tx != nil {
+		return u.offerTrainWaitlistSeat(trainWaitlist)
+	}
+
+	tx := u.trainSeatHoldRepo.BeginTransaction()
+	defer tx.Rollback()
+
+	offered, err := u.WithTx(tx).(*trainWaitlistUsecase).offerTrainWaitlistSeat(trainWaitlist)
+	if err != nil {
+		return false, err
+	}
+	return offered, tx.Commit().Error
+}
+
+func (u *trainWaitlistUsecase) offerTrainWaitlistSeat(trainWaitlist models.TrainWaitlist) (bool, error) {
+	getTrainCarriage, getTrainSeat, found := u.findFreeTrainSeat(trainWaitlist)
+	if !found {
+		return false, nil
+	}
+
+	offerExpiredAt := time.Now().Add(time.Duration(configs.EnvWaitlistOfferMinutes()) * time.Minute)
+	_, err := u.trainSeatHoldRepo.CreateTrainSeatHold(models.TrainSeatHold{
+		UserID:               trainWaitlist.UserID,
+		TrainID:              trainWaitlist.TrainID,
+		TrainCarriageID:      getTrainCarriage.ID,
+		TrainSeatID:          getTrainSeat.ID,
+		StationOriginID:      trainWaitlist.StationOriginID,
+		StationDestinationID: trainWaitlist.StationDestinationID,
+		OriginSequence:       trainWaitlist.OriginSequence,
+		DestinationSequence:  trainWaitlist.DestinationSequence,
+		DateOfDeparture:      trainWaitlist.DateOfDeparture,
+		ServiceDate:          trainWaitlist.ServiceDate,
+		ExpiredAt:            offerExpiredAt,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	trainWaitlist.Status = "offered"
+	trainWaitlist.TrainCarriageID = getTrainCarriage.ID
+	trainWaitlist.TrainSeatID = getTrainSeat.ID
+	trainWaitlist.OfferExpiredAt = &offerExpiredAt
+	_, err = u.trainWaitlistRepo.UpdateTrainWaitlist(trainWaitlist)
+	if err != nil {
+		return false, err
+	}
+
+	// Notifications are only listed when their template exists, so a missing template sends nothing
+	getTemplate, _ := u.templateMessageRepo.GetTemplateMessageByID(trainWaitlistOfferTemplateID)
+	if getTemplate.ID > 0 {
+		_, err = u.notificationRepo.CreateNotification(models.Notification{
+			UserID:          trainWaitlist.UserID,
+			TemplateID:      trainWaitlistOfferTemplateID,
+			TrainWaitlistID: trainWaitlist.ID,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// findFreeTrainSeat looks for a seat of the waited class of the train that nobody booked or holds on the
+// segment of the entry. Each seat is locked before it is checked, which only lasts inside a transaction.
+func (u *trainWaitlistUsecase) findFreeTrainSeat(trainWaitlist models.TrainWaitlist) (models.TrainCarriage, models.TrainSeat, bool) {
+	date := helpers.FormatDateToYMD(&trainWaitlist.ServiceDate)
+
+	getTrainCarriages, err := u.trainCarriageRepo.GetTrainCarriageByID3(trainWaitlist.TrainID, trainWaitlist.Class)
+	if err != nil {
+		return models.TrainCarriage{}, models.TrainSeat{}, false
+	}
+	for _, getTrainCarriage := range getTrainCarriages {
+		var trainSeats []models.TrainSeat
+		if getTrainCarriage.SeatLayoutID > 0 {
+			trainSeats, err = u.trainCarriageRepo.GetTrainSeatsByTrainCarriageID(getTrainCarriage.ID)
+		} else {
+			trainSeats, err = u.trainCarriageRepo.GetTrainSeatsByClass(getTrainCarriage.Class)
+		}
+		if err != nil {
+			continue
+		}
+
+		for _, trainSeat := range trainSeats {
+			if !isTrainSeatInCarriage(trainSeat, getTrainCarriage) {
+				continue
+			}
+			_, err = u.trainSeatRepo.GetTrainSeatByIDForUpdate(trainSeat.ID)
+			if err != nil {
+				continue
+			}
+			trainOrder, _ := u.ticketTravelerDetailRepo.GetTicketTravelerDetailByTrainSeatIDSegment(trainWaitlist.TrainID, trainSeat.ID, date, trainWaitlist.OriginSequence, trainWaitlist.DestinationSequence)
+			if trainOrder.ID > 0 {
+				continue
+			}
+			trainSeatHold, _ := u.trainSeatHoldRepo.GetTrainSeatHoldBySegment(0, trainWaitlist.TrainID, trainSeat.ID, date, trainWaitlist.OriginSequence, trainWaitlist.DestinationSequence)
+			if trainSeatHold.ID > 0 {
+				continue
+			}
+			return getTrainCarriage, trainSeat, true
+		}
+	}
+	return models.TrainCarriage{}, models.TrainSeat{}, false
+}
+
+func (u *trainWaitlistUsecase) newTrainWaitlistResponse(trainWaitlist models.TrainWaitlist) (dtos.TrainWaitlistResponse, error) {
+	var trainWaitlistResponse dtos.TrainWaitlistResponse
+
+	getTrain, err := u.trainRepo.GetTrainByID(trainWaitlist.TrainID)
+	if err != nil {
+		return trainWaitlistResponse, err
+	}
+	getStationOrigin, err := u.stationRepo.GetStationByID(trainWaitlist.StationOriginID)
+	if err != nil {
+		return trainWaitlistResponse, err
+	}
+	getStationDestination, err := u.stationRepo.GetStationByID(trainWaitlist.StationDestinationID)
+	if err != nil {
+		return trainWaitlistResponse, err
+	}
+	trainStationOrigin, _ := u.trainStationRepo.GetTrainStationByTrainIDStationID(trainWaitlist.TrainID, trainWaitlist.StationOriginID)
+	trainStationDestination, _ := u.trainStationRepo.GetTrainStationByTrainIDStationID(trainWaitlist.TrainID, trainWaitlist.StationDestinationID)
+
+	trainWaitlistResponse = dtos.TrainWaitlistResponse{
+		TrainWaitlistID: trainWaitlist.ID,
+		Train: dtos.TrainResponsesSimply{
+			TrainID:   getTrain.ID,
+			CodeTrain: getTrain.CodeTrain,
+			Name:      getTrain.Name,
+			Class:     trainWaitlist.Class,
+		},
+		StationOrigin: dtos.StationResponseSimply{
+			StationID:  getStationOrigin.ID,
+			Origin:     getStationOrigin.Origin,
+			Name:       getStationOrigin.Name,
+			Initial:    getStationOrigin.Initial,
+			ArriveTime: trainStationOrigin.ArriveTime,
+		},
+		StationDestination: dtos.StationResponseSimply{
+			StationID:  getStationDestination.ID,
+			Origin:     getStationDestination.Origin,
+			Name:       getStationDestination.Name,
+			Initial:    getStationDestination.Initial,
+			ArriveTime: trainStationDestination.ArriveTime,
+		},
+		Date:      helpers.FormatDateToYMD(&trainWaitlist.DateOfDeparture),
+		Status:    trainWaitlist.Status,
+		CreatedAt: trainWaitlist.CreatedAt,
+	}
+
+	switch trainWaitlist.Status {
+	case "waiting":
+		trainWaitlistResponse.Position, err = u.trainWaitlistRepo.CountWaitingTrainWaitlistsBefore(trainWaitlist)
+		if err != nil {
+			return trainWaitlistResponse, err
+		}
+	case "offered":
+		// The held seat is what the user orders with
+		getTrainCarriage, _ := u.trainCarriageRepo.GetTrainCarriageByID(trainWaitlist.TrainCarriageID)
+		getTrainSeat, _ := u.trainSeatRepo.GetTrainSeatByID(trainWaitlist.TrainSeatID)
+		trainWaitlistResponse.Train.TrainCarriageID = getTrainCarriage.ID
+		trainWaitlistResponse.Train.TrainCarriage = getTrainCarriage.Name
+		trainWaitlistResponse.Train.TrainSeatID = getTrainSeat.ID
+		trainWaitlistResponse.Train.TrainSeat = getTrainSeat.Name
+		trainWaitlistResponse.OfferExpiredAt = trainWaitlist.OfferExpiredAt
+	}
+	return trainWaitlistResponse, nil
+}
@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"back-end-golang/dtos"
+	"back-end-golang/helpers"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	travelerIDTypeKTP      = "ktp"
+	travelerIDTypePassport = "passport"
+	travelerIDTypeNone     = "none"
+
+	// Ages are counted on the travel date: infants are under 3, children under 12 and seniors 60 or older
+	infantMaxAge = 3
+	childMaxAge  = 12
+	seniorMinAge = 60
+)
+
+// travelerIdentity is a traveler whose document and passenger type passed checkTravelerIdentity.
+type travelerIdentity struct {
+	IDType        string
+	IDNumber      string
+	BirthDate     *time.Time
+	PassengerType string
+}
+
+// checkTravelerIdentity validates the document of a traveler and settles the passenger type. The type is
+// taken from the birth date when it is not given, and must agree with the age on travelDate when both are.
+// Children and infants always need a birth date, every traveler but an infant needs a KTP or a passport.
+func checkTravelerIdentity(travelerDetail dtos.TravelerDetailInput, travelDate time.Time) (travelerIdentity, error) {
+	identity := travelerIdentity{
+		IDType:        normalizeTravelerIDType(travelerDetail.IDType, travelerDetail.IDCardNumber),
+		IDNumber:      strings.ToUpper(strings.TrimSpace(travelerDetail.IDCardNumber)),
+		PassengerType: strings.ToLower(travelerDetail.PassengerType),
+	}
+
+	err := validateTravelerDocument(identity.IDType, identity.IDNumber)
+	if err != nil {
+		return identity, err
+	}
+
+	if travelerDetail.BirthDate != "" {
+		birthDate, err := helpers.FormatStringToDate(travelerDetail.BirthDate)
+		if err != nil || birthDate.After(travelDate) {
+			return identity, errors.New("Birth date invalid")
+		}
+		identity.BirthDate = &birthDate
+	}
+
+	switch identity.PassengerType {
+	case "":
+		if identity.BirthDate == nil {
+			return identity, errors.New("Passenger type or birth date is required")
+		}
+		identity.PassengerType = passengerTypeByAge(*identity.BirthDate, travelDate)
+	case passengerTypeAdult, passengerTypeSenior, passengerTypeChild, passengerTypeInfant:
+		if identity.BirthDate == nil && (identity.PassengerType == passengerTypeChild || identity.PassengerType == passengerTypeInfant) {
+			return identity, errors.New("Birth date is required for child and infant passengers")
+		}
+		if identity.BirthDate != nil && !isPassengerTypeOfAge(identity.PassengerType, ageOn(*identity.BirthDate, travelDate)) {
+			return identity, errors.New("Birth date does not match the passenger type")
+		}
+	default:
+		return identity, errors.New("Passenger type must be adult, senior, child or infant")
+	}
+
+	if identity.IDType == travelerIDTypeNone && identity.PassengerType != passengerTypeInfant {
+		return identity, errors.New("KTP or passport is required for passengers other than infants")
+	}
+	return identity, nil
+}
+
+// normalizeTravelerIDType accepts nik as another name of ktp. Travelers without a type carry a KTP when
+// they have a number and no document otherwise.
+func normalizeTravelerIDType(idType, idNumber string) string {
+	idType = strings.ToLower(idType)
+	switch idType {
+	case "nik":
+		return travelerIDTypeKTP
+	case "":
+		if strings.TrimSpace(idNumber) == "" {
+			return travelerIDTypeNone
+		}
+		return travelerIDTypeKTP
+	}
+	return idType
+}
+
+func validateTravelerDocument(idType, idNumber string) error {
+	switch idType {
+	case travelerIDTypeKTP:
+		if !helpers.ValidateNIK(idNumber) {
+			return errors.New("NIK must be 16 digits with a valid region code and birth date")
+		}
+	case travelerIDTypePassport:
+		if !helpers.ValidatePassportNumber(idNumber) {
+			return errors.New("Passport number must be 1 or 2 letters followed by 6 or 7 digits")
+		}
+	case travelerIDTypeNone:
+		if idNumber != "" {
+			return errors.New("ID number must be empty when ID type is none")
+		}
+	default:
+		return errors.New("ID type must be ktp, passport or none")
+	}
+	return nil
+}
+
+// validateHotelGuestDocument checks the document of a hotel guest, who shows a KTP or passport at check in
+// whatever their age.
+func validateHotelGuestDocument(idType, idNumber string) error {
+	if idType == travelerIDTypeNone {
+		return errors.New("KTP or passport is required for hotel guests")
+	}
+	return validateTravelerDocument(idType, idNumber)
+}
+
+func passengerTypeByAge(birthDate, travelDate time.Time) string {
+	age := ageOn(birthDate, travelDate)
+	switch {
+	case age < infantMaxAge:
+		return passengerTypeInfant
+	case age < childMaxAge:
+		return passengerTypeChild
+	case age >= seniorMinAge:
+		return passengerTypeSenior
+	}
+	return passengerTypeAdult
+}
+
+// isPassengerTypeOfAge reports whether a traveler of age may travel as passengerType. Seniors may still
+// book as adults, they only miss the senior discount.
+func isPassengerTypeOfAge(passengerType string, age int) bool {
+	switch passengerType {
+	case passengerTypeInfant:
+		return age < infantMaxAge
+	case passengerTypeChild:
+		return age >= infantMaxAge && age < childMaxAge
+	case passengerTypeSenior:
+		return age >= seniorMinAge
+	}
+	return age >= childMaxAge
+}
+
+// ageOn returns the age in whole years on date.
+func ageOn(birthDate, date time.Time) int {
+	age := date.Year() - birthDate.Year()
+	if date.Month() < birthDate.Month() || (date.Month() == birthDate.Month() && date.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// ticketOrderTravelDate is the date of the first departure of the order, the day ages are counted on.
+func ticketOrderTravelDate(ticketOrderInput dtos.TicketOrderInput) time.Time {
+	legs := expandTicketTravelerDetailLegs(ticketOrderInput.TicketTravelerDetailDeparture)
+	if len(legs) > 0 {
+		travelDate, err := helpers.FormatStringToDate(legs[0].Date)
+		if err == nil {
+			return travelDate
+		}
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkTicketOrderTravelers checks every traveler of the order and that the quantities agree with them.
+// QuantityAdult counts the travelers who take a seat, QuantityInfant the infants, who each ride on the lap
+// of an adult or senior.
+func checkTicketOrderTravelers(ticketOrderInput dtos.TicketOrderInput) ([]travelerIdentity, error) {
+	var travelerIdentities []travelerIdentity
+	travelDate := ticketOrderTravelDate(ticketOrderInput)
+
+	seated, adults, infants := 0, 0, 0
+	for _, travelerDetail := range ticketOrderInput.TravelerDetail {
+		if travelerDetail.Title == "" || travelerDetail.FullName == "" {
+			return travelerIdentities, errors.New("Failed to create ticket order")
+		}
+		identity, err := checkTravelerIdentity(travelerDetail, travelDate)
+		if err != nil {
+			return travelerIdentities, errors.New(travelerDetail.FullName + ": " + err.Error())
+		}
+		switch identity.PassengerType {
+		case passengerTypeInfant:
+			infants++
+		case passengerTypeAdult, passengerTypeSenior:
+			adults++
+			seated++
+		default:
+			seated++
+		}
+		travelerIdentities = append(travelerIdentities, identity)
+	}
+
+	if ticketOrderInput.QuantityAdult != seated {
+		return travelerIdentities, errors.New("Quantity adult does not match the passengers who take a seat")
+	}
+	if ticketOrderInput.QuantityInfant != infants {
+		return travelerIdentities, errors.New("Quantity infant does not match the infant passengers")
+	}
+	if infants > adults {
+		return travelerIdentities, errors.New("Every infant must travel with an adult")
+	}
+	return travelerIdentities, nil
+}
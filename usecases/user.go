@@ -601,6 +601,9 @@ func (u *userUsecase) UserAdminRegister(input dtos.UserRegisterInputByAdmin) (dt
 	user.ProfilePicture = "https://icon-library.com/images/default-user-icon/default-user-icon-13.jpg"
 	user.Citizen = "Indonesia"
 	user.Role = "user"
+	if input.Role == "staff" {
+		user.Role = "staff"
+	}
 
 	isActive := false // Default value if the pointer is nil
 
@@ -687,6 +690,9 @@ func (u *userUsecase) UserAdminUpdate(id uint, input dtos.UserRegisterInputUpdat
 	user.ProfilePicture = "https://icon-library.com/images/default-user-icon/default-user-icon-13.jpg"
 	user.Citizen = "Indonesia"
 	user.Role = "user"
+	if input.Role == "staff" {
+		user.Role = "staff"
+	}
 
 	isActive := false // Default value if the pointer is nil
 